@@ -4,10 +4,20 @@ import (
 	"taskhub/config"
 	"taskhub/internal/app"
 	"taskhub/internal/desktop"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	labelrepo "taskhub/internal/domains/label/repo"
+	projectrepo "taskhub/internal/domains/project/repo"
+	sessionrepo "taskhub/internal/domains/session/repo"
 	taskrepo "taskhub/internal/domains/task/repo"
 	userrepo "taskhub/internal/domains/user/repo"
+	workflowrepo "taskhub/internal/domains/workflow/repo"
+	"taskhub/pkg/db"
+	"taskhub/pkg/db/migrate"
+	"taskhub/pkg/jwtkeys"
 	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
 	"taskhub/pkg/nats"
+	"taskhub/pkg/outbox"
 
 	"go.uber.org/fx"
 )
@@ -16,11 +26,25 @@ func main() {
 	app := fx.New(
 		config.ConfigModule,
 		logger.LoggerModule,
+		nats.NatsModule,
+		migrate.MigrateModule,
 		userrepo.UserRepositoryModule,
+		sessionrepo.RefreshTokenRepositoryModule,
+		userrepo.ActionTokenRepositoryModule,
+		userrepo.MFARepositoryModule,
+		apitokenrepo.TokenRepositoryModule,
+		mailer.MailerModule,
+		jwtkeys.KeyringModule,
 		taskrepo.TaskRepositoryModule,
+		labelrepo.LabelRepositoryModule,
+		projectrepo.ProjectRepositoryModule,
+		workflowrepo.WorkflowRepositoryModule,
 		app.AuthServiceModule,
+		db.TxModule,
+		outbox.OutboxModule,
+		app.EventPublisherModule,
 		app.TaskServiceModule,
-		nats.NatsModule,
+		app.OutboxRelayModule,
 		fx.Provide(desktop.NewApp),
 		fx.Invoke(desktop.RunDesktopApp),
 	)
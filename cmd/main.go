@@ -2,13 +2,27 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"taskhub/config"
 	"taskhub/internal/app"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	labelrepo "taskhub/internal/domains/label/repo"
+	notificationrepo "taskhub/internal/domains/notification/repo"
+	projectrepo "taskhub/internal/domains/project/repo"
+	sessionrepo "taskhub/internal/domains/session/repo"
 	taskrepo "taskhub/internal/domains/task/repo"
 	userrepo "taskhub/internal/domains/user/repo"
+	viewrepo "taskhub/internal/domains/view/repo"
+	workflowrepo "taskhub/internal/domains/workflow/repo"
 	"taskhub/internal/gateway"
+	"taskhub/pkg/db"
+	"taskhub/pkg/db/migrate"
+	"taskhub/pkg/jwtkeys"
 	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
 	"taskhub/pkg/nats"
+	"taskhub/pkg/outbox"
 
 	"go.uber.org/fx"
 )
@@ -25,16 +39,58 @@ func startApp(lc fx.Lifecycle, gw *gateway.Gateway) {
 	})
 }
 
+func runMigrate(args []string) error {
+	m, err := migrate.NewMigrator(config.NewConfig(), logger.NewLogger())
+	if err != nil {
+		return err
+	}
+
+	return migrate.Run(context.Background(), m, args, os.Stdout)
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app := fx.New(
 		config.ConfigModule,
 		logger.LoggerModule,
+		nats.NatsModule,
+		migrate.MigrateModule,
 		userrepo.UserRepositoryModule,
+		sessionrepo.RefreshTokenRepositoryModule,
+		userrepo.ActionTokenRepositoryModule,
+		userrepo.MFARepositoryModule,
+		mailer.MailerModule,
+		jwtkeys.KeyringModule,
 		taskrepo.TaskRepositoryModule,
 		app.AuthServiceModule,
+		notificationrepo.ReminderRepositoryModule,
+		notificationrepo.NotificationRepositoryModule,
+		db.TxModule,
+		outbox.OutboxModule,
+		app.EventPublisherModule,
 		app.TaskServiceModule,
+		viewrepo.ViewRepositoryModule,
+		app.ViewServiceModule,
+		labelrepo.LabelRepositoryModule,
+		app.LabelServiceModule,
+		projectrepo.ProjectRepositoryModule,
+		app.ProjectServiceModule,
+		workflowrepo.WorkflowRepositoryModule,
+		app.WorkflowServiceModule,
+		apitokenrepo.TokenRepositoryModule,
+		app.PersonalTokenServiceModule,
+		app.NotificationServiceModule,
+		app.ReminderSchedulerModule,
+		app.OutboxRelayModule,
+		app.NotificationInboxModule,
 		gateway.GatewayModule,
-		nats.NatsModule,
 		fx.Invoke(startApp),
 	)
 
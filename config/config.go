@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -21,13 +23,74 @@ type DB struct {
 	User     string
 	Password string
 	DBName   string
+	// Migrate controls schema handling on startup: "off", "check" or "auto".
+	Migrate string
+}
+
+type Reminder struct {
+	Interval   time.Duration
+	LookAhead  time.Duration
+	Thresholds []time.Duration
+}
+
+// EmbeddedNats runs a NATS server inside the process instead of dialing
+// NatsUrl, for local development without any other services.
+type EmbeddedNats struct {
+	Enabled   bool
+	JetStream bool
+	// StoreDir persists JetStream data; empty uses a temporary directory.
+	StoreDir string
+}
+
+type Outbox struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxBackoff   time.Duration
+}
+
+// Mail configures how account emails are sent. Driver is "smtp", or "log"
+// to write them to the log and, when Dir is set, to .eml files in Dir.
+type Mail struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Dir      string
+}
+
+// JWT configures signing tokens with asymmetric keys. Without a KeysDir,
+// tokens are HS256-signed with Config.JWTSecret.
+type JWT struct {
+	// KeysDir holds one PEM-encoded RSA or Ed25519 key per file, named
+	// <kid>.pem.
+	KeysDir string
+	// ActiveKeyID is the kid of the private key new tokens are signed with.
+	// The other keys only verify tokens, so tokens signed before a rotation
+	// stay valid until their key is removed or retired.
+	ActiveKeyID string
+	// LegacyHS256Until is a date (2006-01-02) or RFC 3339 time until which
+	// HS256 tokens signed with Config.JWTSecret before the switch still
+	// verify. When empty they are rejected.
+	LegacyHS256Until string
+	// KeyNotAfter retires keys, as comma-separated kid=time pairs such as
+	// 2024-01=2024-07-08. Tokens a key signed stop verifying at its time.
+	KeyNotAfter string
 }
 
 type Config struct {
-	Port         string
+	Port string
+	// BaseURL is the public URL of the web app, used for links in emails.
+	BaseURL      string
 	NatsUrl      string
+	EmbeddedNats *EmbeddedNats
 	JWTSecret    string
+	JWT          *JWT
 	DB           *DB
+	Reminder     *Reminder
+	Outbox       *Outbox
+	Mail         *Mail
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
@@ -38,16 +101,49 @@ func NewConfig() *Config {
 		panic(err)
 	}
 
+	port := os.Getenv("PORT")
 	return &Config{
-		Port:      os.Getenv("PORT"),
-		NatsUrl:   os.Getenv("NATS_URL"),
+		Port:    port,
+		BaseURL: getString("BASE_URL", "http://localhost:"+port),
+		NatsUrl: os.Getenv("NATS_URL"),
+		EmbeddedNats: &EmbeddedNats{
+			Enabled:   getBool("NATS_EMBEDDED", false),
+			JetStream: getBool("NATS_EMBEDDED_JETSTREAM", true),
+			StoreDir:  os.Getenv("NATS_STORE_DIR"),
+		},
 		JWTSecret: os.Getenv("JWT_SECRET"),
+		JWT: &JWT{
+			KeysDir:          os.Getenv("JWT_KEYS_DIR"),
+			ActiveKeyID:      os.Getenv("JWT_ACTIVE_KEY_ID"),
+			LegacyHS256Until: os.Getenv("JWT_LEGACY_HS256_UNTIL"),
+			KeyNotAfter:      os.Getenv("JWT_KEY_NOT_AFTER"),
+		},
 		DB: &DB{
 			Host:     os.Getenv("DB_HOST"),
 			Port:     os.Getenv("DB_PORT"),
 			User:     os.Getenv("DB_USER"),
 			Password: os.Getenv("DB_PASSWORD"),
 			DBName:   os.Getenv("DB_NAME"),
+			Migrate:  os.Getenv("DB_MIGRATE"),
+		},
+		Reminder: &Reminder{
+			Interval:   getDuration("REMINDER_INTERVAL", time.Minute),
+			LookAhead:  getDuration("REMINDER_LOOKAHEAD", 24*time.Hour),
+			Thresholds: getDurations("REMINDER_THRESHOLDS", []time.Duration{24 * time.Hour, time.Hour}),
+		},
+		Outbox: &Outbox{
+			PollInterval: getDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:    getInt("OUTBOX_BATCH_SIZE", 100),
+			MaxBackoff:   getDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		},
+		Mail: &Mail{
+			Driver:   getString("MAIL_DRIVER", "log"),
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     getString("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     getString("MAIL_FROM", "TaskHub <no-reply@localhost>"),
+			Dir:      os.Getenv("MAIL_DIR"),
 		},
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -55,6 +151,59 @@ func NewConfig() *Config {
 	}
 }
 
+func getString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+
+	return d
+}
+
+func getBool(key string, fallback bool) bool {
+	b, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return b
+}
+
+func getInt(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+
+	return n
+}
+
+func getDurations(key string, fallback []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return fallback
+		}
+		durations = append(durations, d)
+	}
+
+	return durations
+}
+
 func (db *DB) GetDSN() string {
 	if db == nil {
 		return ""
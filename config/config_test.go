@@ -70,3 +70,57 @@ func TestConfig_Fields(t *testing.T) {
 func TestConfigModule(t *testing.T) {
 	assert.NotNil(t, ConfigModule)
 }
+
+func TestGetDuration(t *testing.T) {
+	t.Setenv("TEST_DURATION", "90s")
+	assert.Equal(t, 90*time.Second, getDuration("TEST_DURATION", time.Minute))
+
+	t.Setenv("TEST_DURATION", "not-a-duration")
+	assert.Equal(t, time.Minute, getDuration("TEST_DURATION", time.Minute))
+
+	t.Setenv("TEST_DURATION", "")
+	assert.Equal(t, time.Minute, getDuration("TEST_DURATION", time.Minute))
+}
+
+func TestGetDurations(t *testing.T) {
+	fallback := []time.Duration{time.Hour}
+
+	t.Setenv("TEST_DURATIONS", "24h, 1h,15m")
+	assert.Equal(t, []time.Duration{24 * time.Hour, time.Hour, 15 * time.Minute}, getDurations("TEST_DURATIONS", fallback))
+
+	t.Setenv("TEST_DURATIONS", "24h,bogus")
+	assert.Equal(t, fallback, getDurations("TEST_DURATIONS", fallback))
+
+	t.Setenv("TEST_DURATIONS", "")
+	assert.Equal(t, fallback, getDurations("TEST_DURATIONS", fallback))
+}
+
+func TestGetInt(t *testing.T) {
+	t.Setenv("TEST_INT", "250")
+	assert.Equal(t, 250, getInt("TEST_INT", 100))
+
+	t.Setenv("TEST_INT", "-1")
+	assert.Equal(t, 100, getInt("TEST_INT", 100))
+
+	t.Setenv("TEST_INT", "")
+	assert.Equal(t, 100, getInt("TEST_INT", 100))
+}
+
+func TestGetString(t *testing.T) {
+	t.Setenv("TEST_STRING", "value")
+	assert.Equal(t, "value", getString("TEST_STRING", "fallback"))
+
+	t.Setenv("TEST_STRING", "")
+	assert.Equal(t, "fallback", getString("TEST_STRING", "fallback"))
+}
+
+func TestGetBool(t *testing.T) {
+	t.Setenv("TEST_BOOL", "true")
+	assert.True(t, getBool("TEST_BOOL", false))
+
+	t.Setenv("TEST_BOOL", "0")
+	assert.False(t, getBool("TEST_BOOL", true))
+
+	t.Setenv("TEST_BOOL", "maybe")
+	assert.True(t, getBool("TEST_BOOL", true))
+}
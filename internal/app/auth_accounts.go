@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"taskhub/internal/domains/user"
+	"taskhub/pkg/mailer"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidActionToken is returned for a password reset or verification
+// token that is unknown, expired or already used.
+var ErrInvalidActionToken = errors.New("invalid or expired token")
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+	// passwordResetMailTimeout bounds sending a reset link, which happens
+	// after ForgotPassword has returned.
+	passwordResetMailTimeout = 30 * time.Second
+)
+
+// newActionToken returns a random token to mail and the hash to store.
+func newActionToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashActionToken(token), nil
+}
+
+func hashActionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueActionToken records a token for purpose that replaces any the user
+// already has, and returns the link to the web page at path that uses it.
+func (s *AuthService) issueActionToken(ctx context.Context, u *user.User, purpose user.TokenPurpose, ttl time.Duration, path string) (string, error) {
+	token, hash, err := newActionToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.actionTokenRepo.InvalidateByUser(ctx, u.Id, purpose, now); err != nil {
+		return "", err
+	}
+	err = s.actionTokenRepo.Create(ctx, &user.ActionToken{
+		Id:        uuid.New(),
+		UserID:    u.Id,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.config.BaseURL + path + "?token=" + url.QueryEscape(token), nil
+}
+
+// consumeActionToken uses up token and returns its user.
+func (s *AuthService) consumeActionToken(ctx context.Context, token string, purpose user.TokenPurpose) (*user.User, error) {
+	if token == "" {
+		return nil, ErrInvalidActionToken
+	}
+
+	t, err := s.actionTokenRepo.Consume(ctx, hashActionToken(token), purpose, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrInvalidActionToken
+	}
+
+	u, err := s.userRepo.FindById(ctx, t.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrInvalidActionToken
+	}
+
+	return u, nil
+}
+
+func (s *AuthService) sendEmailVerification(ctx context.Context, u *user.User) error {
+	link, err := s.issueActionToken(ctx, u, user.PurposeEmailVerification, emailVerificationTTL, "/verify-email")
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your TaskHub email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm this is your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours.\n",
+			u.Name, link, int(emailVerificationTTL.Hours())),
+	})
+}
+
+// ResendEmailVerification mails the user a new verification link, which
+// replaces any sent before. It does nothing for a verified user.
+func (s *AuthService) ResendEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.userRepo.FindById(ctx, userID.String())
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrInvalidToken
+	}
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.sendEmailVerification(ctx, u)
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type VerifyEmailResponse struct {
+	User *user.User `json:"user"`
+}
+
+// VerifyEmail marks the email address of the user a verification token was
+// mailed to as verified.
+func (s *AuthService) VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*VerifyEmailResponse, error) {
+	u, err := s.consumeActionToken(ctx, req.Token, user.PurposeEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.EmailVerifiedAt == nil {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+		u.UpdateAt = &now
+		if _, err := s.userRepo.Update(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	u.Password = ""
+	return &VerifyEmailResponse{User: u}, nil
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ForgotPassword mails a password reset link to the user with the email
+// address, replacing any sent before. The lookup and the mail happen in the
+// background and failures are only logged, so callers cannot tell from the
+// result or its timing whether there is such a user.
+func (s *AuthService) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) {
+	email := req.Email
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetMailTimeout)
+		defer cancel()
+		if err := s.sendPasswordReset(ctx, email); err != nil {
+			s.logger.Error("failed to send password reset email", "error", err)
+		}
+	}()
+}
+
+func (s *AuthService) sendPasswordReset(ctx context.Context, email string) error {
+	u, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return nil
+	}
+
+	link, err := s.issueActionToken(ctx, u, user.PurposePasswordReset, passwordResetTTL, "/reset-password")
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your TaskHub password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset your TaskHub password. Choose a new one by opening the link below:\n\n%s\n\nThe link expires in %d minutes. If you did not ask for this, ignore this email.\n",
+			u.Name, link, int(passwordResetTTL.Minutes())),
+	})
+}
+
+// ResetPassword sets a new password for the user a reset token was mailed
+// to. Since the token proves they own their email address, it also verifies
+// it. All of the user's sessions are ended.
+func (s *AuthService) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	u, err := s.consumeActionToken(ctx, req.Token, user.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	u.Password = string(hashedPassword)
+	u.UpdateAt = &now
+	if u.EmailVerifiedAt == nil {
+		u.EmailVerifiedAt = &now
+	}
+	if _, err := s.userRepo.Update(ctx, u); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password may have created tokens with it.
+	if err := s.apiTokenRepo.DeleteByUserId(ctx, u.Id); err != nil {
+		return err
+	}
+
+	return s.LogoutEverywhere(ctx, u.Id)
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"taskhub/internal/domains/apitoken"
+	userrepo "taskhub/internal/domains/user/repo"
+	"taskhub/pkg/mailer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkToken returns the token in the link of msg.
+func linkToken(t *testing.T, msg *mailer.Message) string {
+	t.Helper()
+
+	_, rest, found := strings.Cut(msg.Body, "?token=")
+	require.True(t, found)
+	token, _, _ := strings.Cut(rest, "\n")
+	token, err := url.QueryUnescape(token)
+	require.NoError(t, err)
+	return token
+}
+
+func newTestAccountService(t *testing.T) (*AuthService, *mailer.MemoryMailer) {
+	t.Helper()
+
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+	service.config.BaseURL = "https://taskhub.example.com"
+	mail := service.mailer.(*mailer.MemoryMailer)
+
+	_, err := service.Register(context.Background(), &RegisterRequest{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	return service, mail
+}
+
+func TestAuthService_RegisterSendsVerification(t *testing.T) {
+	ctx := context.Background()
+	service, mail := newTestAccountService(t)
+
+	messages := mail.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "test@example.com", messages[0].To)
+	assert.Contains(t, messages[0].Body, "https://taskhub.example.com/verify-email?token=")
+
+	resp, err := service.VerifyEmail(ctx, &VerifyEmailRequest{Token: linkToken(t, messages[0])})
+	require.NoError(t, err)
+	assert.NotNil(t, resp.User.EmailVerifiedAt)
+
+	_, err = service.VerifyEmail(ctx, &VerifyEmailRequest{Token: linkToken(t, messages[0])})
+	assert.Equal(t, ErrInvalidActionToken, err)
+
+	require.NoError(t, service.ResendEmailVerification(ctx, resp.User.Id))
+	assert.Len(t, mail.Messages(), 1, "verified users get no new link")
+}
+
+func TestAuthService_ForgotPasswordUnknownEmail(t *testing.T) {
+	service, mail := newTestAccountService(t)
+
+	service.ForgotPassword(context.Background(), &ForgotPasswordRequest{Email: "nobody@example.com"})
+	service.background.Wait()
+	assert.Len(t, mail.Messages(), 1)
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	service, mail := newTestAccountService(t)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	tokens := NewPersonalTokenService(service.logger, service.apiTokenRepo)
+	pat, err := tokens.CreateToken(ctx, &CreatePersonalTokenRequest{Name: "CI", Scopes: []apitoken.Scope{apitoken.ScopeTasksRead}}, login.User.Id)
+	require.NoError(t, err)
+
+	service.ForgotPassword(ctx, &ForgotPasswordRequest{Email: "test@example.com"})
+	service.background.Wait()
+	stale := linkToken(t, mail.Messages()[1])
+	service.ForgotPassword(ctx, &ForgotPasswordRequest{Email: "test@example.com"})
+	service.background.Wait()
+	token := linkToken(t, mail.Messages()[2])
+
+	err = service.ResetPassword(ctx, &ResetPasswordRequest{Token: stale, Password: "new-password"})
+	assert.Equal(t, ErrInvalidActionToken, err, "a newer link replaces older ones")
+
+	require.NoError(t, service.ResetPassword(ctx, &ResetPasswordRequest{Token: token, Password: "new-password"}))
+	err = service.ResetPassword(ctx, &ResetPasswordRequest{Token: token, Password: "other-password"})
+	assert.Equal(t, ErrInvalidActionToken, err)
+
+	_, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	assert.Equal(t, ErrInvalidCredentials, err)
+	relogin, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "new-password"})
+	require.NoError(t, err)
+	assert.NotNil(t, relogin.User.EmailVerifiedAt)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	assert.Equal(t, ErrInvalidToken, err, "resetting ends existing sessions")
+	_, err = tokens.Authenticate(ctx, pat.Secret)
+	assert.Equal(t, ErrInvalidToken, err, "resetting revokes personal access tokens")
+}
@@ -0,0 +1,384 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"taskhub/internal/domains/user"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMFACode     = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled     = errors.New("two-factor authentication is not set up")
+	ErrTooManyMFAAttempts = errors.New("too many authentication attempts, try again later")
+)
+
+const (
+	// mfaAudience marks MFA challenge tokens, which must not pass as access
+	// tokens.
+	mfaAudience       = "taskhub:mfa"
+	mfaChallengeTTL   = 5 * time.Minute
+	mfaIssuer         = "TaskHub"
+	recoveryCodeCount = 10
+	recoveryCodeHalf  = 5
+
+	// maxMFAChallengeAttempts is how many codes an MFA token is checked
+	// against before the login has to start over.
+	maxMFAChallengeAttempts = 5
+	// maxMFAUserAttempts caps the codes tried across all of a user's MFA
+	// tokens within mfaAttemptWindow, so that new logins do not buy more
+	// guesses.
+	maxMFAUserAttempts = 10
+	mfaAttemptWindow   = 15 * time.Minute
+)
+
+var recoveryCodeAlphabet = []byte("abcdefghijkmnpqrstuvwxyz23456789")
+
+// MFAClaims are the claims of an MFA challenge token, which proves the
+// password check of a login that still needs a second factor.
+type MFAClaims struct {
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	// Code is a code from the authenticator app or a recovery code.
+	Code   string `json:"code"`
+	Device string `json:"-"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type MFAStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// MFAEnrollmentResponse holds what an authenticator app needs. URI is the
+// otpauth:// URI to show as a QR code; Secret is for typing in by hand.
+type MFAEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodesResponse holds recovery codes. They are only ever shown
+// once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// mfaChallenge records a challenge for u and returns its MFA token.
+func (s *AuthService) mfaChallenge(ctx context.Context, u *user.User) (*LoginResponse, error) {
+	now := time.Now()
+	challenge := &user.MFAChallenge{
+		Id:        uuid.New(),
+		UserID:    u.Id,
+		ExpiresAt: now.Add(mfaChallengeTTL),
+		CreatedAt: now,
+	}
+	if err := s.mfaRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	claims := &MFAClaims{
+		UserID: u.Id.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        challenge.Id.String(),
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			ExpiresAt: jwt.NewNumericDate(challenge.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "taskhub",
+		},
+	}
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{MFARequired: true, MFAToken: token}, nil
+}
+
+// LoginMFA completes a login begun by Login with the MFA token it returned
+// and a second factor. It fails with ErrInvalidToken for a bad, expired or
+// used MFA token, or one that has had maxMFAChallengeAttempts codes, with
+// ErrTooManyMFAAttempts when the user has tried too many codes lately and
+// with ErrInvalidMFACode for a wrong code.
+func (s *AuthService) LoginMFA(ctx context.Context, req *LoginMFARequest) (*LoginResponse, error) {
+	token, err := jwt.ParseWithClaims(req.MFAToken, &MFAClaims{}, s.keys.Keyfunc, jwt.WithAudience(mfaAudience))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*MFAClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	challengeID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	u, err := s.userRepo.FindById(ctx, claims.UserID)
+	if err != nil || u == nil {
+		return nil, ErrInvalidToken
+	}
+
+	mfa, err := s.mfaRepo.FindByUserId(ctx, u.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled() {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	attempts, err := s.mfaRepo.CountChallengeAttempts(ctx, u.Id, now.Add(-mfaAttemptWindow))
+	if err != nil {
+		return nil, err
+	}
+	if attempts >= maxMFAUserAttempts {
+		return nil, ErrTooManyMFAAttempts
+	}
+
+	// The attempt is counted before the code is checked, so that
+	// concurrent guesses cannot get past the limit.
+	allowed, err := s.mfaRepo.AttemptChallenge(ctx, challengeID, maxMFAChallengeAttempts, now)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrInvalidToken
+	}
+
+	if err := s.verifySecondFactor(ctx, mfa, req.Code, true); err != nil {
+		return nil, err
+	}
+
+	consumed, err := s.mfaRepo.ConsumeChallenge(ctx, challengeID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidToken
+	}
+
+	tokens, err := s.GenerateTokenPair(ctx, u, req.Device)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Password = ""
+	return &LoginResponse{User: u, Tokens: tokens}, nil
+}
+
+// verifySecondFactor checks code against the user's authenticator app or,
+// when allowRecovery is set, their unused recovery codes, using it up.
+func (s *AuthService) verifySecondFactor(ctx context.Context, mfa *user.MFA, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+
+	if counter, ok := user.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		used, err := s.mfaRepo.UseCounter(ctx, mfa.UserID, counter)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return ErrInvalidMFACode
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, hashRecoveryCode(code), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// newRecoveryCodes returns fresh recovery codes and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 2*recoveryCodeHalf)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[int(b[j])%len(recoveryCodeAlphabet)]
+		}
+
+		codes[i] = string(b[:recoveryCodeHalf]) + "-" + string(b[recoveryCodeHalf:])
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes code, ignoring case and separators.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return hashActionToken(code)
+}
+
+func (s *AuthService) MFAStatus(ctx context.Context, userID uuid.UUID) (*MFAStatusResponse, error) {
+	mfa, err := s.mfaRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled() {
+		return &MFAStatusResponse{}, nil
+	}
+
+	left, err := s.mfaRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAStatusResponse{Enabled: true, RecoveryCodesLeft: left}, nil
+}
+
+// EnrollMFA starts setting up two-factor authentication with a new secret,
+// replacing an enrollment that was never confirmed. It takes effect once
+// ConfirmMFA sees a code from it.
+func (s *AuthService) EnrollMFA(ctx context.Context, userID uuid.UUID) (*MFAEnrollmentResponse, error) {
+	u, err := s.userRepo.FindById(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrInvalidToken
+	}
+
+	existing, err := s.mfaRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := user.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = s.mfaRepo.Save(ctx, &user.MFA{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollmentResponse{
+		Secret: secret,
+		URI:    user.TOTPURI(mfaIssuer, u.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA turns two-factor authentication on once the user proves their
+// authenticator app works, and returns their recovery codes.
+func (s *AuthService) ConfirmMFA(ctx context.Context, userID uuid.UUID, req *MFACodeRequest) (*RecoveryCodesResponse, error) {
+	mfa, err := s.mfaRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ErrMFANotEnrolled
+	}
+	if mfa.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	counter, ok := user.ValidateTOTP(mfa.Secret, strings.TrimSpace(req.Code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mfa.ConfirmedAt = &now
+	mfa.LastCounter = counter
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, given a code
+// from their authenticator app.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *MFACodeRequest) (*RecoveryCodesResponse, error) {
+	mfa, err := s.enabledMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifySecondFactor(ctx, mfa, req.Code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns two-factor authentication off, given a code from the
+// user's authenticator app or a recovery code.
+func (s *AuthService) DisableMFA(ctx context.Context, userID uuid.UUID, req *MFACodeRequest) error {
+	mfa, err := s.enabledMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(ctx, mfa, req.Code, true); err != nil {
+		return err
+	}
+
+	return s.mfaRepo.Delete(ctx, userID)
+}
+
+func (s *AuthService) enabledMFA(ctx context.Context, userID uuid.UUID) (*user.MFA, error) {
+	mfa, err := s.mfaRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.Enabled() {
+		return nil, ErrMFANotEnrolled
+	}
+
+	return mfa, nil
+}
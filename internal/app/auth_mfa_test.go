@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/user"
+	userrepo "taskhub/internal/domains/user/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enrollTestMFA registers a user and turns two-factor authentication on for
+// them, returning their id, TOTP secret and recovery codes.
+func enrollTestMFA(t *testing.T, service *AuthService) (uuid.UUID, string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	registered, err := service.Register(ctx, &RegisterRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	userID := registered.User.Id
+
+	enrollment, err := service.EnrollMFA(ctx, userID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/TaskHub:test@example.com?")
+
+	_, err = service.ConfirmMFA(ctx, userID, &MFACodeRequest{Code: "000000"})
+	assert.Equal(t, ErrInvalidMFACode, err)
+
+	code, err := user.TOTPCode(enrollment.Secret, user.TOTPCounter(time.Now()))
+	require.NoError(t, err)
+	codes, err := service.ConfirmMFA(ctx, userID, &MFACodeRequest{Code: code})
+	require.NoError(t, err)
+	assert.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+
+	return userID, enrollment.Secret, codes.RecoveryCodes
+}
+
+// nextTOTPCode returns a code valid now that enrollment has not used yet.
+func nextTOTPCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	code, err := user.TOTPCode(secret, user.TOTPCounter(time.Now())+step)
+	require.NoError(t, err)
+	return code
+}
+
+func TestAuthService_LoginWithMFA(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+	_, secret, _ := enrollTestMFA(t, service)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.True(t, login.MFARequired)
+	assert.Nil(t, login.Tokens)
+
+	_, err = service.ValidateAccessToken(login.MFAToken)
+	assert.Equal(t, ErrInvalidToken, err, "challenge tokens are not access tokens")
+
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: "000000"})
+	assert.Equal(t, ErrInvalidMFACode, err)
+
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: "invalid", Code: nextTOTPCode(t, secret, 1)})
+	assert.Equal(t, ErrInvalidToken, err)
+
+	code := nextTOTPCode(t, secret, 1)
+	done, err := service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: code})
+	require.NoError(t, err)
+	assert.NotEmpty(t, done.Tokens.AccessToken)
+	assert.Empty(t, done.User.Password)
+
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: nextTOTPCode(t, secret, 2)})
+	assert.Equal(t, ErrInvalidToken, err, "challenge tokens are used once")
+
+	login, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: code})
+	assert.Equal(t, ErrInvalidMFACode, err, "codes are accepted once")
+}
+
+func TestAuthService_LoginMFAAttemptLimits(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+	_, secret, _ := enrollTestMFA(t, service)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	for i := 0; i < maxMFAChallengeAttempts; i++ {
+		_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: "000000"})
+		assert.Equal(t, ErrInvalidMFACode, err)
+	}
+
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: nextTOTPCode(t, secret, 1)})
+	assert.Equal(t, ErrInvalidToken, err, "a challenge is dead after too many wrong codes")
+
+	login, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	for i := 0; i < maxMFAUserAttempts-maxMFAChallengeAttempts; i++ {
+		_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: "000000"})
+		assert.Equal(t, ErrInvalidMFACode, err)
+	}
+
+	login, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: nextTOTPCode(t, secret, 1)})
+	assert.Equal(t, ErrTooManyMFAAttempts, err, "new challenges do not reset the user's limit")
+}
+
+func TestAuthService_LoginWithRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+	userID, _, codes := enrollTestMFA(t, service)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: " " + codes[0] + " "})
+	require.NoError(t, err)
+
+	login, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	_, err = service.LoginMFA(ctx, &LoginMFARequest{MFAToken: login.MFAToken, Code: codes[0]})
+	assert.Equal(t, ErrInvalidMFACode, err)
+
+	status, err := service.MFAStatus(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesLeft)
+}
+
+func TestAuthService_ManageMFA(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+	userID, secret, codes := enrollTestMFA(t, service)
+
+	_, err := service.EnrollMFA(ctx, userID)
+	assert.Equal(t, ErrMFAAlreadyEnabled, err)
+
+	_, err = service.RegenerateRecoveryCodes(ctx, userID, &MFACodeRequest{Code: codes[0]})
+	assert.Equal(t, ErrInvalidMFACode, err, "regenerating needs the authenticator")
+	fresh, err := service.RegenerateRecoveryCodes(ctx, userID, &MFACodeRequest{Code: nextTOTPCode(t, secret, 1)})
+	require.NoError(t, err)
+
+	assert.Equal(t, ErrInvalidMFACode, service.DisableMFA(ctx, userID, &MFACodeRequest{Code: codes[1]}), "old codes are replaced")
+	require.NoError(t, service.DisableMFA(ctx, userID, &MFACodeRequest{Code: fresh.RecoveryCodes[0]}))
+
+	status, err := service.MFAStatus(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, status.Enabled)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.False(t, login.MFARequired)
+	assert.NotNil(t, login.Tokens)
+
+	_, err = service.ConfirmMFA(ctx, userID, &MFACodeRequest{Code: "123456"})
+	assert.Equal(t, ErrMFANotEnrolled, err)
+}
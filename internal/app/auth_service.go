@@ -3,14 +3,21 @@ package app
 import (
 	"context"
 	"errors"
+	"slices"
+	"sync"
 	"taskhub/config"
+	"taskhub/internal/domains/apitoken"
+	"taskhub/internal/domains/session"
 	"taskhub/internal/domains/user"
-	"taskhub/internal/domains/user/repo"
 	"taskhub/pkg/base/entity"
+	"taskhub/pkg/jwtkeys"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
 	"taskhub/pkg/utils"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/fx"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -25,6 +32,24 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrTokenExpired       = errors.New("token expired")
+	// ErrTokenReused is returned when a refresh token is presented again
+	// after it was rotated. Its whole family is revoked, since either the
+	// client or an attacker holds a stolen copy.
+	ErrTokenReused = errors.New("refresh token reused")
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+	// refreshReuseGrace lets a rotated refresh token be presented again just
+	// after rotation without counting as reuse, as happens when a browser
+	// sends several requests with an expired access cookie at once.
+	refreshReuseGrace = 10 * time.Second
+	// maxDeviceLength matches the refresh_tokens.device column.
+	maxDeviceLength = 255
+	// refreshAudience marks refresh tokens, which must not pass as access
+	// tokens. Access tokens have no audience.
+	refreshAudience = "taskhub:refresh"
 )
 
 type Claims struct {
@@ -33,6 +58,8 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// RefreshClaims are the claims of a refresh token. Its ID (jti) keys the
+// token's server-side record.
 type RefreshClaims struct {
 	UserID string `json:"user_id"`
 	jwt.RegisteredClaims
@@ -45,14 +72,34 @@ type TokenPair struct {
 }
 
 type AuthService struct {
-	config   *config.Config
-	userRepo *repo.UserRepository
+	config          *config.Config
+	logger          *logger.Logger
+	keys            *jwtkeys.Keyring
+	userRepo        user.UserStore
+	tokenRepo       session.RefreshTokenStore
+	actionTokenRepo user.ActionTokenStore
+	mfaRepo         user.MFAStore
+	apiTokenRepo    apitoken.TokenStore
+	mailer          mailer.Mailer
+	// reuseGrace is how long after rotation a refresh token may still be
+	// presented; see refreshReuseGrace.
+	reuseGrace time.Duration
+	// background tracks password reset mails still being sent.
+	background sync.WaitGroup
 }
 
-func NewAuthService(config *config.Config, userRepo *repo.UserRepository) *AuthService {
+func NewAuthService(config *config.Config, logger *logger.Logger, keys *jwtkeys.Keyring, userRepo user.UserStore, tokenRepo session.RefreshTokenStore, actionTokenRepo user.ActionTokenStore, mfaRepo user.MFAStore, apiTokenRepo apitoken.TokenStore, mailer mailer.Mailer) *AuthService {
 	return &AuthService{
-		config:   config,
-		userRepo: userRepo,
+		config:          config,
+		logger:          logger,
+		keys:            keys,
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		actionTokenRepo: actionTokenRepo,
+		mfaRepo:         mfaRepo,
+		apiTokenRepo:    apiTokenRepo,
+		mailer:          mailer,
+		reuseGrace:      refreshReuseGrace,
 	}
 }
 
@@ -66,6 +113,8 @@ type RegisterResponse struct {
 	User *user.User `json:"user"`
 }
 
+// Register creates an account and mails the user a link to verify their
+// email address.
 func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
 	existingUser, _ := s.userRepo.FindByEmail(ctx, req.Email)
 	if existingUser != nil {
@@ -92,6 +141,12 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Regi
 		return nil, err
 	}
 
+	// The account is usable without verification, so a mail failure must
+	// not fail registration; the user can ask for another email.
+	if err := s.sendEmailVerification(ctx, createdUser); err != nil {
+		s.logger.Error("Failed to send verification email", "user_id", createdUser.Id, "error", err)
+	}
+
 	createdUser.Password = ""
 
 	return &RegisterResponse{User: createdUser}, nil
@@ -100,13 +155,23 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Regi
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// Device names the client the session is for, such as its user agent.
+	Device string `json:"-"`
 }
 
+// LoginResponse holds the user and their tokens, or, when the user has
+// two-factor authentication on, only an MFA token to pass to LoginMFA along
+// with a code.
 type LoginResponse struct {
-	User   *user.User `json:"user"`
-	Tokens *TokenPair `json:"tokens"`
+	User        *user.User `json:"user,omitempty"`
+	Tokens      *TokenPair `json:"tokens,omitempty"`
+	MFARequired bool       `json:"mfa_required,omitempty"`
+	MFAToken    string     `json:"mfa_token,omitempty"`
 }
 
+// Login checks the user's password and issues their tokens. When the user
+// has two-factor authentication on, it returns an MFA challenge instead;
+// LoginMFA completes it.
 func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
 	existingUser, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil || existingUser == nil {
@@ -117,7 +182,15 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 		return nil, ErrInvalidCredentials
 	}
 
-	tokens, err := s.GenerateTokenPair(existingUser)
+	mfa, err := s.mfaRepo.FindByUserId(ctx, existingUser.Id)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled() {
+		return s.mfaChallenge(ctx, existingUser)
+	}
+
+	tokens, err := s.GenerateTokenPair(ctx, existingUser, req.Device)
 	if err != nil {
 		return nil, err
 	}
@@ -130,40 +203,63 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 	}, nil
 }
 
-func (s *AuthService) GenerateTokenPair(u *user.User) (*TokenPair, error) {
-	accessExpiry := time.Now().Add(15 * time.Minute)
+// GenerateTokenPair issues tokens for a new session of u on device, starting
+// a refresh token family.
+func (s *AuthService) GenerateTokenPair(ctx context.Context, u *user.User, device string) (*TokenPair, error) {
+	return s.issueTokenPair(ctx, u, uuid.New(), device)
+}
+
+// issueTokenPair issues an access token and a refresh token in familyID,
+// recording the refresh token.
+func (s *AuthService) issueTokenPair(ctx context.Context, u *user.User, familyID uuid.UUID, device string) (*TokenPair, error) {
+	now := time.Now()
+	accessExpiry := now.Add(accessTokenTTL)
 	accessClaims := &Claims{
 		UserID: u.Id.String(),
 		Email:  u.Email,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessExpiry),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "taskhub",
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(s.config.JWTSecret))
+	accessTokenString, err := s.keys.Sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshExpiry := time.Now().Add(7 * 24 * time.Hour)
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+	record := &session.RefreshToken{
+		Id:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    u.Id,
+		Device:    device,
+		ExpiresAt: now.Add(refreshTokenTTL),
+		CreatedAt: now,
+	}
 	refreshClaims := &RefreshClaims{
 		UserID: u.Id.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(refreshExpiry),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        record.Id.String(),
+			Audience:  jwt.ClaimStrings{refreshAudience},
+			ExpiresAt: jwt.NewNumericDate(record.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "taskhub",
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.config.JWTSecret))
+	refreshTokenString, err := s.keys.Sign(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.tokenRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
 	return &TokenPair{
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
@@ -172,13 +268,7 @@ func (s *AuthService) GenerateTokenPair(u *user.User) (*TokenPair, error) {
 }
 
 func (s *AuthService) ValidateAccessToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.config.JWTSecret), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.keys.Keyfunc)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrTokenExpired
@@ -187,41 +277,118 @@ func (s *AuthService) ValidateAccessToken(tokenString string) (*Claims, error) {
 	}
 
 	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || slices.Contains(claims.Audience, mfaAudience) || slices.Contains(claims.Audience, refreshAudience) {
 		return nil, ErrInvalidToken
 	}
 
 	return claims, nil
 }
 
+// JWKS returns the public keys that verify TaskHub's tokens, for
+// /.well-known/jwks.json.
+func (s *AuthService) JWKS() *jwtkeys.JWKSet {
+	return s.keys.JWKS()
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-func (s *AuthService) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*TokenPair, error) {
-	token, err := jwt.ParseWithClaims(req.RefreshToken, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.config.JWTSecret), nil
-	})
-
+// parseRefreshToken verifies a refresh token's signature and claims and
+// returns its jti.
+func (s *AuthService) parseRefreshToken(tokenString string, opts ...jwt.ParserOption) (*RefreshClaims, uuid.UUID, error) {
+	opts = append(opts, jwt.WithAudience(refreshAudience))
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, s.keys.Keyfunc, opts...)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrTokenExpired
+			return nil, uuid.Nil, ErrTokenExpired
 		}
-		return nil, ErrInvalidToken
+		return nil, uuid.Nil, ErrInvalidToken
 	}
 
+	// WithoutClaimsValidation skips the audience check too, so it is
+	// repeated here.
 	claims, ok := token.Claims.(*RefreshClaims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || !slices.Contains(claims.Audience, refreshAudience) {
+		return nil, uuid.Nil, ErrInvalidToken
+	}
+
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, uuid.Nil, ErrInvalidToken
+	}
+
+	return claims, jti, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair in the same
+// family. The presented token is rotated out: presenting it again fails with
+// ErrTokenReused and revokes the family. Revoked tokens fail with
+// ErrInvalidToken.
+func (s *AuthService) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*TokenPair, error) {
+	claims, jti, err := s.parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.tokenRepo.FindById(ctx, jti)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.RevokedAt != nil || stored.UserID.String() != claims.UserID {
 		return nil, ErrInvalidToken
 	}
 
+	now := time.Now()
+	rotated, err := s.tokenRepo.MarkRotated(ctx, jti, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Someone else rotated the token first; reload it to see when.
+		stored, err = s.tokenRepo.FindById(ctx, jti)
+		if err != nil {
+			return nil, err
+		}
+		if stored.RevokedAt != nil {
+			return nil, ErrInvalidToken
+		}
+		if stored.RotatedAt == nil || now.Sub(*stored.RotatedAt) > s.reuseGrace {
+			if err := s.tokenRepo.RevokeFamily(ctx, stored.FamilyID, now); err != nil {
+				return nil, err
+			}
+			return nil, ErrTokenReused
+		}
+	}
+
 	u, err := s.userRepo.FindById(ctx, claims.UserID)
 	if err != nil || u == nil {
 		return nil, ErrInvalidToken
 	}
 
-	return s.GenerateTokenPair(u)
+	return s.issueTokenPair(ctx, u, stored.FamilyID, stored.Device)
+}
+
+// Logout revokes the family of a refresh token, ending the session it
+// belongs to. Expired tokens are accepted; tokens with a bad signature fail
+// with ErrInvalidToken. Access tokens already issued stay valid until they
+// expire.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	_, jti, err := s.parseRefreshToken(refreshToken, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.tokenRepo.FindById(ctx, jti)
+	if err != nil || stored == nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeFamily(ctx, stored.FamilyID, time.Now())
+}
+
+// LogoutEverywhere revokes every refresh token of the user, ending all of
+// their sessions.
+func (s *AuthService) LogoutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	return s.tokenRepo.RevokeByUser(ctx, userID, time.Now())
 }
@@ -2,15 +2,28 @@ package app
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"taskhub/config"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	sessionrepo "taskhub/internal/domains/session/repo"
 	"taskhub/internal/domains/user"
+	userrepo "taskhub/internal/domains/user/repo"
 	"taskhub/pkg/base/entity"
+	"taskhub/pkg/jwtkeys"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -51,9 +64,13 @@ func newTestConfig() *config.Config {
 	}
 }
 
+func newTestAuthService(cfg *config.Config, users user.UserStore) *AuthService {
+	return NewAuthService(cfg, logger.NewLogger(), jwtkeys.NewHMACKeyring(cfg.JWTSecret), users, sessionrepo.NewMemoryRefreshTokenRepository(), userrepo.NewMemoryActionTokenRepository(), userrepo.NewMemoryMFARepository(), apitokenrepo.NewMemoryTokenRepository(), mailer.NewMemoryMailer())
+}
+
 func TestGenerateTokenPair(t *testing.T) {
 	cfg := newTestConfig()
-	service := &AuthService{config: cfg}
+	service := newTestAuthService(cfg, nil)
 
 	testUser := &user.User{
 		BaseEntity: entity.BaseEntity{
@@ -62,7 +79,7 @@ func TestGenerateTokenPair(t *testing.T) {
 		Email: "test@example.com",
 	}
 
-	tokens, err := service.GenerateTokenPair(testUser)
+	tokens, err := service.GenerateTokenPair(context.Background(), testUser, "")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, tokens)
@@ -73,7 +90,7 @@ func TestGenerateTokenPair(t *testing.T) {
 
 func TestValidateAccessToken_Success(t *testing.T) {
 	cfg := newTestConfig()
-	service := &AuthService{config: cfg}
+	service := newTestAuthService(cfg, nil)
 
 	testUser := &user.User{
 		BaseEntity: entity.BaseEntity{
@@ -82,7 +99,7 @@ func TestValidateAccessToken_Success(t *testing.T) {
 		Email: "test@example.com",
 	}
 
-	tokens, _ := service.GenerateTokenPair(testUser)
+	tokens, _ := service.GenerateTokenPair(context.Background(), testUser, "")
 	claims, err := service.ValidateAccessToken(tokens.AccessToken)
 
 	assert.NoError(t, err)
@@ -93,7 +110,7 @@ func TestValidateAccessToken_Success(t *testing.T) {
 
 func TestValidateAccessToken_InvalidToken(t *testing.T) {
 	cfg := newTestConfig()
-	service := &AuthService{config: cfg}
+	service := newTestAuthService(cfg, nil)
 
 	claims, err := service.ValidateAccessToken("invalid-token")
 
@@ -104,7 +121,7 @@ func TestValidateAccessToken_InvalidToken(t *testing.T) {
 
 func TestValidateAccessToken_WrongSecret(t *testing.T) {
 	cfg := newTestConfig()
-	service := &AuthService{config: cfg}
+	service := newTestAuthService(cfg, nil)
 
 	testUser := &user.User{
 		BaseEntity: entity.BaseEntity{
@@ -113,10 +130,10 @@ func TestValidateAccessToken_WrongSecret(t *testing.T) {
 		Email: "test@example.com",
 	}
 
-	tokens, _ := service.GenerateTokenPair(testUser)
+	tokens, _ := service.GenerateTokenPair(context.Background(), testUser, "")
 
 	wrongCfg := &config.Config{JWTSecret: "wrong-secret"}
-	wrongService := &AuthService{config: wrongCfg}
+	wrongService := newTestAuthService(wrongCfg, nil)
 
 	claims, err := wrongService.ValidateAccessToken(tokens.AccessToken)
 
@@ -124,6 +141,57 @@ func TestValidateAccessToken_WrongSecret(t *testing.T) {
 	assert.Nil(t, claims)
 }
 
+func TestValidateAccessToken_RejectsRefreshToken(t *testing.T) {
+	service := newTestAuthService(newTestConfig(), nil)
+
+	testUser := &user.User{BaseEntity: entity.BaseEntity{Id: uuid.New()}, Email: "test@example.com"}
+	tokens, err := service.GenerateTokenPair(context.Background(), testUser, "")
+	require.NoError(t, err)
+
+	_, err = service.ValidateAccessToken(tokens.RefreshToken)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestAuthService_AsymmetricKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "k1.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	cfg := newTestConfig()
+	cfg.JWT = &config.JWT{KeysDir: dir, ActiveKeyID: "k1", LegacyHS256Until: time.Now().Add(time.Hour).Format(time.RFC3339)}
+	keys, err := jwtkeys.NewKeyring(cfg)
+	require.NoError(t, err)
+	users := userrepo.NewMemoryUserRepository()
+	service := NewAuthService(cfg, logger.NewLogger(), keys, users, sessionrepo.NewMemoryRefreshTokenRepository(), userrepo.NewMemoryActionTokenRepository(), userrepo.NewMemoryMFARepository(), apitokenrepo.NewMemoryTokenRepository(), mailer.NewMemoryMailer())
+
+	testUser := &user.User{BaseEntity: entity.BaseEntity{Id: uuid.New()}, Email: "test@example.com"}
+	_, err = users.Create(ctx, testUser)
+	require.NoError(t, err)
+	tokens, err := service.GenerateTokenPair(ctx, testUser, "")
+	require.NoError(t, err)
+
+	claims, err := service.ValidateAccessToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, testUser.Id.String(), claims.UserID)
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	assert.NoError(t, err)
+
+	// Tokens signed with the shared secret from before the switch still
+	// verify until the legacy cutoff.
+	legacy, err := newTestAuthService(newTestConfig(), nil).GenerateTokenPair(ctx, testUser, "")
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(legacy.AccessToken)
+	assert.NoError(t, err)
+
+	jwks := service.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "k1", jwks.Keys[0].Kid)
+}
+
 func TestHashPassword(t *testing.T) {
 	password := "testpassword123"
 
@@ -275,3 +343,142 @@ func TestErrorTypes(t *testing.T) {
 	assert.Equal(t, "invalid token", ErrInvalidToken.Error())
 	assert.Equal(t, "token expired", ErrTokenExpired.Error())
 }
+
+func TestAuthService_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	service := newTestAuthService(newTestConfig(), userrepo.NewMemoryUserRepository())
+
+	registered, err := service.Register(ctx, &RegisterRequest{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, registered.User.Password)
+
+	_, err = service.Register(ctx, &RegisterRequest{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	assert.Equal(t, ErrUserAlreadyExists, err)
+
+	_, err = service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "wrong"})
+	assert.Equal(t, ErrInvalidCredentials, err)
+
+	login, err := service.Login(ctx, &LoginRequest{Email: "test@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, login.Tokens.AccessToken)
+
+	refreshed, err := service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: login.Tokens.RefreshToken})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refreshed.AccessToken)
+}
+
+func newTestSessionService(t *testing.T) (*AuthService, *user.User) {
+	t.Helper()
+
+	users := userrepo.NewMemoryUserRepository()
+	testUser := &user.User{
+		BaseEntity: entity.BaseEntity{Id: uuid.New()},
+		Email:      "test@example.com",
+	}
+	_, err := users.Create(context.Background(), testUser)
+	assert.NoError(t, err)
+
+	return newTestAuthService(newTestConfig(), users), testUser
+}
+
+func TestAuthService_RefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	service, testUser := newTestSessionService(t)
+
+	first, err := service.GenerateTokenPair(ctx, testUser, "laptop")
+	assert.NoError(t, err)
+
+	second, err := service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	third, err := service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: second.RefreshToken})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, third.AccessToken)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: third.AccessToken})
+	assert.Equal(t, ErrInvalidToken, err, "access tokens are not refresh tokens")
+	assert.Equal(t, ErrInvalidToken, service.Logout(ctx, third.AccessToken))
+}
+
+func TestAuthService_RefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	service, testUser := newTestSessionService(t)
+	service.reuseGrace = 0
+
+	first, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+	other, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+
+	second, err := service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.NoError(t, err)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.Equal(t, ErrTokenReused, err)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: second.RefreshToken})
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: other.RefreshToken})
+	assert.NoError(t, err, "other sessions survive")
+}
+
+func TestAuthService_RefreshReuseWithinGrace(t *testing.T) {
+	ctx := context.Background()
+	service, testUser := newTestSessionService(t)
+
+	first, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.NoError(t, err)
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	service, testUser := newTestSessionService(t)
+
+	first, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+	second, err := service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: first.RefreshToken})
+	assert.NoError(t, err)
+	other, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.Logout(ctx, first.RefreshToken))
+
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: second.RefreshToken})
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: other.RefreshToken})
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrInvalidToken, service.Logout(ctx, "invalid-token"))
+}
+
+func TestAuthService_LogoutEverywhere(t *testing.T) {
+	ctx := context.Background()
+	service, testUser := newTestSessionService(t)
+
+	first, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+	second, err := service.GenerateTokenPair(ctx, testUser, "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.LogoutEverywhere(ctx, testUser.Id))
+
+	for _, tokens := range []*TokenPair{first, second} {
+		_, err = service.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		assert.Equal(t, ErrInvalidToken, err)
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	notificationrepo "taskhub/internal/domains/notification/repo"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/nats/natstest"
+	"taskhub/pkg/outbox"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskEventFlow follows a created task through the outbox, the relay and
+// JetStream into the owner's notification inbox.
+func TestTaskEventFlow(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewLogger()
+	n := natstest.New(t)
+
+	tasks := taskrepo.NewMemoryTaskRepository()
+	store := outbox.NewMemoryStore()
+	taskService := NewTaskService(log, tasks, nil, nil, nil, NewOutboxEventPublisher(store), nil)
+	notifications := NewNotificationService(log, n, tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+
+	stop, err := notifications.SubscribeToInbox(ctx)
+	require.NoError(t, err)
+	defer stop()
+
+	userID := uuid.New()
+	_, err = taskService.CreateTask(ctx, &CreateTaskRequest{Title: "Ship it"}, userID)
+	require.NoError(t, err)
+
+	relay := outbox.NewRelay(store, n.PublishStream, log, 10, time.Second)
+	published, err := relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	assert.Eventually(t, func() bool {
+		resp, err := notifications.ListNotifications(ctx, &ListNotificationsRequest{}, userID)
+		return err == nil && len(resp.Notifications) == 1 && resp.Notifications[0].Title == "Ship it"
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"taskhub/internal/domains/label"
+	"taskhub/pkg/base/entity"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var LabelServiceModule = fx.Module(
+	"label-service",
+	fx.Provide(NewLabelService),
+)
+
+var (
+	ErrLabelNotFound = errors.New("label not found")
+	ErrInvalidLabel  = errors.New("label needs a name of at most 50 characters and a #rrggbb color")
+	ErrUnknownLabel  = errors.New("unknown label")
+)
+
+const (
+	maxLabelNameLength = 50
+	// defaultLabelColor is used when a label is saved without a color.
+	defaultLabelColor = "#6b7280"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// LabelService manages the labels users tag their tasks with.
+type LabelService struct {
+	logger    *logger.Logger
+	labelRepo label.LabelStore
+}
+
+func NewLabelService(logger *logger.Logger, labelRepo label.LabelStore) *LabelService {
+	return &LabelService{
+		logger:    logger,
+		labelRepo: labelRepo,
+	}
+}
+
+type SaveLabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// validate trims the name and normalizes the color to lower case, so colors
+// are safe to put in a style attribute.
+func (req *SaveLabelRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxLabelNameLength {
+		return ErrInvalidLabel
+	}
+
+	req.Color = strings.ToLower(strings.TrimSpace(req.Color))
+	if req.Color == "" {
+		req.Color = defaultLabelColor
+	}
+	if !labelColorPattern.MatchString(req.Color) {
+		return ErrInvalidLabel
+	}
+
+	return nil
+}
+
+type LabelResponse struct {
+	Label *label.Label `json:"label"`
+}
+
+type ListLabelsResponse struct {
+	Labels []*label.Label `json:"labels"`
+}
+
+func (s *LabelService) CreateLabel(ctx context.Context, req *SaveLabelRequest, userID uuid.UUID) (*LabelResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	l, err := s.labelRepo.Create(ctx, &label.Label{
+		BaseEntity: entity.BaseEntity{
+			Id:        uuid.New(),
+			CreatedAt: time.Now(),
+			CreatedBy: userID,
+		},
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelResponse{Label: l}, nil
+}
+
+func (s *LabelService) ListLabels(ctx context.Context, userID uuid.UUID) (*ListLabelsResponse, error) {
+	labels, err := s.labelRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if labels == nil {
+		labels = []*label.Label{}
+	}
+
+	return &ListLabelsResponse{Labels: labels}, nil
+}
+
+func (s *LabelService) GetLabel(ctx context.Context, labelID uuid.UUID, userID uuid.UUID) (*LabelResponse, error) {
+	l, err := s.ownedLabel(ctx, labelID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelResponse{Label: l}, nil
+}
+
+func (s *LabelService) UpdateLabel(ctx context.Context, labelID uuid.UUID, req *SaveLabelRequest, userID uuid.UUID) (*LabelResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	l, err := s.ownedLabel(ctx, labelID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	l.Name = req.Name
+	l.Color = req.Color
+	l.UpdateAt = &now
+	l.UpdateBy = &userID
+
+	if _, err := s.labelRepo.UpdateById(ctx, labelID, l); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLabelNotFound
+		}
+		return nil, err
+	}
+
+	return &LabelResponse{Label: l}, nil
+}
+
+// DeleteLabel deletes a label and detaches it from every task.
+func (s *LabelService) DeleteLabel(ctx context.Context, labelID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.ownedLabel(ctx, labelID, userID); err != nil {
+		return err
+	}
+
+	if err := s.labelRepo.DeleteById(ctx, labelID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrLabelNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *LabelService) ownedLabel(ctx context.Context, labelID uuid.UUID, userID uuid.UUID) (*label.Label, error) {
+	l, err := s.labelRepo.FindById(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, ErrLabelNotFound
+	}
+	if l.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return l, nil
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/label"
+	labelrepo "taskhub/internal/domains/label/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	service := NewLabelService(nil, labelrepo.NewMemoryLabelRepository())
+	userID := uuid.New()
+
+	created, err := service.CreateLabel(ctx, &SaveLabelRequest{Name: " urgent ", Color: "#DC2626"}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "urgent", created.Label.Name)
+	assert.Equal(t, "#dc2626", created.Label.Color)
+
+	plain, err := service.CreateLabel(ctx, &SaveLabelRequest{Name: "home"}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultLabelColor, plain.Label.Color)
+
+	list, err := service.ListLabels(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"home", "urgent"}, []string{list.Labels[0].Name, list.Labels[1].Name})
+
+	updated, err := service.UpdateLabel(ctx, created.Label.Id, &SaveLabelRequest{Name: "urgent", Color: "#f59e0b"}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "#f59e0b", updated.Label.Color)
+
+	got, err := service.GetLabel(ctx, created.Label.Id, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "#f59e0b", got.Label.Color)
+
+	assert.NoError(t, service.DeleteLabel(ctx, created.Label.Id, userID))
+	_, err = service.GetLabel(ctx, created.Label.Id, userID)
+	assert.ErrorIs(t, err, ErrLabelNotFound)
+
+	list, err = service.ListLabels(ctx, uuid.New())
+	assert.NoError(t, err)
+	assert.Empty(t, list.Labels)
+}
+
+func TestLabelService_Errors(t *testing.T) {
+	ctx := context.Background()
+	service := NewLabelService(nil, labelrepo.NewMemoryLabelRepository())
+	userID := uuid.New()
+
+	_, err := service.CreateLabel(ctx, &SaveLabelRequest{Name: " "}, userID)
+	assert.ErrorIs(t, err, ErrInvalidLabel)
+
+	_, err = service.CreateLabel(ctx, &SaveLabelRequest{Name: "red", Color: "red"}, userID)
+	assert.ErrorIs(t, err, ErrInvalidLabel)
+
+	created, err := service.CreateLabel(ctx, &SaveLabelRequest{Name: "work"}, userID)
+	assert.NoError(t, err)
+
+	_, err = service.CreateLabel(ctx, &SaveLabelRequest{Name: "work"}, userID)
+	assert.ErrorIs(t, err, label.ErrNameTaken)
+
+	_, err = service.GetLabel(ctx, created.Label.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	err = service.DeleteLabel(ctx, created.Label.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.UpdateLabel(ctx, uuid.New(), &SaveLabelRequest{Name: "ghost"}, userID)
+	assert.ErrorIs(t, err, ErrLabelNotFound)
+}
@@ -2,10 +2,15 @@ package app
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"taskhub/internal/domains/notification"
 	"taskhub/internal/domains/task"
-	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/pkg/base/entity"
 	"taskhub/pkg/logger"
 	natsconn "taskhub/pkg/nats"
 	"time"
@@ -19,6 +24,19 @@ var NotificationServiceModule = fx.Module(
 	fx.Provide(NewNotificationService),
 )
 
+// NotificationInboxModule stores reminders and task events published on NATS
+// in each user's inbox.
+var NotificationInboxModule = fx.Module(
+	"notification-inbox",
+	fx.Invoke(registerInboxSubscriber),
+)
+
+// inboxConsumer is the durable consumer shared by every replica, so each
+// event is stored by one replica only.
+const inboxConsumer = "notification-inbox"
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
 const (
 	SubjectTaskCreated  = "task.created"
 	SubjectTaskUpdated  = "task.updated"
@@ -26,115 +44,302 @@ const (
 )
 
 type NotificationService struct {
-	logger   *logger.Logger
-	nats     *natsconn.Nats
-	taskRepo *taskrepo.TaskRepository
+	logger           *logger.Logger
+	nats             *natsconn.Nats
+	taskRepo         task.TaskStore
+	reminderLog      notification.ReminderLog
+	notificationRepo notification.NotificationStore
 }
 
-func NewNotificationService(logger *logger.Logger, nats *natsconn.Nats, taskRepo *taskrepo.TaskRepository) *NotificationService {
+func NewNotificationService(
+	logger *logger.Logger,
+	nats *natsconn.Nats,
+	taskRepo task.TaskStore,
+	reminderLog notification.ReminderLog,
+	notificationRepo notification.NotificationStore,
+) *NotificationService {
 	return &NotificationService{
-		logger:   logger,
-		nats:     nats,
-		taskRepo: taskRepo,
+		logger:           logger,
+		nats:             nats,
+		taskRepo:         taskRepo,
+		reminderLog:      reminderLog,
+		notificationRepo: notificationRepo,
 	}
 }
 
 type TaskEvent struct {
-	EventType string     `json:"event_type"`
-	TaskID    uuid.UUID  `json:"task_id"`
-	UserID    uuid.UUID  `json:"user_id"`
-	Title     string     `json:"title"`
-	Deadline  *time.Time `json:"deadline,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-}
-
-func (s *NotificationService) PublishTaskCreated(ctx context.Context, t *task.Task) error {
-	event := &TaskEvent{
-		EventType: SubjectTaskCreated,
-		TaskID:    t.Id,
-		UserID:    t.UserID,
-		Title:     t.Title,
-		Deadline:  t.Deadline,
-		CreatedAt: time.Now(),
-	}
-
-	return s.publishEvent(SubjectTaskCreated, event)
-}
-
-func (s *NotificationService) PublishTaskUpdated(ctx context.Context, t *task.Task) error {
-	event := &TaskEvent{
-		EventType: SubjectTaskUpdated,
-		TaskID:    t.Id,
-		UserID:    t.UserID,
-		Title:     t.Title,
-		Deadline:  t.Deadline,
-		CreatedAt: time.Now(),
-	}
-
-	return s.publishEvent(SubjectTaskUpdated, event)
-}
-
-func (s *NotificationService) publishEvent(subject string, event *TaskEvent) error {
-	data, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	return s.nats.Publish(subject, data)
+	ID        uuid.UUID              `json:"id"`
+	EventType string                 `json:"event_type"`
+	TaskID    uuid.UUID              `json:"task_id"`
+	UserID    uuid.UUID              `json:"user_id"`
+	Title     string                 `json:"title"`
+	Status    task.TaskStatus        `json:"status,omitempty"`
+	Deadline  *time.Time             `json:"deadline,omitempty"`
+	Changes   map[string]FieldChange `json:"changes,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
 }
 
 type ReminderNotification struct {
-	ID        uuid.UUID `json:"id"`
-	TaskID    uuid.UUID `json:"task_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Title     string    `json:"title"`
-	Message   string    `json:"message"`
-	Deadline  time.Time `json:"deadline"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID     `json:"id"`
+	TaskID    uuid.UUID     `json:"task_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Title     string        `json:"title"`
+	Message   string        `json:"message"`
+	Deadline  time.Time     `json:"deadline"`
+	Threshold time.Duration `json:"threshold"`
+	CreatedAt time.Time     `json:"created_at"`
 }
 
-func (s *NotificationService) CheckAndSendReminders(ctx context.Context, hoursAhead int) ([]*notification.Notification, error) {
+// CheckAndSendReminders publishes one reminder per task for the tightest
+// threshold its deadline has crossed, looking lookAhead into the future. The
+// reminder log guarantees each threshold fires once per deadline.
+func (s *NotificationService) CheckAndSendReminders(ctx context.Context, lookAhead time.Duration, thresholds []time.Duration) ([]*ReminderNotification, error) {
+	hoursAhead := int(math.Ceil(lookAhead.Hours()))
 	tasks, err := s.taskRepo.FindTasksNearDeadline(ctx, hoursAhead)
 	if err != nil {
 		return nil, err
 	}
 
-	var notifications []*notification.Notification
+	var reminders []*ReminderNotification
 	for _, t := range tasks {
+		threshold, ok := reminderThreshold(time.Until(*t.Deadline), thresholds)
+		if !ok {
+			continue
+		}
+
+		claimed, err := s.reminderLog.Claim(ctx, t.Id, *t.Deadline, threshold)
+		if err != nil {
+			s.logger.Error("failed to claim reminder", "task_id", t.Id, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
 		reminder := &ReminderNotification{
 			ID:        uuid.New(),
 			TaskID:    t.Id,
 			UserID:    t.UserID,
 			Title:     t.Title,
-			Message:   "Task deadline is approaching",
+			Message:   fmt.Sprintf("Task is due within %s", formatThreshold(threshold)),
 			Deadline:  *t.Deadline,
+			Threshold: threshold,
 			CreatedAt: time.Now(),
 		}
 
-		data, err := json.Marshal(reminder)
-		if err != nil {
-			s.logger.Error("failed to marshal reminder", "error", err)
+		if err := s.publishReminder(ctx, reminder); err != nil {
+			s.logger.Error("failed to publish reminder", "task_id", t.Id, "error", err)
+			if err := s.reminderLog.Release(ctx, t.Id, *t.Deadline, threshold); err != nil {
+				s.logger.Error("failed to release reminder", "task_id", t.Id, "error", err)
+			}
 			continue
 		}
 
-		if err := s.nats.Publish(SubjectTaskReminder, data); err != nil {
-			s.logger.Error("failed to publish reminder", "error", err)
-			continue
+		s.logger.Info("reminder sent for task", "task_id", t.Id, "user_id", t.UserID, "threshold", threshold)
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, nil
+}
+
+// publishReminder waits for JetStream to store the reminder, so that a
+// reminder that was not sent is released and retried on the next check. The
+// message id is the claim, so a retry after a lost ack is not sent twice.
+func (s *NotificationService) publishReminder(ctx context.Context, reminder *ReminderNotification) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+
+	msgID := fmt.Sprintf("reminder:%s:%d:%d", reminder.TaskID, reminder.Deadline.Unix(), int64(reminder.Threshold/time.Minute))
+	return s.nats.PublishStream(ctx, SubjectTaskReminder, msgID, data)
+}
+
+// reminderThreshold picks the smallest threshold that remaining has already
+// crossed. Because remaining only shrinks, larger thresholds are never picked
+// again once a smaller one applies. Overdue tasks get no reminder.
+func reminderThreshold(remaining time.Duration, thresholds []time.Duration) (time.Duration, bool) {
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	sorted := append([]time.Duration(nil), thresholds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, threshold := range sorted {
+		if remaining <= threshold {
+			return threshold, true
 		}
+	}
 
-		s.logger.Info("reminder sent for task", "task_id", t.Id, "user_id", t.UserID)
+	return 0, false
+}
+
+func formatThreshold(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return pluralize(int(d/(24*time.Hour)), "day")
+	case d >= time.Hour && d%time.Hour == 0:
+		return pluralize(int(d/time.Hour), "hour")
+	case d >= time.Minute && d%time.Minute == 0:
+		return pluralize(int(d/time.Minute), "minute")
+	default:
+		return d.String()
 	}
+}
 
-	return notifications, nil
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
 }
 
-func (s *NotificationService) SubscribeToReminders(ctx context.Context, handler func(*ReminderNotification)) error {
-	return s.nats.Subscribe(SubjectTaskReminder, func(data []byte) {
+func registerInboxSubscriber(lc fx.Lifecycle, s *NotificationService) {
+	var stop func()
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			var err error
+			stop, err = s.SubscribeToInbox(ctx)
+			return err
+		},
+		OnStop: func(ctx context.Context) error {
+			if stop != nil {
+				stop()
+			}
+			return nil
+		},
+	})
+}
+
+// SubscribeToInbox stores every reminder and task event in the owning user's
+// inbox so users who were offline can fetch them later. It reads from a
+// durable JetStream consumer, so events published while no replica is
+// running are stored once one starts.
+func (s *NotificationService) SubscribeToInbox(ctx context.Context) (stop func(), err error) {
+	return s.nats.Consume(ctx, natsconn.ConsumerConfig{
+		Durable:  inboxConsumer,
+		Stream:   natsconn.TaskStream.Name,
+		Subjects: append([]string{SubjectTaskReminder}, TaskSubjects...),
+	}, s.handleInboxMessage)
+}
+
+func (s *NotificationService) handleInboxMessage(ctx context.Context, msg *natsconn.Message) error {
+	if msg.Subject == SubjectTaskReminder {
 		var reminder ReminderNotification
-		if err := json.Unmarshal(data, &reminder); err != nil {
-			s.logger.Error("failed to unmarshal reminder", "error", err)
-			return
+		if err := json.Unmarshal(msg.Data, &reminder); err != nil {
+			return natsconn.Permanent(err)
 		}
-		handler(&reminder)
+		return s.RecordReminder(ctx, &reminder)
+	}
+
+	var event TaskEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return natsconn.Permanent(err)
+	}
+	return s.RecordTaskEvent(ctx, &event)
+}
+
+func (s *NotificationService) RecordReminder(ctx context.Context, reminder *ReminderNotification) error {
+	taskID := reminder.TaskID
+	_, err := s.notificationRepo.Create(ctx, &notification.Notification{
+		BaseEntity: entity.BaseEntity{
+			Id:        reminder.ID,
+			CreatedAt: reminder.CreatedAt,
+		},
+		UserID:  reminder.UserID,
+		TaskID:  &taskID,
+		Type:    notification.TypeReminder,
+		Title:   reminder.Title,
+		Message: reminder.Message,
 	})
+	return err
+}
+
+func (s *NotificationService) RecordTaskEvent(ctx context.Context, event *TaskEvent) error {
+	var notificationType notification.NotificationType
+	var message string
+	switch event.EventType {
+	case SubjectTaskCreated:
+		notificationType = notification.TypeTaskCreated
+		message = "Task created"
+	case SubjectTaskUpdated:
+		notificationType = notification.TypeTaskUpdated
+		message = "Task updated"
+	case SubjectTaskCompleted:
+		notificationType = notification.TypeTaskCompleted
+		message = "Task completed"
+	case SubjectTaskDeleted:
+		notificationType = notification.TypeTaskDeleted
+		message = "Task deleted"
+	case SubjectTaskReopened:
+		notificationType = notification.TypeTaskReopened
+		message = "Task reopened"
+	default:
+		return nil
+	}
+
+	id := event.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	taskID := event.TaskID
+	_, err := s.notificationRepo.Create(ctx, &notification.Notification{
+		BaseEntity: entity.BaseEntity{
+			Id:        id,
+			CreatedAt: event.CreatedAt,
+		},
+		UserID:  event.UserID,
+		TaskID:  &taskID,
+		Type:    notificationType,
+		Title:   event.Title,
+		Message: message,
+	})
+	return err
+}
+
+type ListNotificationsRequest struct {
+	UnreadOnly bool `json:"unread_only"`
+	Limit      int  `json:"limit"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []*notification.Notification `json:"notifications"`
+	Unread        int                          `json:"unread"`
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, req *ListNotificationsRequest, userID uuid.UUID) (*ListNotificationsResponse, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	notifications, err := s.notificationRepo.FindByUserId(ctx, &notification.NotificationFilter{
+		UserID:     userID,
+		UnreadOnly: req.UnreadOnly,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListNotificationsResponse{Notifications: notifications, Unread: unread}, nil
+}
+
+func (s *NotificationService) MarkNotificationRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	err := s.notificationRepo.MarkAsRead(ctx, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotificationNotFound
+	}
+	return err
+}
+
+func (s *NotificationService) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllAsRead(ctx, userID)
 }
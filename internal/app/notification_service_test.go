@@ -1,9 +1,21 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"taskhub/config"
+	"taskhub/internal/domains/notification"
+	notificationrepo "taskhub/internal/domains/notification/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/pkg/logger"
+	natsconn "taskhub/pkg/nats"
+	"taskhub/pkg/nats/natstest"
+	"taskhub/pkg/scheduler"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -73,9 +85,216 @@ func TestNotificationServiceModule(t *testing.T) {
 }
 
 func TestNewNotificationService_Nil(t *testing.T) {
-	service := NewNotificationService(nil, nil, nil)
+	service := NewNotificationService(nil, nil, nil, nil, nil)
 	assert.NotNil(t, service)
 	assert.Nil(t, service.logger)
 	assert.Nil(t, service.nats)
 	assert.Nil(t, service.taskRepo)
+	assert.Nil(t, service.reminderLog)
+}
+
+func TestReminderThreshold(t *testing.T) {
+	thresholds := []time.Duration{time.Hour, 24 * time.Hour}
+
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		expected  time.Duration
+		ok        bool
+	}{
+		{"outside all thresholds", 48 * time.Hour, 0, false},
+		{"within a day", 23 * time.Hour, 24 * time.Hour, true},
+		{"within an hour", 30 * time.Minute, time.Hour, true},
+		{"overdue", -time.Hour, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, ok := reminderThreshold(tt.remaining, thresholds)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, threshold)
+		})
+	}
+}
+
+func TestFormatThreshold(t *testing.T) {
+	assert.Equal(t, "1 day", formatThreshold(24*time.Hour))
+	assert.Equal(t, "2 days", formatThreshold(48*time.Hour))
+	assert.Equal(t, "1 hour", formatThreshold(time.Hour))
+	assert.Equal(t, "30 minutes", formatThreshold(30*time.Minute))
+	assert.Equal(t, "90 minutes", formatThreshold(90*time.Minute))
+	assert.Equal(t, "1m30s", formatThreshold(90*time.Second))
+}
+
+func TestCheckAndSendReminders_OncePerThreshold(t *testing.T) {
+	ctx := context.Background()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	service := NewNotificationService(logger.NewLogger(), natstest.New(t), tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	thresholds := []time.Duration{24 * time.Hour, time.Hour}
+	userID := uuid.New()
+
+	dayAhead := time.Now().Add(20 * time.Hour)
+	hourAhead := time.Now().Add(30 * time.Minute)
+	tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Tomorrow", Deadline: &dayAhead}, userID))
+	tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Soon", Deadline: &hourAhead}, userID))
+
+	reminders, err := service.CheckAndSendReminders(ctx, 24*time.Hour, thresholds)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 2)
+
+	reminders, err = service.CheckAndSendReminders(ctx, 24*time.Hour, thresholds)
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
+func TestCheckAndSendReminders_RetriesUnsentReminders(t *testing.T) {
+	ctx := context.Background()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	reminderLog := notificationrepo.NewMemoryReminderLog()
+	soon := time.Now().Add(30 * time.Minute)
+	tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Soon", Deadline: &soon}, uuid.New()))
+
+	disconnected := NewNotificationService(logger.NewLogger(), nil, tasks, reminderLog, notificationrepo.NewMemoryNotificationRepository())
+	reminders, err := disconnected.CheckAndSendReminders(ctx, time.Hour, []time.Duration{time.Hour})
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	connected := NewNotificationService(logger.NewLogger(), natstest.New(t), tasks, reminderLog, notificationrepo.NewMemoryNotificationRepository())
+	reminders, err = connected.CheckAndSendReminders(ctx, time.Hour, []time.Duration{time.Hour})
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1, "a reminder that could not be published is sent later")
+}
+
+func TestCheckAndSendReminders_SkipsOverdueTasks(t *testing.T) {
+	ctx := context.Background()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	service := NewNotificationService(logger.NewLogger(), natstest.New(t), tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+
+	overdue := time.Now().Add(-time.Hour)
+	tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Late", Deadline: &overdue}, uuid.New()))
+
+	reminders, err := service.CheckAndSendReminders(ctx, 24*time.Hour, []time.Duration{24 * time.Hour, time.Hour})
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
+func TestCheckAndSendReminders_SkipsCompletedTasks(t *testing.T) {
+	ctx := context.Background()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	service := NewNotificationService(logger.NewLogger(), nil, tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	userID := uuid.New()
+
+	soon := time.Now().Add(30 * time.Minute)
+	created, _ := tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Done", Deadline: &soon}, userID))
+	tasks.MarkAsCompleted(ctx, created.Id, userID)
+
+	reminders, err := service.CheckAndSendReminders(ctx, time.Hour, []time.Duration{time.Hour})
+	assert.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
+func TestReminderScheduler_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	service := NewNotificationService(logger.NewLogger(), nil, tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	soon := time.Now().Add(30 * time.Minute)
+	tasks.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Soon", Deadline: &soon}, uuid.New()))
+
+	s := newReminderScheduler(&config.Reminder{
+		Interval:   time.Minute,
+		LookAhead:  24 * time.Hour,
+		Thresholds: []time.Duration{time.Hour},
+	}, &scheduler.LocalLocker{}, logger.NewLogger(), service)
+
+	ran, err := s.RunOnce(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ran)
+}
+
+func TestReminderSchedulerModule(t *testing.T) {
+	assert.NotNil(t, ReminderSchedulerModule)
+}
+
+func TestRecordReminder_StoresInInbox(t *testing.T) {
+	ctx := context.Background()
+	service := NewNotificationService(logger.NewLogger(), nil, taskrepo.NewMemoryTaskRepository(), notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	userID := uuid.New()
+
+	reminder := &ReminderNotification{
+		ID:        uuid.New(),
+		TaskID:    uuid.New(),
+		UserID:    userID,
+		Title:     "Soon",
+		Message:   "Task \"Soon\" is due in 1 hour",
+		CreatedAt: time.Now(),
+	}
+	assert.NoError(t, service.RecordReminder(ctx, reminder))
+	assert.NoError(t, service.RecordReminder(ctx, reminder))
+
+	resp, err := service.ListNotifications(ctx, &ListNotificationsRequest{}, userID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 1)
+	assert.Equal(t, 1, resp.Unread)
+	assert.Equal(t, reminder.ID, resp.Notifications[0].Id)
+
+	assert.ErrorIs(t, service.MarkNotificationRead(ctx, reminder.ID, uuid.New()), ErrNotificationNotFound)
+	assert.NoError(t, service.MarkNotificationRead(ctx, reminder.ID, userID))
+
+	resp, err = service.ListNotifications(ctx, &ListNotificationsRequest{UnreadOnly: true}, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Notifications)
+}
+
+func TestRecordTaskEvent_RecordsEveryTaskSubject(t *testing.T) {
+	ctx := context.Background()
+	service := NewNotificationService(logger.NewLogger(), nil, taskrepo.NewMemoryTaskRepository(), notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	userID := uuid.New()
+
+	expected := map[notification.NotificationType]string{
+		notification.TypeTaskCreated:   "Task created",
+		notification.TypeTaskUpdated:   "Task updated",
+		notification.TypeTaskCompleted: "Task completed",
+		notification.TypeTaskDeleted:   "Task deleted",
+		notification.TypeTaskReopened:  "Task reopened",
+	}
+	for _, subject := range TaskSubjects {
+		assert.NoError(t, service.RecordTaskEvent(ctx, &TaskEvent{ID: uuid.New(), EventType: subject, TaskID: uuid.New(), UserID: userID, Title: "T"}))
+	}
+
+	resp, err := service.ListNotifications(ctx, &ListNotificationsRequest{}, userID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, len(TaskSubjects))
+	for _, n := range resp.Notifications {
+		assert.Equal(t, expected[n.Type], n.Message, n.Type)
+	}
+}
+
+func TestRecordTaskEvent_IgnoresOtherEvents(t *testing.T) {
+	ctx := context.Background()
+	service := NewNotificationService(logger.NewLogger(), nil, taskrepo.NewMemoryTaskRepository(), notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	userID := uuid.New()
+
+	assert.NoError(t, service.RecordTaskEvent(ctx, &TaskEvent{ID: uuid.New(), EventType: "task.unknown", UserID: userID}))
+	assert.NoError(t, service.RecordTaskEvent(ctx, &TaskEvent{ID: uuid.New(), EventType: SubjectTaskUpdated, UserID: userID, Title: "T"}))
+
+	resp, err := service.ListNotifications(ctx, &ListNotificationsRequest{}, userID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 1)
+	assert.Equal(t, "task_updated", string(resp.Notifications[0].Type))
+}
+
+func TestHandleInboxMessage(t *testing.T) {
+	ctx := context.Background()
+	service := NewNotificationService(logger.NewLogger(), nil, taskrepo.NewMemoryTaskRepository(), notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+	userID := uuid.New()
+
+	err := service.handleInboxMessage(ctx, &natsconn.Message{Subject: SubjectTaskCreated, Data: []byte("not json")})
+	assert.True(t, natsconn.IsPermanent(err))
+
+	data, _ := json.Marshal(&TaskEvent{ID: uuid.New(), EventType: SubjectTaskCreated, UserID: userID, Title: "New"})
+	assert.NoError(t, service.handleInboxMessage(ctx, &natsconn.Message{Subject: SubjectTaskCreated, Data: data}))
+
+	resp, err := service.ListNotifications(ctx, &ListNotificationsRequest{}, userID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 1)
 }
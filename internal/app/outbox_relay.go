@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"taskhub/config"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	natsconn "taskhub/pkg/nats"
+	"taskhub/pkg/outbox"
+	"taskhub/pkg/scheduler"
+
+	"go.uber.org/fx"
+)
+
+var OutboxRelayModule = fx.Module(
+	"outbox-relay",
+	fx.Provide(NewOutboxRelay),
+	fx.Invoke(startOutboxRelay),
+)
+
+// outboxLockKey is the advisory lock that elects the single replica relaying
+// the outbox, which keeps events in id order.
+const outboxLockKey int64 = 0x7461736b6f7574 // "taskout"
+
+type OutboxRelay struct {
+	*outbox.Relay
+	scheduler *scheduler.Scheduler
+}
+
+func NewOutboxRelay(config *config.Config, logger *logger.Logger, nats *natsconn.Nats, store outbox.Store) *OutboxRelay {
+	locker := db.NewAdvisoryLocker(db.NewDB(config).GetConnection(), outboxLockKey)
+	return newOutboxRelay(config.Outbox, locker, logger, store, nats.PublishStream)
+}
+
+func newOutboxRelay(cfg *config.Outbox, locker scheduler.Locker, logger *logger.Logger, store outbox.Store, publish outbox.Publisher) *OutboxRelay {
+	relay := outbox.NewRelay(store, publish, logger, cfg.BatchSize, cfg.MaxBackoff)
+
+	job := func(ctx context.Context) error {
+		// Drain full batches back to back so a backlog clears without
+		// waiting a poll interval per batch.
+		for {
+			published, err := relay.RunOnce(ctx)
+			if err != nil {
+				return err
+			}
+			if published < cfg.BatchSize {
+				return nil
+			}
+		}
+	}
+
+	return &OutboxRelay{
+		Relay:     relay,
+		scheduler: scheduler.NewScheduler("outbox-relay", cfg.PollInterval, locker, job, logger),
+	}
+}
+
+func startOutboxRelay(lc fx.Lifecycle, r *OutboxRelay) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			r.scheduler.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return r.scheduler.Stop(ctx)
+		},
+	})
+}
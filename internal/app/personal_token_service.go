@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"taskhub/internal/domains/apitoken"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var PersonalTokenServiceModule = fx.Module(
+	"personal-token-service",
+	fx.Provide(NewPersonalTokenService),
+)
+
+var ErrPersonalTokenNotFound = errors.New("personal access token not found")
+
+const (
+	// maxPersonalTokenDays caps how far ahead a token's expiry can be set.
+	maxPersonalTokenDays = 366
+	// personalTokenPrefixLength is how much of a token is kept for display.
+	personalTokenPrefixLength = len(apitoken.TokenPrefix) + 8
+	// lastUsedResolution limits how often a busy token's LastUsedAt is
+	// written.
+	lastUsedResolution = time.Minute
+)
+
+// PersonalTokenService manages the personal access tokens users create for
+// scripts and CI jobs, and authenticates requests made with them.
+type PersonalTokenService struct {
+	logger    *logger.Logger
+	tokenRepo apitoken.TokenStore
+}
+
+func NewPersonalTokenService(logger *logger.Logger, tokenRepo apitoken.TokenStore) *PersonalTokenService {
+	return &PersonalTokenService{
+		logger:    logger,
+		tokenRepo: tokenRepo,
+	}
+}
+
+type CreatePersonalTokenRequest struct {
+	Name   string           `json:"name"`
+	Scopes []apitoken.Scope `json:"scopes"`
+	// ExpiresInDays is how long the token lasts; zero means it never
+	// expires.
+	ExpiresInDays int `json:"expires_in_days,omitempty"`
+}
+
+type CreatePersonalTokenResponse struct {
+	Token *apitoken.Token `json:"token"`
+	// Secret is the token itself. It is not stored, so this is the only
+	// time it is shown.
+	Secret string `json:"secret"`
+}
+
+type ListPersonalTokensResponse struct {
+	Tokens []*apitoken.Token `json:"tokens"`
+}
+
+// CreateToken gives the user a new token. It fails with an error wrapping
+// apitoken.ErrInvalidToken when the name, scopes or expiry are unusable.
+func (s *PersonalTokenService) CreateToken(ctx context.Context, req *CreatePersonalTokenRequest, userID uuid.UUID) (*CreatePersonalTokenResponse, error) {
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxPersonalTokenDays {
+		return nil, fmt.Errorf("%w: expiry must be 0 to %d days", apitoken.ErrInvalidToken, maxPersonalTokenDays)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	secret := apitoken.TokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	t := &apitoken.Token{
+		Id:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    secret[:personalTokenPrefixLength],
+		TokenHash: hashActionToken(secret),
+		Scopes:    req.Scopes,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		t.ExpiresAt = &expiresAt
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return &CreatePersonalTokenResponse{Token: t, Secret: secret}, nil
+}
+
+func (s *PersonalTokenService) ListTokens(ctx context.Context, userID uuid.UUID) (*ListPersonalTokensResponse, error) {
+	tokens, err := s.tokenRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = []*apitoken.Token{}
+	}
+
+	return &ListPersonalTokensResponse{Tokens: tokens}, nil
+}
+
+// DeleteToken revokes one of the user's tokens.
+func (s *PersonalTokenService) DeleteToken(ctx context.Context, tokenID uuid.UUID, userID uuid.UUID) error {
+	t, err := s.tokenRepo.FindById(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return ErrPersonalTokenNotFound
+	}
+	if t.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	if err := s.tokenRepo.DeleteById(ctx, tokenID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPersonalTokenNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate returns the token secret belongs to, failing with
+// ErrInvalidToken for an unknown token and ErrTokenExpired for an expired
+// one. It records when the token was used.
+func (s *PersonalTokenService) Authenticate(ctx context.Context, secret string) (*apitoken.Token, error) {
+	if !strings.HasPrefix(secret, apitoken.TokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	t, err := s.tokenRepo.FindByHash(ctx, hashActionToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	if t.Expired(now) {
+		return nil, ErrTokenExpired
+	}
+
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= lastUsedResolution {
+		if err := s.tokenRepo.TouchLastUsed(ctx, t.Id, now); err != nil {
+			s.logger.Error("Failed to record personal access token use", "token_id", t.Id, "error", err)
+		} else {
+			t.LastUsedAt = &now
+		}
+	}
+
+	return t, nil
+}
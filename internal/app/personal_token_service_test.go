@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/apitoken"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalTokenService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	service := NewPersonalTokenService(logger.NewLogger(), apitokenrepo.NewMemoryTokenRepository())
+	userID := uuid.New()
+
+	created, err := service.CreateToken(ctx, &CreatePersonalTokenRequest{
+		Name:          "ci",
+		Scopes:        []apitoken.Scope{apitoken.ScopeTasksWrite},
+		ExpiresInDays: 30,
+	}, userID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Secret, created.Token.Prefix))
+	assert.NotContains(t, created.Token.TokenHash, created.Secret)
+	require.NotNil(t, created.Token.ExpiresAt)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), *created.Token.ExpiresAt, time.Minute)
+
+	forever, err := service.CreateToken(ctx, &CreatePersonalTokenRequest{Name: "backup", Scopes: []apitoken.Scope{apitoken.ScopeProjectsRead}}, userID)
+	require.NoError(t, err)
+	assert.Nil(t, forever.Token.ExpiresAt)
+
+	_, err = service.CreateToken(ctx, &CreatePersonalTokenRequest{Name: "ci", Scopes: []apitoken.Scope{"tasks:delete"}}, userID)
+	assert.ErrorIs(t, err, apitoken.ErrInvalidToken)
+	_, err = service.CreateToken(ctx, &CreatePersonalTokenRequest{Name: "ci", Scopes: []apitoken.Scope{apitoken.ScopeTasksRead}, ExpiresInDays: 1000}, userID)
+	assert.ErrorIs(t, err, apitoken.ErrInvalidToken)
+
+	list, err := service.ListTokens(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, list.Tokens, 2)
+
+	assert.ErrorIs(t, service.DeleteToken(ctx, created.Token.Id, uuid.New()), ErrUnauthorized)
+	assert.NoError(t, service.DeleteToken(ctx, created.Token.Id, userID))
+	assert.ErrorIs(t, service.DeleteToken(ctx, created.Token.Id, userID), ErrPersonalTokenNotFound)
+
+	_, err = service.Authenticate(ctx, created.Secret)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestPersonalTokenService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	tokens := apitokenrepo.NewMemoryTokenRepository()
+	service := NewPersonalTokenService(logger.NewLogger(), tokens)
+	userID := uuid.New()
+
+	created, err := service.CreateToken(ctx, &CreatePersonalTokenRequest{Name: "ci", Scopes: []apitoken.Scope{apitoken.ScopeTasksRead}}, userID)
+	require.NoError(t, err)
+
+	got, err := service.Authenticate(ctx, created.Secret)
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.UserID)
+	stored, err := tokens.FindById(ctx, created.Token.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastUsedAt)
+
+	_, err = service.Authenticate(ctx, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = service.Authenticate(ctx, created.Secret+"x")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired := &apitoken.Token{Id: uuid.New(), UserID: userID, Name: "old", TokenHash: hashActionToken(apitoken.TokenPrefix + "old"), Scopes: []apitoken.Scope{apitoken.ScopeTasksRead}, ExpiresAt: &expiresAt}
+	require.NoError(t, tokens.Create(ctx, expired))
+	_, err = service.Authenticate(ctx, apitoken.TokenPrefix+"old")
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"taskhub/internal/domains/project"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/base/entity"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var ProjectServiceModule = fx.Module(
+	"project-service",
+	fx.Provide(NewProjectService),
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrInvalidProject  = errors.New("project needs a name of at most 100 characters and a default priority of low, medium or high")
+	ErrUnknownProject  = errors.New("unknown project")
+	ErrProjectArchived = errors.New("project is archived")
+)
+
+const maxProjectNameLength = 100
+
+// ProjectService manages the projects users group their tasks in.
+type ProjectService struct {
+	logger      *logger.Logger
+	projectRepo project.ProjectStore
+	taskRepo    task.TaskStore
+	tx          db.Transactor
+}
+
+func NewProjectService(logger *logger.Logger, projectRepo project.ProjectStore, taskRepo task.TaskStore, tx db.Transactor) *ProjectService {
+	if tx == nil {
+		tx = db.NoTx{}
+	}
+
+	return &ProjectService{
+		logger:      logger,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		tx:          tx,
+	}
+}
+
+type SaveProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// DefaultPriority is given to tasks created in the project without a
+	// priority. It defaults to medium.
+	DefaultPriority task.TaskPriority `json:"default_priority"`
+}
+
+func (req *SaveProjectRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxProjectNameLength {
+		return ErrInvalidProject
+	}
+
+	if req.DefaultPriority == "" {
+		req.DefaultPriority = task.PriorityMedium
+	}
+	if !slices.Contains([]task.TaskPriority{task.PriorityLow, task.PriorityMedium, task.PriorityHigh}, req.DefaultPriority) {
+		return ErrInvalidProject
+	}
+
+	return nil
+}
+
+type ProjectResponse struct {
+	Project *project.Project `json:"project"`
+}
+
+type ListProjectsResponse struct {
+	Projects []*project.Project `json:"projects"`
+}
+
+func (s *ProjectService) CreateProject(ctx context.Context, req *SaveProjectRequest, userID uuid.UUID) (*ProjectResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	p, err := s.projectRepo.Create(ctx, &project.Project{
+		BaseEntity: entity.BaseEntity{
+			Id:        uuid.New(),
+			CreatedAt: time.Now(),
+			CreatedBy: userID,
+		},
+		UserID:          userID,
+		Name:            req.Name,
+		Description:     req.Description,
+		DefaultPriority: req.DefaultPriority,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectResponse{Project: p}, nil
+}
+
+// ListProjects returns the user's projects, including archived ones only
+// when archived is set.
+func (s *ProjectService) ListProjects(ctx context.Context, archived bool, userID uuid.UUID) (*ListProjectsResponse, error) {
+	projects, err := s.projectRepo.FindByUserId(ctx, userID, archived)
+	if err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		projects = []*project.Project{}
+	}
+
+	return &ListProjectsResponse{Projects: projects}, nil
+}
+
+func (s *ProjectService) GetProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*ProjectResponse, error) {
+	p, err := s.ownedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectResponse{Project: p}, nil
+}
+
+func (s *ProjectService) UpdateProject(ctx context.Context, projectID uuid.UUID, req *SaveProjectRequest, userID uuid.UUID) (*ProjectResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	p, err := s.ownedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	p.Name = req.Name
+	p.Description = req.Description
+	p.DefaultPriority = req.DefaultPriority
+	p.UpdateAt = &now
+	p.UpdateBy = &userID
+
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &ProjectResponse{Project: p}, nil
+}
+
+// ArchiveProject archives a project along with all its tasks, which drop out
+// of task listings until the project is unarchived.
+func (s *ProjectService) ArchiveProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*ProjectResponse, error) {
+	return s.setArchived(ctx, projectID, true, userID)
+}
+
+// UnarchiveProject restores an archived project and its tasks.
+func (s *ProjectService) UnarchiveProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*ProjectResponse, error) {
+	return s.setArchived(ctx, projectID, false, userID)
+}
+
+func (s *ProjectService) setArchived(ctx context.Context, projectID uuid.UUID, archived bool, userID uuid.UUID) (*ProjectResponse, error) {
+	p, err := s.ownedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (p.ArchivedAt != nil) == archived {
+		return &ProjectResponse{Project: p}, nil
+	}
+
+	now := time.Now()
+	p.ArchivedAt = nil
+	if archived {
+		p.ArchivedAt = &now
+	}
+	p.UpdateAt = &now
+	p.UpdateBy = &userID
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.save(ctx, p); err != nil {
+			return err
+		}
+		return s.taskRepo.ArchiveByProject(ctx, projectID, p.ArchivedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectResponse{Project: p}, nil
+}
+
+// DeleteProject deletes a project. Its tasks are kept, outside any project,
+// and unarchived if the project was archived.
+func (s *ProjectService) DeleteProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) error {
+	p, err := s.ownedProject(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if p.ArchivedAt != nil {
+			if err := s.taskRepo.ArchiveByProject(ctx, projectID, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := s.projectRepo.DeleteById(ctx, projectID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *ProjectService) save(ctx context.Context, p *project.Project) error {
+	if _, err := s.projectRepo.UpdateById(ctx, p.Id, p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ProjectService) ownedProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*project.Project, error) {
+	p, err := s.projectRepo.FindById(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrProjectNotFound
+	}
+	if p.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return p, nil
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/project"
+	projectrepo "taskhub/internal/domains/project/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	service := NewProjectService(nil, projectrepo.NewMemoryProjectRepository(), taskrepo.NewMemoryTaskRepository(), nil)
+	userID := uuid.New()
+
+	created, err := service.CreateProject(ctx, &SaveProjectRequest{Name: " Q3 launch ", DefaultPriority: task.PriorityHigh}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Q3 launch", created.Project.Name)
+	assert.Equal(t, task.PriorityHigh, created.Project.DefaultPriority)
+
+	chores, err := service.CreateProject(ctx, &SaveProjectRequest{Name: "Chores"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PriorityMedium, chores.Project.DefaultPriority)
+
+	list, err := service.ListProjects(ctx, false, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Chores", "Q3 launch"}, []string{list.Projects[0].Name, list.Projects[1].Name})
+
+	updated, err := service.UpdateProject(ctx, created.Project.Id, &SaveProjectRequest{Name: "Q3 launch", Description: "Ship it", DefaultPriority: task.PriorityLow}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PriorityLow, updated.Project.DefaultPriority)
+
+	got, err := service.GetProject(ctx, created.Project.Id, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ship it", got.Project.Description)
+
+	assert.NoError(t, service.DeleteProject(ctx, created.Project.Id, userID))
+	_, err = service.GetProject(ctx, created.Project.Id, userID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+}
+
+func TestProjectService_Errors(t *testing.T) {
+	ctx := context.Background()
+	service := NewProjectService(nil, projectrepo.NewMemoryProjectRepository(), taskrepo.NewMemoryTaskRepository(), nil)
+	userID := uuid.New()
+
+	_, err := service.CreateProject(ctx, &SaveProjectRequest{Name: " "}, userID)
+	assert.ErrorIs(t, err, ErrInvalidProject)
+
+	_, err = service.CreateProject(ctx, &SaveProjectRequest{Name: "Urgent", DefaultPriority: "critical"}, userID)
+	assert.ErrorIs(t, err, ErrInvalidProject)
+
+	created, err := service.CreateProject(ctx, &SaveProjectRequest{Name: "Home"}, userID)
+	require.NoError(t, err)
+
+	_, err = service.CreateProject(ctx, &SaveProjectRequest{Name: "Home"}, userID)
+	assert.ErrorIs(t, err, project.ErrNameTaken)
+
+	_, err = service.GetProject(ctx, created.Project.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.ArchiveProject(ctx, created.Project.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.UpdateProject(ctx, uuid.New(), &SaveProjectRequest{Name: "Ghost"}, userID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+}
+
+func TestProjectService_ArchiveWithTasks(t *testing.T) {
+	ctx := context.Background()
+	projects := projectrepo.NewMemoryProjectRepository()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	service := NewProjectService(nil, projects, tasks, nil)
+	taskService := NewTaskService(nil, tasks, nil, projects, nil, nil, nil)
+	userID := uuid.New()
+
+	launch, err := service.CreateProject(ctx, &SaveProjectRequest{Name: "Launch"}, userID)
+	require.NoError(t, err)
+	projectID := launch.Project.Id
+
+	inProject, err := taskService.CreateTask(ctx, &CreateTaskRequest{Title: "Press kit", ProjectID: &projectID}, userID)
+	require.NoError(t, err)
+	_, err = taskService.CreateTask(ctx, &CreateTaskRequest{Title: "Laundry"}, userID)
+	require.NoError(t, err)
+
+	titles := func(req *ListTasksRequest) []string {
+		resp, err := taskService.ListTasks(ctx, req, userID)
+		require.NoError(t, err)
+		var out []string
+		for _, t := range resp.Tasks {
+			out = append(out, t.Title)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"Press kit"}, titles(&ListTasksRequest{ProjectID: &projectID}))
+
+	archived, err := service.ArchiveProject(ctx, projectID, userID)
+	require.NoError(t, err)
+	assert.NotNil(t, archived.Project.ArchivedAt)
+
+	assert.Equal(t, []string{"Laundry"}, titles(&ListTasksRequest{}))
+	assert.Equal(t, []string{"Press kit"}, titles(&ListTasksRequest{Archived: true}))
+
+	list, err := service.ListProjects(ctx, false, userID)
+	require.NoError(t, err)
+	assert.Empty(t, list.Projects)
+
+	_, err = taskService.CreateTask(ctx, &CreateTaskRequest{Title: "Late", ProjectID: &projectID}, userID)
+	assert.ErrorIs(t, err, ErrProjectArchived)
+
+	nilID := uuid.Nil
+	_, err = taskService.UpdateTask(ctx, inProject.Task.Id, &UpdateTaskRequest{Title: "Press kit", ProjectID: &nilID}, userID)
+	assert.ErrorIs(t, err, ErrProjectArchived)
+
+	_, err = service.UnarchiveProject(ctx, projectID, userID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Laundry", "Press kit"}, titles(&ListTasksRequest{}))
+
+	// Deleting an archived project brings its tasks back outside any
+	// project.
+	_, err = service.ArchiveProject(ctx, projectID, userID)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteProject(ctx, projectID, userID))
+	assert.ElementsMatch(t, []string{"Laundry", "Press kit"}, titles(&ListTasksRequest{}))
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"taskhub/config"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/scheduler"
+
+	"go.uber.org/fx"
+)
+
+var ReminderSchedulerModule = fx.Module(
+	"reminder-scheduler",
+	fx.Provide(NewReminderScheduler),
+	fx.Invoke(startReminderScheduler),
+)
+
+// reminderLockKey is the advisory lock that elects the replica firing
+// reminders on each tick.
+const reminderLockKey int64 = 0x7461736b72656d // "taskrem"
+
+type ReminderScheduler struct {
+	*scheduler.Scheduler
+}
+
+func NewReminderScheduler(config *config.Config, logger *logger.Logger, notificationService *NotificationService) *ReminderScheduler {
+	locker := db.NewAdvisoryLocker(db.NewDB(config).GetConnection(), reminderLockKey)
+	return newReminderScheduler(config.Reminder, locker, logger, notificationService)
+}
+
+func newReminderScheduler(cfg *config.Reminder, locker scheduler.Locker, logger *logger.Logger, notificationService *NotificationService) *ReminderScheduler {
+	job := func(ctx context.Context) error {
+		reminders, err := notificationService.CheckAndSendReminders(ctx, cfg.LookAhead, cfg.Thresholds)
+		if err != nil {
+			return err
+		}
+		if len(reminders) > 0 {
+			logger.Info("reminders sent", "count", len(reminders))
+		}
+		return nil
+	}
+
+	return &ReminderScheduler{
+		Scheduler: scheduler.NewScheduler("reminders", cfg.Interval, locker, job, logger),
+	}
+}
+
+func startReminderScheduler(lc fx.Lifecycle, s *ReminderScheduler) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			s.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return s.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+)
+
+var ErrDependencyNotFound = errors.New("dependency not found")
+
+// annotate fills in the derived fields of tasks: subtask progress, the open
+// tasks blocking each one and their labels.
+func (s *TaskService) annotate(ctx context.Context, tasks ...*task.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.Id
+	}
+
+	progress, err := s.taskRepo.SubtaskProgress(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	blockers, err := s.taskRepo.OpenBlockers(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		if p, ok := progress[t.Id]; ok {
+			t.Progress = &p
+		}
+		t.BlockedBy = blockers[t.Id]
+		t.Blocked = len(t.BlockedBy) > 0
+	}
+
+	return s.resolveLabels(ctx, tasks)
+}
+
+// checkUnblocked fails with task.ErrBlocked while a task this one depends on
+// is open, unless force is set.
+func (s *TaskService) checkUnblocked(ctx context.Context, taskID uuid.UUID, force bool) error {
+	if force {
+		return nil
+	}
+
+	blockers, err := s.taskRepo.OpenBlockers(ctx, []uuid.UUID{taskID})
+	if err != nil {
+		return err
+	}
+	if len(blockers[taskID]) > 0 {
+		return task.ErrBlocked
+	}
+
+	return nil
+}
+
+type StartTaskRequest struct {
+	// Force starts the task even while tasks it depends on are open.
+	Force bool `json:"force,omitempty"`
+}
+
+// StartTask moves a task to the first active state its workflow lets it
+// reach, failing with a workflow.TransitionError when there is none. It
+// fails with task.ErrBlocked while a task it depends on is open, unless
+// req.Force is set. A nil req does not force.
+func (s *TaskService) StartTask(ctx context.Context, taskID uuid.UUID, req *StartTaskRequest, userID uuid.UUID) (*TaskResponse, error) {
+	return s.modifyTask(ctx, taskID, userID, func(t *task.Task) error {
+		moved, err := s.moveToCategory(ctx, t, task.CategoryActive, userID)
+		if err != nil || !moved {
+			return err
+		}
+
+		return s.checkUnblocked(ctx, taskID, req != nil && req.Force)
+	})
+}
+
+type AddDependencyRequest struct {
+	BlockedByID uuid.UUID `json:"blocked_by_id"`
+}
+
+// AddDependency records that a task is blocked by another of the user's
+// tasks. It fails with task.ErrInvalidDependency when the blocker is not one
+// of the user's tasks, task.ErrDependencyCycle when the blocker already
+// depends on the task, and task.ErrDependencyExists for a duplicate.
+func (s *TaskService) AddDependency(ctx context.Context, taskID uuid.UUID, req *AddDependencyRequest, userID uuid.UUID) (*TaskResponse, error) {
+	t, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocker, err := s.taskRepo.FindById(ctx, req.BlockedByID)
+	if err != nil {
+		return nil, err
+	}
+	if blocker == nil || blocker.UserID != userID {
+		return nil, task.ErrInvalidDependency
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.taskRepo.AddDependency(ctx, taskID, req.BlockedByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.annotate(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return &TaskResponse{Task: t}, nil
+}
+
+func (s *TaskService) RemoveDependency(ctx context.Context, taskID, blockerID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.RemoveDependency(ctx, taskID, blockerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDependencyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GraphDirection selects which side of a task's dependency graph to walk.
+type GraphDirection int
+
+const (
+	// Upstream follows the tasks a task depends on.
+	Upstream GraphDirection = iota
+	// Downstream follows the tasks that depend on a task.
+	Downstream
+)
+
+// DependencyGraphResponse is the part of the dependency graph reachable from
+// Task in one direction. Tasks lists every other task in it, nearest first.
+type DependencyGraphResponse struct {
+	Task  *task.Task        `json:"task"`
+	Tasks []*task.Task      `json:"tasks"`
+	Edges []task.Dependency `json:"edges"`
+}
+
+func (s *TaskService) GetDependencyGraph(ctx context.Context, taskID uuid.UUID, direction GraphDirection, userID uuid.UUID) (*DependencyGraphResponse, error) {
+	root, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DependencyGraphResponse{Task: root, Tasks: []*task.Task{}, Edges: []task.Dependency{}}
+	seen := map[uuid.UUID]bool{root.Id: true}
+	queue := []uuid.UUID{root.Id}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		var linked []*task.Task
+		if direction == Upstream {
+			linked, err = s.taskRepo.FindBlockers(ctx, id)
+		} else {
+			linked, err = s.taskRepo.FindDependents(ctx, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range linked {
+			if direction == Upstream {
+				resp.Edges = append(resp.Edges, task.Dependency{TaskID: id, BlockedByID: t.Id})
+			} else {
+				resp.Edges = append(resp.Edges, task.Dependency{TaskID: t.Id, BlockedByID: id})
+			}
+			if !seen[t.Id] {
+				seen[t.Id] = true
+				resp.Tasks = append(resp.Tasks, t)
+				queue = append(queue, t.Id)
+			}
+		}
+	}
+
+	if err := s.annotate(ctx, append([]*task.Task{root}, resp.Tasks...)...); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
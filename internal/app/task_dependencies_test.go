@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Dependencies(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	design := createSubtask(t, service, "design", nil, ownerID)
+	build := createSubtask(t, service, "build", nil, ownerID)
+	ship := createSubtask(t, service, "ship", nil, ownerID)
+
+	resp, err := service.AddDependency(ctx, build.Id, &AddDependencyRequest{BlockedByID: design.Id}, ownerID)
+	require.NoError(t, err)
+	assert.True(t, resp.Task.Blocked)
+	assert.Equal(t, []uuid.UUID{design.Id}, resp.Task.BlockedBy)
+
+	_, err = service.AddDependency(ctx, ship.Id, &AddDependencyRequest{BlockedByID: build.Id}, ownerID)
+	require.NoError(t, err)
+
+	_, err = service.AddDependency(ctx, design.Id, &AddDependencyRequest{BlockedByID: ship.Id}, ownerID)
+	assert.ErrorIs(t, err, task.ErrDependencyCycle)
+
+	other := createSubtask(t, service, "other", nil, uuid.New())
+	_, err = service.AddDependency(ctx, build.Id, &AddDependencyRequest{BlockedByID: other.Id}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidDependency)
+
+	upstream, err := service.GetDependencyGraph(ctx, ship.Id, Upstream, ownerID)
+	require.NoError(t, err)
+	require.Len(t, upstream.Tasks, 2)
+	assert.Equal(t, []string{"build", "design"}, []string{upstream.Tasks[0].Title, upstream.Tasks[1].Title})
+	assert.Equal(t, []task.Dependency{
+		{TaskID: ship.Id, BlockedByID: build.Id},
+		{TaskID: build.Id, BlockedByID: design.Id},
+	}, upstream.Edges)
+
+	downstream, err := service.GetDependencyGraph(ctx, design.Id, Downstream, ownerID)
+	require.NoError(t, err)
+	assert.Len(t, downstream.Tasks, 2)
+
+	assert.NoError(t, service.RemoveDependency(ctx, ship.Id, build.Id, ownerID))
+	assert.ErrorIs(t, service.RemoveDependency(ctx, ship.Id, build.Id, ownerID), ErrDependencyNotFound)
+}
+
+func TestTaskService_BlockedTransitions(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	blocker := createSubtask(t, service, "blocker", nil, ownerID)
+	blocked := createSubtask(t, service, "blocked", nil, ownerID)
+	_, err := service.AddDependency(ctx, blocked.Id, &AddDependencyRequest{BlockedByID: blocker.Id}, ownerID)
+	require.NoError(t, err)
+
+	_, err = service.StartTask(ctx, blocked.Id, nil, ownerID)
+	assert.ErrorIs(t, err, task.ErrBlocked)
+
+	_, err = service.CompleteTask(ctx, blocked.Id, nil, ownerID)
+	assert.ErrorIs(t, err, task.ErrBlocked)
+
+	_, err = service.UpdateTask(ctx, blocked.Id, &UpdateTaskRequest{Title: "blocked", Status: task.StatusInProgress}, ownerID)
+	assert.ErrorIs(t, err, task.ErrBlocked)
+
+	started, err := service.StartTask(ctx, blocked.Id, &StartTaskRequest{Force: true}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusInProgress, started.Task.Status)
+
+	_, err = service.CompleteTask(ctx, blocker.Id, nil, ownerID)
+	require.NoError(t, err)
+
+	got, err := service.GetTask(ctx, blocked.Id, ownerID)
+	require.NoError(t, err)
+	assert.False(t, got.Task.Blocked)
+
+	_, err = service.CompleteTask(ctx, blocked.Id, nil, ownerID)
+	assert.NoError(t, err)
+}
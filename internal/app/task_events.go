@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/outbox"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// EventPublisherModule provides the EventPublisher used by TaskService.
+var EventPublisherModule = fx.Module(
+	"event-publisher",
+	fx.Provide(fx.Annotate(NewOutboxEventPublisher, fx.As(new(EventPublisher)))),
+)
+
+const (
+	SubjectTaskCompleted = "task.completed"
+	SubjectTaskDeleted   = "task.deleted"
+	SubjectTaskReopened  = "task.reopened"
+)
+
+// TaskSubjects lists every subject a task mutation can be published on.
+var TaskSubjects = []string{
+	SubjectTaskCreated,
+	SubjectTaskUpdated,
+	SubjectTaskCompleted,
+	SubjectTaskDeleted,
+	SubjectTaskReopened,
+}
+
+// FieldChange is the before and after value of a task field in a
+// task.updated event.
+type FieldChange = task.FieldChange
+
+// EventPublisher records task events. TaskService calls it inside the
+// transaction of the mutation that caused the event, so an error aborts the
+// mutation and an accepted event must not be lost once the transaction
+// commits.
+type EventPublisher interface {
+	PublishTaskEvent(ctx context.Context, event *TaskEvent) error
+}
+
+func newTaskEvent(subject string, t *task.Task) *TaskEvent {
+	return &TaskEvent{
+		ID:        uuid.New(),
+		EventType: subject,
+		TaskID:    t.Id,
+		UserID:    t.UserID,
+		Title:     t.Title,
+		Status:    t.Status,
+		Deadline:  t.Deadline,
+		CreatedAt: time.Now(),
+	}
+}
+
+// OutboxEventPublisher writes task events to the transactional outbox. When
+// called inside the mutation's transaction the event commits or rolls back
+// with it, and the outbox relay publishes it to NATS afterwards.
+type OutboxEventPublisher struct {
+	store outbox.Store
+}
+
+func NewOutboxEventPublisher(store outbox.Store) *OutboxEventPublisher {
+	return &OutboxEventPublisher{store: store}
+}
+
+func (p *OutboxEventPublisher) PublishTaskEvent(ctx context.Context, event *TaskEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.store.Enqueue(ctx, event.ID, event.EventType, data)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskhub/config"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/outbox"
+	"taskhub/pkg/scheduler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxEventPublisher_Enqueues(t *testing.T) {
+	ctx := context.Background()
+	store := outbox.NewMemoryStore()
+	publisher := NewOutboxEventPublisher(store)
+	event := &TaskEvent{ID: uuid.New(), EventType: SubjectTaskCreated, Title: "Queued"}
+
+	assert.NoError(t, publisher.PublishTaskEvent(ctx, event))
+
+	pending, err := store.Pending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, event.ID, pending[0].EventID)
+	assert.Equal(t, SubjectTaskCreated, pending[0].Subject)
+	assert.Contains(t, string(pending[0].Payload), `"title":"Queued"`)
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) PublishTaskEvent(ctx context.Context, event *TaskEvent) error {
+	return errors.New("outbox unavailable")
+}
+
+func TestTaskService_PublishFailureAbortsMutation(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, failingPublisher{}, nil)
+
+	_, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Lost"}, uuid.New())
+	assert.Error(t, err)
+}
+
+func TestOutboxRelay_DrainsBacklog(t *testing.T) {
+	ctx := context.Background()
+	store := outbox.NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		store.Enqueue(ctx, uuid.New(), SubjectTaskCreated, []byte("{}"))
+	}
+
+	var subjects []string
+	relay := newOutboxRelay(&config.Outbox{PollInterval: time.Minute, BatchSize: 2, MaxBackoff: time.Minute},
+		&scheduler.LocalLocker{}, logger.NewLogger(), store,
+		func(ctx context.Context, subject, msgID string, data []byte) error {
+			subjects = append(subjects, subject)
+			return nil
+		})
+
+	ran, err := relay.scheduler.RunOnce(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Len(t, subjects, 5)
+
+	stats, err := relay.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Backlog)
+	assert.Equal(t, int64(5), stats.Watermark)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+)
+
+type TaskHistoryResponse struct {
+	History []*task.Change `json:"history"`
+}
+
+// GetTaskHistory returns the changes made to one of the user's tasks, oldest
+// first.
+func (s *TaskService) GetTaskHistory(ctx context.Context, taskID uuid.UUID, userID uuid.UUID) (*TaskHistoryResponse, error) {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.taskRepo.FindHistory(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		history = []*task.Change{}
+	}
+
+	return &TaskHistoryResponse{History: history}, nil
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_History(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Report", Priority: task.PriorityLow}, ownerID)
+	require.NoError(t, err)
+	taskID := created.Task.Id
+
+	_, err = service.UpdateTask(ctx, taskID, &UpdateTaskRequest{Title: "Report", Priority: task.PriorityHigh}, ownerID)
+	require.NoError(t, err)
+	_, err = service.CompleteTask(ctx, taskID, &CompleteTaskRequest{}, ownerID)
+	require.NoError(t, err)
+
+	resp, err := service.GetTaskHistory(ctx, taskID, ownerID)
+	require.NoError(t, err)
+	require.Len(t, resp.History, 3)
+	assert.Equal(t, task.ActionCreated, resp.History[0].Action)
+	assert.Equal(t, "priority", resp.History[1].Field)
+	assert.JSONEq(t, `"low"`, string(resp.History[1].Old))
+	assert.JSONEq(t, `"high"`, string(resp.History[1].New))
+	assert.Equal(t, "status", resp.History[2].Field)
+	assert.Equal(t, ownerID, resp.History[2].ActorID)
+
+	_, err = service.GetTaskHistory(ctx, taskID, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	_, err = service.GetTaskHistory(ctx, uuid.New(), ownerID)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
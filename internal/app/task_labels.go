@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+)
+
+// checkLabels returns ids sorted and without duplicates, failing with
+// ErrUnknownLabel unless each is one of the user's labels.
+func (s *TaskService) checkLabels(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if s.labelRepo == nil {
+		return nil, ErrUnknownLabel
+	}
+
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	ids = slices.Compact(ids)
+
+	labels, err := s.labelRepo.FindByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) != len(ids) {
+		return nil, ErrUnknownLabel
+	}
+	for _, l := range labels {
+		if l.UserID != userID {
+			return nil, ErrUnknownLabel
+		}
+	}
+
+	return ids, nil
+}
+
+// resolveLabels sets the Labels of tasks from their LabelIDs, ordered by
+// name. Labels that no longer exist are left out.
+func (s *TaskService) resolveLabels(ctx context.Context, tasks []*task.Task) error {
+	var ids []uuid.UUID
+	for _, t := range tasks {
+		ids = append(ids, t.LabelIDs...)
+	}
+	if len(ids) == 0 || s.labelRepo == nil {
+		return nil
+	}
+
+	labels, err := s.labelRepo.FindByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		t.Labels = nil
+		for _, l := range labels {
+			if slices.Contains(t.LabelIDs, l.Id) {
+				t.Labels = append(t.Labels, l)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	labelrepo "taskhub/internal/domains/label/repo"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Labels(t *testing.T) {
+	ctx := context.Background()
+	labels := labelrepo.NewMemoryLabelRepository()
+	labelService := NewLabelService(nil, labels)
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), labels, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	work, err := labelService.CreateLabel(ctx, &SaveLabelRequest{Name: "work"}, ownerID)
+	require.NoError(t, err)
+	urgent, err := labelService.CreateLabel(ctx, &SaveLabelRequest{Name: "urgent"}, ownerID)
+	require.NoError(t, err)
+	foreign, err := labelService.CreateLabel(ctx, &SaveLabelRequest{Name: "theirs"}, uuid.New())
+	require.NoError(t, err)
+	workID, urgentID := work.Label.Id, urgent.Label.Id
+
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "Sneaky", LabelIDs: []uuid.UUID{foreign.Label.Id}}, ownerID)
+	assert.ErrorIs(t, err, ErrUnknownLabel)
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "Ghost", LabelIDs: []uuid.UUID{uuid.New()}}, ownerID)
+	assert.ErrorIs(t, err, ErrUnknownLabel)
+
+	both, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Both", LabelIDs: []uuid.UUID{workID, urgentID, workID}}, ownerID)
+	require.NoError(t, err)
+	require.Len(t, both.Task.Labels, 2)
+	assert.Equal(t, "urgent", both.Task.Labels[0].Name)
+	assert.Equal(t, "work", both.Task.Labels[1].Name)
+
+	onlyWork, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Work", LabelIDs: []uuid.UUID{workID}}, ownerID)
+	require.NoError(t, err)
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "None"}, ownerID)
+	require.NoError(t, err)
+
+	titles := func(req *ListTasksRequest) []string {
+		resp, err := service.ListTasks(ctx, req, ownerID)
+		require.NoError(t, err)
+		var out []string
+		for _, t := range resp.Tasks {
+			out = append(out, t.Title)
+		}
+		return out
+	}
+	assert.ElementsMatch(t, []string{"Both", "Work"}, titles(&ListTasksRequest{LabelsAny: []uuid.UUID{workID, urgentID}}))
+	assert.Equal(t, []string{"Both"}, titles(&ListTasksRequest{LabelsAll: []uuid.UUID{workID, urgentID}}))
+
+	publisher.events = nil
+	detached := []uuid.UUID{}
+	updated, err := service.UpdateTask(ctx, onlyWork.Task.Id, &UpdateTaskRequest{Title: "Work", LabelIDs: &detached}, ownerID)
+	require.NoError(t, err)
+	assert.Empty(t, updated.Task.Labels)
+	require.Len(t, publisher.events, 1)
+	assert.Contains(t, publisher.events[0].Changes, "labels")
+	assert.Equal(t, []string{"Both"}, titles(&ListTasksRequest{LabelsAny: []uuid.UUID{workID}}))
+
+	// Leaving LabelIDs out of an update keeps the labels.
+	kept, err := service.UpdateTask(ctx, both.Task.Id, &UpdateTaskRequest{Title: "Both again"}, ownerID)
+	require.NoError(t, err)
+	assert.Len(t, kept.Task.Labels, 2)
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"taskhub/internal/domains/project"
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+)
+
+// checkProject returns the project tasks are being put in, failing with
+// ErrUnknownProject unless it is one of the user's projects and with
+// ErrProjectArchived while it is archived.
+func (s *TaskService) checkProject(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*project.Project, error) {
+	if s.projectRepo == nil {
+		return nil, ErrUnknownProject
+	}
+
+	p, err := s.projectRepo.FindById(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.UserID != userID {
+		return nil, ErrUnknownProject
+	}
+	if p.ArchivedAt != nil {
+		return nil, ErrProjectArchived
+	}
+
+	return p, nil
+}
+
+// moveToProject sets t's project, or takes t out of its project when
+// projectID is uuid.Nil. Archived tasks stay where they are until their
+// project is unarchived.
+func (s *TaskService) moveToProject(ctx context.Context, t *task.Task, projectID uuid.UUID, userID uuid.UUID) error {
+	current := uuid.Nil
+	if t.ProjectID != nil {
+		current = *t.ProjectID
+	}
+	if projectID == current {
+		return nil
+	}
+	if t.ArchivedAt != nil {
+		return ErrProjectArchived
+	}
+
+	if projectID == uuid.Nil {
+		t.ProjectID = nil
+		return nil
+	}
+	if _, err := s.checkProject(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	t.ProjectID = &projectID
+	return nil
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	projectrepo "taskhub/internal/domains/project/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Projects(t *testing.T) {
+	ctx := context.Background()
+	projects := projectrepo.NewMemoryProjectRepository()
+	tasks := taskrepo.NewMemoryTaskRepository()
+	projectService := NewProjectService(nil, projects, tasks, nil)
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, tasks, nil, projects, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	urgent, err := projectService.CreateProject(ctx, &SaveProjectRequest{Name: "Urgent", DefaultPriority: task.PriorityHigh}, ownerID)
+	require.NoError(t, err)
+	foreign, err := projectService.CreateProject(ctx, &SaveProjectRequest{Name: "Theirs"}, uuid.New())
+	require.NoError(t, err)
+	urgentID, foreignID := urgent.Project.Id, foreign.Project.Id
+
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "Sneaky", ProjectID: &foreignID}, ownerID)
+	assert.ErrorIs(t, err, ErrUnknownProject)
+
+	plain, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Plain"}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PriorityMedium, plain.Task.Priority)
+
+	defaulted, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Fire", ProjectID: &urgentID}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PriorityHigh, defaulted.Task.Priority)
+	assert.Equal(t, urgentID, *defaulted.Task.ProjectID)
+
+	explicit, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Smoke", Priority: task.PriorityLow, ProjectID: &urgentID}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PriorityLow, explicit.Task.Priority)
+
+	// Subtasks go in their parent's project.
+	child := createSubtask(t, service, "Extinguisher", &defaulted.Task.Id, ownerID)
+	require.NotNil(t, child.ProjectID)
+	assert.Equal(t, urgentID, *child.ProjectID)
+	assert.Equal(t, task.PriorityHigh, child.Priority)
+
+	publisher.events = nil
+	moved, err := service.UpdateTask(ctx, plain.Task.Id, &UpdateTaskRequest{Title: "Plain", Priority: task.PriorityMedium, ProjectID: &urgentID}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, urgentID, *moved.Task.ProjectID)
+	require.Len(t, publisher.events, 1)
+	assert.Contains(t, publisher.events[0].Changes, "project_id")
+
+	_, err = service.UpdateTask(ctx, plain.Task.Id, &UpdateTaskRequest{Title: "Plain", ProjectID: &foreignID}, ownerID)
+	assert.ErrorIs(t, err, ErrUnknownProject)
+
+	nilID := uuid.Nil
+	out, err := service.UpdateTask(ctx, plain.Task.Id, &UpdateTaskRequest{Title: "Plain", ProjectID: &nilID}, ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, out.Task.ProjectID)
+
+	// Leaving ProjectID out of an update keeps the project.
+	kept, err := service.UpdateTask(ctx, explicit.Task.Id, &UpdateTaskRequest{Title: "Smoke"}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, urgentID, *kept.Task.ProjectID)
+}
@@ -0,0 +1,203 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidScope = errors.New("scope must be this or future")
+
+// EditScope says which occurrences of a recurring task an update applies to.
+type EditScope string
+
+const (
+	// ScopeThis edits only the occurrence being updated.
+	ScopeThis EditScope = "this"
+	// ScopeFuture edits the occurrence and every later one, and makes its
+	// fields the template for occurrences still to be created.
+	ScopeFuture EditScope = "future"
+)
+
+func seriesTemplate(t *task.Task) task.SeriesTemplate {
+	return task.SeriesTemplate{
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		Deadline:    *t.Deadline,
+	}
+}
+
+// startSeries makes t the first occurrence of a new series. t must have a
+// deadline.
+func startSeries(t *task.Task, rule *task.RRule) *task.Recurrence {
+	return &task.Recurrence{
+		Rule:       rule.String(),
+		SeriesID:   t.Id,
+		Occurrence: 1,
+		Template:   seriesTemplate(t),
+	}
+}
+
+// applyRecurrence updates t.Recurrence for an update already applied to t.
+// A rule change always applies to the whole series; with ScopeFuture the
+// task's new fields also become the series template. It never edits the
+// existing Recurrence in place, so the caller's copy of the task from before
+// the update stays intact.
+func applyRecurrence(t *task.Task, rule *string, scope EditScope) error {
+	rec := t.Recurrence
+	if rule != nil {
+		if *rule == "" {
+			t.Recurrence = nil
+			return nil
+		}
+
+		parsed, err := task.ParseRRule(*rule)
+		if err != nil {
+			return err
+		}
+		if t.Deadline == nil {
+			return task.ErrRecurrenceDeadline
+		}
+		if rec == nil {
+			t.Recurrence = startSeries(t, parsed)
+			return nil
+		}
+
+		changed := *rec
+		changed.Rule = parsed.String()
+		rec = &changed
+	}
+
+	if rec != nil && scope == ScopeFuture {
+		if t.Deadline == nil {
+			return task.ErrRecurrenceDeadline
+		}
+		changed := *rec
+		changed.Template = seriesTemplate(t)
+		rec = &changed
+	}
+
+	t.Recurrence = rec
+	return nil
+}
+
+// updateLaterOccurrences carries a ScopeFuture edit of t over to the open
+// occurrences after it in series, inside the caller's transaction. Their
+// deadlines stay as scheduled.
+func (s *TaskService) updateLaterOccurrences(ctx context.Context, t *task.Task, series *task.Recurrence, userID uuid.UUID) error {
+	occurrences, err := s.taskRepo.FindAll(ctx, &task.TaskFilter{SeriesID: &series.SeriesID})
+	if err != nil {
+		return err
+	}
+
+	for _, o := range occurrences {
+		if o.Id == t.Id || o.Recurrence.Occurrence < series.Occurrence || o.Closed() {
+			continue
+		}
+
+		before := *o
+		o.Title = t.Title
+		o.Description = t.Description
+		o.Priority = t.Priority
+		o.UpdateAt = t.UpdateAt
+		o.UpdateBy = &userID
+		if t.Recurrence == nil {
+			o.Recurrence = nil
+		} else {
+			changed := *o.Recurrence
+			changed.Rule = t.Recurrence.Rule
+			changed.Template.Title = t.Title
+			changed.Template.Description = t.Description
+			changed.Template.Priority = t.Priority
+			o.Recurrence = &changed
+		}
+
+		if _, err := s.taskRepo.UpdateById(ctx, o.Id, o); err != nil {
+			return err
+		}
+		if err := s.publish(ctx, updateEvents(&before, o)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextOccurrence creates the occurrence that follows a completed task in its
+// series, with the same labels, parent and project, inside the caller's
+// transaction. It starts in the first state of its workflow. It returns nil when the task does not repeat, its series has
+// ended, or a later occurrence already exists, as when an occurrence is
+// reopened and completed again.
+func (s *TaskService) nextOccurrence(ctx context.Context, t *task.Task) (*task.Task, error) {
+	rec := t.Recurrence
+	if rec == nil {
+		return nil, nil
+	}
+
+	rule, err := task.ParseRRule(rec.Rule)
+	if err != nil {
+		return nil, err
+	}
+	due, ok := rule.Next(rec.Template.Deadline, rec.Occurrence)
+	if !ok {
+		return nil, nil
+	}
+
+	occurrences, err := s.taskRepo.FindAll(ctx, &task.TaskFilter{SeriesID: &rec.SeriesID})
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range occurrences {
+		if o.Recurrence.Occurrence > rec.Occurrence {
+			return nil, nil
+		}
+	}
+
+	var checklist []task.ChecklistItem
+	for _, item := range t.Checklist {
+		checklist = append(checklist, task.ChecklistItem{ID: uuid.New(), Text: item.Text})
+	}
+
+	w, err := s.workflowOf(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	initial := w.Initial()
+
+	template := rec.Template
+	template.Deadline = due
+	next := task.NewTask(ctx, &task.Task{
+		Title:          template.Title,
+		Description:    template.Description,
+		Status:         initial.Name,
+		StatusCategory: initial.Category,
+		Priority:       template.Priority,
+		Deadline:       &due,
+		ParentID:       t.ParentID,
+		ProjectID:      t.ProjectID,
+		Checklist:      checklist,
+		LabelIDs:       t.LabelIDs,
+		Recurrence: &task.Recurrence{
+			Rule:       rec.Rule,
+			SeriesID:   rec.SeriesID,
+			Occurrence: rec.Occurrence + 1,
+			Template:   template,
+		},
+	}, t.UserID)
+
+	created, err := s.taskRepo.Create(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publish(ctx, newTaskEvent(SubjectTaskCreated, created)); err != nil {
+		return nil, err
+	}
+	if err := s.resolveLabels(ctx, []*task.Task{created}); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_RecurringCompletion(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+	deadline := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	_, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Chores", Recurrence: "FREQ=WEEKLY"}, ownerID)
+	assert.ErrorIs(t, err, task.ErrRecurrenceDeadline)
+
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "Chores", Deadline: &deadline, Recurrence: "FREQ=SOMETIMES"}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidRRule)
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{
+		Title:      "Chores",
+		Deadline:   &deadline,
+		Checklist:  []string{"Dishes"},
+		Recurrence: "freq=weekly;count=2",
+	}, ownerID)
+	require.NoError(t, err)
+	require.NotNil(t, created.Task.Recurrence)
+	assert.Equal(t, "FREQ=WEEKLY;COUNT=2", created.Task.Recurrence.Rule)
+	assert.Equal(t, created.Task.Id, created.Task.Recurrence.SeriesID)
+
+	_, err = service.UpdateChecklistItem(ctx, created.Task.Id, created.Task.Checklist[0].ID, &UpdateChecklistItemRequest{Done: boolPtr(true)}, ownerID)
+	require.NoError(t, err)
+
+	publisher.events = nil
+	completed, err := service.CompleteTask(ctx, created.Task.Id, nil, ownerID)
+	require.NoError(t, err)
+	next := completed.Next
+	require.NotNil(t, next)
+	assert.Equal(t, []string{SubjectTaskCompleted, SubjectTaskCreated}, publisher.subjects())
+	assert.Equal(t, "Chores", next.Title)
+	assert.Equal(t, task.StatusTodo, next.Status)
+	assert.Equal(t, deadline.AddDate(0, 0, 7), *next.Deadline)
+	assert.Equal(t, 2, next.Recurrence.Occurrence)
+	assert.False(t, next.Checklist[0].Done)
+
+	// Reopening and completing again does not create a second next occurrence.
+	_, err = service.UpdateTask(ctx, created.Task.Id, &UpdateTaskRequest{Title: "Chores", Status: task.StatusTodo, Deadline: &deadline}, ownerID)
+	require.NoError(t, err)
+	again, err := service.CompleteTask(ctx, created.Task.Id, nil, ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, again.Next)
+
+	// COUNT=2 ends the series after the second occurrence.
+	last, err := service.UpdateTask(ctx, next.Id, &UpdateTaskRequest{Title: "Chores", Status: task.StatusDone, Deadline: next.Deadline}, ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, last.Next)
+}
+
+func TestTaskService_RecurringEditScope(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+	deadline := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Standup notes", Deadline: &deadline, Recurrence: "FREQ=DAILY"}, ownerID)
+	require.NoError(t, err)
+	id := created.Task.Id
+
+	_, err = service.UpdateTask(ctx, id, &UpdateTaskRequest{Title: "x", Deadline: &deadline, Scope: "everything"}, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	// Editing this occurrence moves it without shifting the series.
+	moved := deadline.Add(3 * time.Hour)
+	_, err = service.UpdateTask(ctx, id, &UpdateTaskRequest{Title: "Standup notes (late)", Status: task.StatusTodo, Deadline: &moved}, ownerID)
+	require.NoError(t, err)
+	completed, err := service.CompleteTask(ctx, id, nil, ownerID)
+	require.NoError(t, err)
+	second := completed.Next
+	require.NotNil(t, second)
+	assert.Equal(t, "Standup notes", second.Title)
+	assert.Equal(t, deadline.AddDate(0, 0, 1), *second.Deadline)
+
+	// Editing all future occurrences changes the template and the rule.
+	rule := "FREQ=WEEKLY"
+	later := second.Deadline.Add(time.Hour)
+	updated, err := service.UpdateTask(ctx, second.Id, &UpdateTaskRequest{
+		Title:      "Weekly notes",
+		Status:     task.StatusDone,
+		Priority:   task.PriorityHigh,
+		Deadline:   &later,
+		Recurrence: &rule,
+		Scope:      ScopeFuture,
+	}, ownerID)
+	require.NoError(t, err)
+	third := updated.Next
+	require.NotNil(t, third)
+	assert.Equal(t, "Weekly notes", third.Title)
+	assert.Equal(t, task.PriorityHigh, third.Priority)
+	assert.Equal(t, later.AddDate(0, 0, 7), *third.Deadline)
+	assert.Equal(t, 3, third.Recurrence.Occurrence)
+
+	// A future edit of an earlier occurrence reaches the open later ones.
+	_, err = service.UpdateTask(ctx, second.Id, &UpdateTaskRequest{Title: "Notes", Status: task.StatusTodo, Deadline: &later, Scope: ScopeFuture}, ownerID)
+	require.NoError(t, err)
+	got, err := service.GetTask(ctx, third.Id, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, "Notes", got.Task.Title)
+	assert.Equal(t, "Notes", got.Task.Recurrence.Template.Title)
+	assert.Equal(t, later.AddDate(0, 0, 7), *got.Task.Deadline)
+
+	stop := ""
+	stopped, err := service.UpdateTask(ctx, third.Id, &UpdateTaskRequest{Title: "Weekly notes", Deadline: third.Deadline, Recurrence: &stop}, ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, stopped.Task.Recurrence)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"taskhub/internal/domains/label"
+	"taskhub/internal/domains/project"
 	"taskhub/internal/domains/task"
-	"taskhub/internal/domains/task/repo"
+	"taskhub/internal/domains/workflow"
+	"taskhub/pkg/db"
 	"taskhub/pkg/logger"
 	"time"
 
@@ -24,15 +27,45 @@ var (
 )
 
 type TaskService struct {
-	logger   *logger.Logger
-	taskRepo *repo.TaskRepository
+	logger       *logger.Logger
+	taskRepo     task.TaskStore
+	labelRepo    label.LabelStore
+	projectRepo  project.ProjectStore
+	workflowRepo workflow.WorkflowStore
+	publisher    EventPublisher
+	tx           db.Transactor
 }
 
-func NewTaskService(logger *logger.Logger, taskRepo *repo.TaskRepository) *TaskService {
+func NewTaskService(logger *logger.Logger, taskRepo task.TaskStore, labelRepo label.LabelStore, projectRepo project.ProjectStore, workflowRepo workflow.WorkflowStore, publisher EventPublisher, tx db.Transactor) *TaskService {
+	if tx == nil {
+		tx = db.NoTx{}
+	}
+
 	return &TaskService{
-		logger:   logger,
-		taskRepo: taskRepo,
+		logger:       logger,
+		taskRepo:     taskRepo,
+		labelRepo:    labelRepo,
+		projectRepo:  projectRepo,
+		workflowRepo: workflowRepo,
+		publisher:    publisher,
+		tx:           tx,
+	}
+}
+
+// publish records events in the caller's transaction so they are stored
+// atomically with the mutation that caused them.
+func (s *TaskService) publish(ctx context.Context, events ...*TaskEvent) error {
+	if s.publisher == nil {
+		return nil
 	}
+
+	for _, event := range events {
+		if err := s.publisher.PublishTaskEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type CreateTaskRequest struct {
@@ -40,36 +73,165 @@ type CreateTaskRequest struct {
 	Description string            `json:"description"`
 	Priority    task.TaskPriority `json:"priority"`
 	Deadline    *time.Time        `json:"deadline,omitempty"`
+	// ParentID makes the task a subtask of one of the user's tasks.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	// Checklist holds the text of the task's initial checklist items.
+	Checklist []string `json:"checklist,omitempty"`
+	// Recurrence is an RRULE that makes the task the first occurrence of a
+	// recurring series. It needs a deadline.
+	Recurrence string `json:"recurrence,omitempty"`
+	// LabelIDs attaches some of the user's labels.
+	LabelIDs []uuid.UUID `json:"label_ids,omitempty"`
+	// ProjectID puts the task in one of the user's projects. Subtasks
+	// default to their parent's project.
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
 }
 
 type TaskResponse struct {
 	Task *task.Task `json:"task"`
+	// Next is the occurrence created by completing a recurring task.
+	Next *task.Task `json:"next,omitempty"`
 }
 
+// CreateTask creates a task, or a subtask when req.ParentID is set. It fails
+// with task.ErrInvalidParent or task.ErrMaxDepth when the parent cannot take
+// the subtask, with task.ErrInvalidChecklistItem on a bad checklist, and with
+// task.ErrInvalidRRule or task.ErrRecurrenceDeadline on a bad recurrence. A
+// label or project that is not one of the user's fails with ErrUnknownLabel
+// or ErrUnknownProject, and an archived project with ErrProjectArchived.
+// Without a priority, the task gets its project's default priority, or
+// medium outside a project. It starts in the first state of its workflow.
 func (s *TaskService) CreateTask(ctx context.Context, req *CreateTaskRequest, userID uuid.UUID) (*TaskResponse, error) {
+	var checklist []task.ChecklistItem
+	for _, text := range req.Checklist {
+		item, err := task.NewChecklistItem(text)
+		if err != nil {
+			return nil, err
+		}
+		checklist = append(checklist, item)
+	}
+
+	if req.ParentID != nil {
+		if err := s.checkParent(ctx, uuid.Nil, *req.ParentID, 0, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	labelIDs, err := s.checkLabels(ctx, req.LabelIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := req.ProjectID
+	if projectID == nil && req.ParentID != nil {
+		parent, err := s.taskRepo.FindById(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		projectID = parent.ProjectID
+	}
+
+	priority := req.Priority
+	if projectID != nil {
+		p, err := s.checkProject(ctx, *projectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if priority == "" {
+			priority = p.DefaultPriority
+		}
+	}
+	if priority == "" {
+		priority = task.PriorityMedium
+	}
+
+	w, err := resolveWorkflow(ctx, s.workflowRepo, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	initial := w.Initial()
+
+	var rule *task.RRule
+	if req.Recurrence != "" {
+		if rule, err = task.ParseRRule(req.Recurrence); err != nil {
+			return nil, err
+		}
+		if req.Deadline == nil {
+			return nil, task.ErrRecurrenceDeadline
+		}
+	}
+
 	newTask := task.NewTask(ctx, &task.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		Priority:    req.Priority,
-		Deadline:    req.Deadline,
+		Title:          req.Title,
+		Description:    req.Description,
+		Status:         initial.Name,
+		StatusCategory: initial.Category,
+		Priority:       priority,
+		Deadline:       req.Deadline,
+		ParentID:       req.ParentID,
+		ProjectID:      projectID,
+		Checklist:      checklist,
+		LabelIDs:       labelIDs,
 	}, userID)
+	if rule != nil {
+		newTask.Recurrence = startSeries(newTask, rule)
+	}
+
+	var createdTask *task.Task
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdTask, err = s.taskRepo.Create(ctx, newTask)
+		if err != nil {
+			return err
+		}
 
-	createdTask, err := s.taskRepo.Create(ctx, newTask)
+		return s.publish(ctx, newTaskEvent(SubjectTaskCreated, createdTask))
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.resolveLabels(ctx, []*task.Task{createdTask}); err != nil {
+		return nil, err
+	}
+
 	return &TaskResponse{Task: createdTask}, nil
 }
 
 type UpdateTaskRequest struct {
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Status      task.TaskStatus   `json:"status"`
-	Priority    task.TaskPriority `json:"priority"`
-	Deadline    *time.Time        `json:"deadline,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Status moves the task to another state of its workflow; empty keeps
+	// the current one.
+	Status   task.TaskStatus   `json:"status,omitempty"`
+	Priority task.TaskPriority `json:"priority"`
+	Deadline *time.Time        `json:"deadline,omitempty"`
+	// Force completes a task along with its open subtasks and lets a
+	// blocked task start or finish. Without it, those status changes fail.
+	Force bool `json:"force,omitempty"`
+	// Recurrence replaces the task's RRULE when set; an empty rule stops
+	// the task repeating.
+	Recurrence *string `json:"recurrence,omitempty"`
+	// Scope picks the occurrences of a recurring task to edit and defaults
+	// to ScopeThis.
+	Scope EditScope `json:"scope,omitempty"`
+	// LabelIDs replaces the attached labels when set; an empty list
+	// detaches them all.
+	LabelIDs *[]uuid.UUID `json:"label_ids,omitempty"`
+	// ProjectID moves the task to another of the user's projects when set;
+	// uuid.Nil takes it out of its project.
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
 }
 
+// UpdateTask replaces the task's editable fields. A status the task's
+// workflow does not have fails with workflow.ErrUnknownStatus, and a move it
+// does not allow with a workflow.TransitionError. Unless req.Force is set,
+// moving a blocked task to an active or closed status fails with
+// task.ErrBlocked, and closing a task while it has open subtasks fails with
+// task.ErrOpenSubtasks; with it, the subtasks are closed too. Closing an
+// occurrence of a recurring task creates the next occurrence. Moving a task
+// into or out of an archived project fails with ErrProjectArchived; the
+// status is then checked against the new project's workflow.
 func (s *TaskService) UpdateTask(ctx context.Context, taskID uuid.UUID, req *UpdateTaskRequest, userID uuid.UUID) (*TaskResponse, error) {
 	existingTask, err := s.taskRepo.FindById(ctx, taskID)
 	if err != nil {
@@ -83,21 +245,104 @@ func (s *TaskService) UpdateTask(ctx context.Context, taskID uuid.UUID, req *Upd
 		return nil, ErrUnauthorized
 	}
 
+	scope := req.Scope
+	if scope == "" {
+		scope = ScopeThis
+	}
+	if scope != ScopeThis && scope != ScopeFuture {
+		return nil, ErrInvalidScope
+	}
+
+	before := *existingTask
+
+	if req.LabelIDs != nil {
+		ids, err := s.checkLabels(ctx, *req.LabelIDs, userID)
+		if err != nil {
+			return nil, err
+		}
+		existingTask.LabelIDs = ids
+	}
+
+	if req.ProjectID != nil {
+		if err := s.moveToProject(ctx, existingTask, *req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	state := workflow.State{Name: before.Status, Category: before.StatusCategory}
+	if req.Status != "" && req.Status != before.Status {
+		w, err := s.workflowOf(ctx, existingTask)
+		if err != nil {
+			return nil, err
+		}
+		if state, err = w.Transition(before.Status, req.Status); err != nil {
+			return nil, err
+		}
+
+		if state.Category != task.CategoryOpen {
+			if err := s.checkUnblocked(ctx, taskID, req.Force); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var open []*task.Task
+	if state.Category == task.CategoryClosed && !before.Closed() {
+		open, err = s.openSubtasks(ctx, taskID, req.Force)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
 	existingTask.Title = req.Title
 	existingTask.Description = req.Description
-	existingTask.Status = req.Status
+	existingTask.Status = state.Name
+	existingTask.StatusCategory = state.Category
 	existingTask.Priority = req.Priority
 	existingTask.Deadline = req.Deadline
 	existingTask.UpdateAt = &now
 	existingTask.UpdateBy = &userID
+	if err := applyRecurrence(existingTask, req.Recurrence, scope); err != nil {
+		return nil, err
+	}
+
+	var updatedTask, next *task.Task
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updatedTask, err = s.taskRepo.UpdateById(ctx, taskID, existingTask)
+		if err != nil {
+			return err
+		}
+
+		if err := s.completeSubtasks(ctx, open, userID); err != nil {
+			return err
+		}
+
+		if scope == ScopeFuture && before.Recurrence != nil {
+			if err := s.updateLaterOccurrences(ctx, updatedTask, before.Recurrence, userID); err != nil {
+				return err
+			}
+		}
 
-	updatedTask, err := s.taskRepo.UpdateById(ctx, taskID, existingTask)
+		if err := s.publish(ctx, updateEvents(&before, updatedTask)...); err != nil {
+			return err
+		}
+
+		if !before.Closed() && updatedTask.Closed() {
+			next, err = s.nextOccurrence(ctx, updatedTask)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &TaskResponse{Task: updatedTask}, nil
+	if err := s.annotate(ctx, updatedTask); err != nil {
+		return nil, err
+	}
+
+	return &TaskResponse{Task: updatedTask, Next: next}, nil
 }
 
 func (s *TaskService) GetTask(ctx context.Context, taskID uuid.UUID, userID uuid.UUID) (*TaskResponse, error) {
@@ -113,6 +358,10 @@ func (s *TaskService) GetTask(ctx context.Context, taskID uuid.UUID, userID uuid
 		return nil, ErrUnauthorized
 	}
 
+	if err := s.annotate(ctx, t); err != nil {
+		return nil, err
+	}
+
 	return &TaskResponse{Task: t}, nil
 }
 
@@ -120,42 +369,114 @@ type ListTasksRequest struct {
 	Status   *task.TaskStatus   `json:"status,omitempty"`
 	Priority *task.TaskPriority `json:"priority,omitempty"`
 	Deadline *time.Time         `json:"deadline,omitempty"`
-	Search   string             `json:"search,omitempty"`
+	// Search is a full-text query: words are ANDed, "quoted text" is a
+	// phrase, -word excludes and OR separates alternatives.
+	Search string `json:"search,omitempty"`
+	// Query is a task query as parsed by task.ParseQuery. Its free text is
+	// added to Search.
+	Query string `json:"query,omitempty"`
+	// Sort is one of created_at, deadline, priority, title or, with a
+	// search, relevance; Order is asc or desc. They default to relevance
+	// desc when searching and created_at desc otherwise.
+	Sort  string `json:"sort,omitempty"`
+	Order string `json:"order,omitempty"`
+	// Limit caps the page size at task.MaxPageLimit and defaults to
+	// task.DefaultPageLimit.
+	Limit int `json:"limit,omitempty"`
+	// Cursor is the next_cursor of the previous page.
+	Cursor string `json:"cursor,omitempty"`
+	// ParentID lists the subtasks of a task; TopLevel lists only tasks that
+	// are not subtasks.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	TopLevel bool       `json:"top_level,omitempty"`
+	// LabelsAny lists tasks with any of the labels, LabelsAll tasks with
+	// all of them.
+	LabelsAny []uuid.UUID `json:"labels_any,omitempty"`
+	LabelsAll []uuid.UUID `json:"labels_all,omitempty"`
+	// ProjectID lists the tasks in a project. Archived lists archived tasks
+	// instead of live ones.
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
+	Archived  bool       `json:"archived,omitempty"`
 }
 
 type ListTasksResponse struct {
-	Tasks []*task.Task `json:"tasks"`
+	Tasks      []*task.Task `json:"tasks"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      int          `json:"total"`
 }
 
+// ListTasks returns one page of the user's tasks. It fails with
+// task.ErrInvalidSort, task.ErrInvalidCursor or task.ErrInvalidQuery on a
+// malformed request.
 func (s *TaskService) ListTasks(ctx context.Context, req *ListTasksRequest, userID uuid.UUID) (*ListTasksResponse, error) {
-	filter := &task.TaskFilter{
-		Status:   req.Status,
-		Priority: req.Priority,
-		Deadline: req.Deadline,
-		UserID:   &userID,
+	query, err := task.ParseQuery(req.Query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	search := strings.TrimSpace(req.Search + " " + query.Text)
+
+	field := req.Sort
+	if field == "" && search != "" {
+		field = string(task.SortRelevance)
 	}
 
-	tasks, err := s.taskRepo.FindAll(ctx, filter)
+	sort, err := task.ParseSort(field, req.Order)
 	if err != nil {
 		return nil, err
 	}
+	if sort.Field == task.SortRelevance && search == "" {
+		return nil, task.ErrInvalidSort
+	}
 
-	// Apply search filter client-side for now
-	if req.Search != "" {
-		var filteredTasks []*task.Task
-		searchLower := strings.ToLower(req.Search)
-		for _, t := range tasks {
-			if strings.Contains(strings.ToLower(t.Title), searchLower) ||
-				strings.Contains(strings.ToLower(t.Description), searchLower) {
-				filteredTasks = append(filteredTasks, t)
-			}
+	page := &task.PageRequest{Sort: sort, Limit: req.Limit}
+	if page.Limit <= 0 {
+		page.Limit = task.DefaultPageLimit
+	}
+	page.Limit = min(page.Limit, task.MaxPageLimit)
+
+	if req.Cursor != "" {
+		page.After, err = task.DecodeCursor(req.Cursor, sort)
+		if err != nil {
+			return nil, err
 		}
-		tasks = filteredTasks
 	}
 
-	return &ListTasksResponse{Tasks: tasks}, nil
+	filter := &task.TaskFilter{
+		Status:     req.Status,
+		Priority:   req.Priority,
+		Deadline:   req.Deadline,
+		UserID:     &userID,
+		Search:     search,
+		Conditions: query.Conditions,
+		ParentID:   req.ParentID,
+		TopLevel:   req.TopLevel,
+		LabelsAny:  req.LabelsAny,
+		LabelsAll:  req.LabelsAll,
+		ProjectID:  req.ProjectID,
+		Archived:   req.Archived,
+	}
+
+	result, err := s.taskRepo.FindPage(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.annotate(ctx, result.Tasks...); err != nil {
+		return nil, err
+	}
+
+	resp := &ListTasksResponse{Tasks: result.Tasks, Total: result.Total}
+	if resp.Tasks == nil {
+		resp.Tasks = []*task.Task{}
+	}
+	if result.Next != nil {
+		resp.NextCursor = result.Next.Encode()
+	}
+
+	return resp, nil
 }
 
+// DeleteTask deletes a task together with all of its subtasks.
 func (s *TaskService) DeleteTask(ctx context.Context, taskID uuid.UUID, userID uuid.UUID) error {
 	existingTask, err := s.taskRepo.FindById(ctx, taskID)
 	if err != nil {
@@ -169,10 +490,38 @@ func (s *TaskService) DeleteTask(ctx context.Context, taskID uuid.UUID, userID u
 		return ErrUnauthorized
 	}
 
-	return s.taskRepo.DeleteById(ctx, taskID, userID)
+	subtasks, err := s.subtasks(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, t := range append([]*task.Task{existingTask}, subtasks...) {
+			if err := s.taskRepo.DeleteById(ctx, t.Id, userID); err != nil {
+				return err
+			}
+			if err := s.publish(ctx, newTaskEvent(SubjectTaskDeleted, t)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+type CompleteTaskRequest struct {
+	// Force completes the task's open subtasks as well and ignores open
+	// tasks it depends on.
+	Force bool `json:"force,omitempty"`
 }
 
-func (s *TaskService) CompleteTask(ctx context.Context, taskID uuid.UUID, userID uuid.UUID) (*TaskResponse, error) {
+// CompleteTask moves a task to the first closed state its workflow lets it
+// reach, failing with a workflow.TransitionError when there is none. Unless
+// req.Force is set, a blocked task fails with task.ErrBlocked and a task with
+// open subtasks fails with task.ErrOpenSubtasks; with it, the open subtasks
+// are completed too. A nil req does not force. Completing an occurrence of a
+// recurring task creates the next occurrence, returned as Next.
+func (s *TaskService) CompleteTask(ctx context.Context, taskID uuid.UUID, req *CompleteTaskRequest, userID uuid.UUID) (*TaskResponse, error) {
 	existingTask, err := s.taskRepo.FindById(ctx, taskID)
 	if err != nil {
 		return nil, err
@@ -185,10 +534,76 @@ func (s *TaskService) CompleteTask(ctx context.Context, taskID uuid.UUID, userID
 		return nil, ErrUnauthorized
 	}
 
-	if err := s.taskRepo.MarkAsCompleted(ctx, taskID, userID); err != nil {
+	// Completing a closed task changes nothing.
+	if existingTask.Closed() {
+		if err := s.annotate(ctx, existingTask); err != nil {
+			return nil, err
+		}
+		return &TaskResponse{Task: existingTask}, nil
+	}
+
+	if _, err := s.moveToCategory(ctx, existingTask, task.CategoryClosed, userID); err != nil {
+		return nil, err
+	}
+
+	force := req != nil && req.Force
+	if err := s.checkUnblocked(ctx, taskID, force); err != nil {
+		return nil, err
+	}
+
+	open, err := s.openSubtasks(ctx, taskID, force)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *task.Task
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.taskRepo.UpdateById(ctx, taskID, existingTask); err != nil {
+			return err
+		}
+
+		if err := s.completeSubtasks(ctx, open, userID); err != nil {
+			return err
+		}
+
+		if err := s.publish(ctx, newTaskEvent(SubjectTaskCompleted, existingTask)); err != nil {
+			return err
+		}
+
+		var err error
+		next, err = s.nextOccurrence(ctx, existingTask)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.annotate(ctx, existingTask); err != nil {
 		return nil, err
 	}
 
-	existingTask.Status = task.StatusDone
-	return &TaskResponse{Task: existingTask}, nil
+	return &TaskResponse{Task: existingTask, Next: next}, nil
+}
+
+// updateEvents describes an update: task.updated with the changed fields,
+// followed by task.completed or task.reopened when the task was closed or
+// reopened. An update that changes nothing emits no events.
+func updateEvents(before, after *task.Task) []*TaskEvent {
+	changes := task.Diff(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	updated := newTaskEvent(SubjectTaskUpdated, after)
+	updated.Changes = changes
+	events := []*TaskEvent{updated}
+
+	switch {
+	case !before.Closed() && after.Closed():
+		events = append(events, newTaskEvent(SubjectTaskCompleted, after))
+	case before.Closed() && !after.Closed():
+		events = append(events, newTaskEvent(SubjectTaskReopened, after))
+	}
+
+	return events
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
 	"taskhub/pkg/base/entity"
 
 	"github.com/google/uuid"
@@ -240,3 +241,175 @@ func TestTaskPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskService_WithMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Buy milk", Priority: task.PriorityLow}, ownerID)
+	assert.NoError(t, err)
+
+	_, err = service.GetTask(ctx, created.Task.Id, uuid.New())
+	assert.Equal(t, ErrUnauthorized, err)
+
+	list, err := service.ListTasks(ctx, &ListTasksRequest{Search: "milk"}, ownerID)
+	assert.NoError(t, err)
+	assert.Len(t, list.Tasks, 1)
+
+	completed, err := service.CompleteTask(ctx, created.Task.Id, nil, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StatusDone, completed.Task.Status)
+
+	assert.NoError(t, service.DeleteTask(ctx, created.Task.Id, ownerID))
+
+	_, err = service.GetTask(ctx, created.Task.Id, ownerID)
+	assert.Equal(t, ErrTaskNotFound, err)
+}
+
+func TestTaskService_ListTasksPaginates(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	for _, title := range []string{"c", "a", "b"} {
+		_, err := service.CreateTask(ctx, &CreateTaskRequest{Title: title}, ownerID)
+		assert.NoError(t, err)
+	}
+
+	first, err := service.ListTasks(ctx, &ListTasksRequest{Sort: "title", Order: "asc", Limit: 2}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, first.Total)
+	assert.Equal(t, []string{"a", "b"}, []string{first.Tasks[0].Title, first.Tasks[1].Title})
+	assert.NotEmpty(t, first.NextCursor)
+
+	second, err := service.ListTasks(ctx, &ListTasksRequest{Sort: "title", Order: "asc", Limit: 2, Cursor: first.NextCursor}, ownerID)
+	assert.NoError(t, err)
+	assert.Len(t, second.Tasks, 1)
+	assert.Equal(t, "c", second.Tasks[0].Title)
+	assert.Empty(t, second.NextCursor)
+
+	_, err = service.ListTasks(ctx, &ListTasksRequest{Sort: "title", Order: "desc", Cursor: first.NextCursor}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidCursor)
+
+	_, err = service.ListTasks(ctx, &ListTasksRequest{Sort: "status"}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidSort)
+}
+
+func TestTaskService_ListTasksSearch(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "Pay invoice", Description: "Invoice from <Acme>"}, ownerID)
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "Call bank", Description: "Ask about the invoice"}, ownerID)
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "Groceries"}, ownerID)
+
+	list, err := service.ListTasks(ctx, &ListTasksRequest{Search: "invoice"}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, "Pay invoice", list.Tasks[0].Title)
+	assert.Equal(t, "Pay <mark>invoice</mark>", list.Tasks[0].Match.Title)
+	assert.Equal(t, "<mark>Invoice</mark> from &lt;Acme&gt;", list.Tasks[0].Match.Snippet)
+
+	list, err = service.ListTasks(ctx, &ListTasksRequest{Search: "invoice -bank"}, ownerID)
+	assert.NoError(t, err)
+	assert.Len(t, list.Tasks, 1)
+
+	_, err = service.ListTasks(ctx, &ListTasksRequest{Sort: "relevance"}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidSort)
+}
+
+func TestTaskService_ListTasksQuery(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	soon := time.Now().Add(24 * time.Hour)
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "File taxes", Priority: task.PriorityHigh, Deadline: &soon}, ownerID)
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "Plan taxes", Priority: task.PriorityLow}, ownerID)
+	service.CreateTask(ctx, &CreateTaskRequest{Title: "Book flights", Priority: task.PriorityHigh}, ownerID)
+
+	list, err := service.ListTasks(ctx, &ListTasksRequest{Query: "priority:high due:<7d"}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "File taxes", list.Tasks[0].Title)
+
+	list, err = service.ListTasks(ctx, &ListTasksRequest{Query: "taxes -priority:high"}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "Plan taxes", list.Tasks[0].Title)
+	assert.NotNil(t, list.Tasks[0].Match)
+
+	_, err = service.ListTasks(ctx, &ListTasksRequest{Query: "status:open"}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidQuery)
+}
+
+type recordingPublisher struct {
+	events []*TaskEvent
+}
+
+func (p *recordingPublisher) PublishTaskEvent(ctx context.Context, event *TaskEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) subjects() []string {
+	var subjects []string
+	for _, e := range p.events {
+		subjects = append(subjects, e.EventType)
+	}
+	return subjects
+}
+
+func TestTaskService_PublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Draft", Priority: task.PriorityLow}, ownerID)
+	assert.NoError(t, err)
+	id := created.Task.Id
+
+	_, err = service.UpdateTask(ctx, id, &UpdateTaskRequest{Title: "Final", Status: task.StatusTodo, Priority: task.PriorityLow}, ownerID)
+	assert.NoError(t, err)
+	updated := publisher.events[len(publisher.events)-1]
+	assert.Equal(t, map[string]FieldChange{"title": {From: "Draft", To: "Final"}}, updated.Changes)
+
+	_, err = service.UpdateTask(ctx, id, &UpdateTaskRequest{Title: "Final", Status: task.StatusTodo, Priority: task.PriorityLow}, ownerID)
+	assert.NoError(t, err)
+
+	_, err = service.CompleteTask(ctx, id, nil, ownerID)
+	assert.NoError(t, err)
+	_, err = service.CompleteTask(ctx, id, nil, ownerID)
+	assert.NoError(t, err)
+
+	_, err = service.UpdateTask(ctx, id, &UpdateTaskRequest{Title: "Final", Status: task.StatusInProgress, Priority: task.PriorityLow}, ownerID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.DeleteTask(ctx, id, ownerID))
+
+	assert.Equal(t, []string{
+		SubjectTaskCreated,
+		SubjectTaskUpdated,
+		SubjectTaskCompleted,
+		SubjectTaskUpdated,
+		SubjectTaskReopened,
+		SubjectTaskDeleted,
+	}, publisher.subjects())
+
+	for _, e := range publisher.events {
+		assert.Equal(t, id, e.TaskID)
+		assert.Equal(t, ownerID, e.UserID)
+	}
+}
+
+func TestTaskService_FailedMutationPublishesNothing(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+
+	assert.Equal(t, ErrTaskNotFound, service.DeleteTask(ctx, uuid.New(), uuid.New()))
+	assert.Empty(t, publisher.events)
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"taskhub/internal/domains/task"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// checkParent checks that a task whose subtasks reach height levels below it
+// can become a subtask of parentID. The parent must be a live task of the same
+// user and must not be the task itself or one of its subtasks, and the deepest
+// subtask must stay within task.MaxDepth. taskID is uuid.Nil for a new task.
+func (s *TaskService) checkParent(ctx context.Context, taskID, parentID uuid.UUID, height int, userID uuid.UUID) error {
+	// Walk up from the parent. Each ancestor visited pushes the task one
+	// level deeper, so the walk is bounded even if the data holds a cycle.
+	for id, visited := &parentID, 0; id != nil; visited++ {
+		if *id == taskID {
+			return task.ErrInvalidParent
+		}
+		if visited+1+height > task.MaxDepth {
+			return task.ErrMaxDepth
+		}
+
+		ancestor, err := s.taskRepo.FindById(ctx, *id)
+		if err != nil {
+			return err
+		}
+		if ancestor == nil || ancestor.UserID != userID {
+			return task.ErrInvalidParent
+		}
+		id = ancestor.ParentID
+	}
+
+	return nil
+}
+
+// subtreeHeight returns how many levels of subtasks lie below a task.
+func (s *TaskService) subtreeHeight(ctx context.Context, taskID uuid.UUID) (int, error) {
+	children, err := s.taskRepo.FindChildren(ctx, taskID)
+	if err != nil {
+		return 0, err
+	}
+
+	height := 0
+	for _, child := range children {
+		h, err := s.subtreeHeight(ctx, child.Id)
+		if err != nil {
+			return 0, err
+		}
+		height = max(height, h+1)
+	}
+
+	return height, nil
+}
+
+// subtasks returns every live subtask below a task, parents before their
+// children.
+func (s *TaskService) subtasks(ctx context.Context, taskID uuid.UUID) ([]*task.Task, error) {
+	var all []*task.Task
+	queue := []uuid.UUID{taskID}
+	for len(queue) > 0 {
+		children, err := s.taskRepo.FindChildren(ctx, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		for _, child := range children {
+			all = append(all, child)
+			queue = append(queue, child.Id)
+		}
+	}
+
+	return all, nil
+}
+
+// openSubtasks returns the unfinished subtasks below a task that completing
+// it would also complete. Without force any open subtask is an
+// task.ErrOpenSubtasks error.
+func (s *TaskService) openSubtasks(ctx context.Context, taskID uuid.UUID, force bool) ([]*task.Task, error) {
+	subtasks, err := s.subtasks(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []*task.Task
+	for _, t := range subtasks {
+		if !t.Closed() {
+			open = append(open, t)
+		}
+	}
+
+	if len(open) > 0 && !force {
+		return nil, task.ErrOpenSubtasks
+	}
+
+	return open, nil
+}
+
+// completeSubtasks moves the given subtasks to the first closed state their
+// workflow lets them reach, inside the caller's transaction.
+func (s *TaskService) completeSubtasks(ctx context.Context, open []*task.Task, userID uuid.UUID) error {
+	for _, t := range open {
+		if _, err := s.moveToCategory(ctx, t, task.CategoryClosed, userID); err != nil {
+			return err
+		}
+		if _, err := s.taskRepo.UpdateById(ctx, t.Id, t); err != nil {
+			return err
+		}
+
+		if err := s.publish(ctx, newTaskEvent(SubjectTaskCompleted, t)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ownedTask loads a live task belonging to userID.
+func (s *TaskService) ownedTask(ctx context.Context, taskID uuid.UUID, userID uuid.UUID) (*task.Task, error) {
+	t, err := s.taskRepo.FindById(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTaskNotFound
+	}
+	if t.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return t, nil
+}
+
+// modifyTask applies fn to an owned task and saves it, publishing
+// task.updated with whatever fn changed.
+func (s *TaskService) modifyTask(ctx context.Context, taskID uuid.UUID, userID uuid.UUID, fn func(t *task.Task) error) (*TaskResponse, error) {
+	t, err := s.ownedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *t
+	before.Checklist = slices.Clone(t.Checklist)
+	if err := fn(t); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	t.UpdateAt = &now
+	t.UpdateBy = &userID
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.taskRepo.UpdateById(ctx, taskID, t); err != nil {
+			return err
+		}
+
+		return s.publish(ctx, updateEvents(&before, t)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.annotate(ctx, t); err != nil {
+		return nil, err
+	}
+
+	return &TaskResponse{Task: t}, nil
+}
+
+type MoveTaskRequest struct {
+	// ParentID is the new parent; nil makes the task a top-level task.
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// MoveTask changes a task's parent, carrying its subtasks along. It fails
+// with task.ErrInvalidParent when the new parent is the task itself or one of
+// its subtasks, and with task.ErrMaxDepth when the subtasks would end up
+// nested too deep.
+func (s *TaskService) MoveTask(ctx context.Context, taskID uuid.UUID, req *MoveTaskRequest, userID uuid.UUID) (*TaskResponse, error) {
+	if _, err := s.ownedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		height, err := s.subtreeHeight(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkParent(ctx, taskID, *req.ParentID, height, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.modifyTask(ctx, taskID, userID, func(t *task.Task) error {
+		t.ParentID = req.ParentID
+		return nil
+	})
+}
+
+type AddChecklistItemRequest struct {
+	Text string `json:"text"`
+}
+
+func (s *TaskService) AddChecklistItem(ctx context.Context, taskID uuid.UUID, req *AddChecklistItemRequest, userID uuid.UUID) (*TaskResponse, error) {
+	item, err := task.NewChecklistItem(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modifyTask(ctx, taskID, userID, func(t *task.Task) error {
+		t.Checklist = append(t.Checklist, item)
+		return nil
+	})
+}
+
+// UpdateChecklistItemRequest changes the fields that are set.
+type UpdateChecklistItemRequest struct {
+	Text *string `json:"text,omitempty"`
+	Done *bool   `json:"done,omitempty"`
+}
+
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, taskID, itemID uuid.UUID, req *UpdateChecklistItemRequest, userID uuid.UUID) (*TaskResponse, error) {
+	if req.Text != nil {
+		text := strings.TrimSpace(*req.Text)
+		if _, err := task.NewChecklistItem(text); err != nil {
+			return nil, err
+		}
+		req.Text = &text
+	}
+
+	return s.modifyTask(ctx, taskID, userID, func(t *task.Task) error {
+		item := t.ChecklistItem(itemID)
+		if item == nil {
+			return task.ErrChecklistItemNotFound
+		}
+		if req.Text != nil {
+			item.Text = *req.Text
+		}
+		if req.Done != nil {
+			item.Done = *req.Done
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, taskID, itemID uuid.UUID, userID uuid.UUID) (*TaskResponse, error) {
+	return s.modifyTask(ctx, taskID, userID, func(t *task.Task) error {
+		if !t.RemoveChecklistItem(itemID) {
+			return task.ErrChecklistItemNotFound
+		}
+		return nil
+	})
+}
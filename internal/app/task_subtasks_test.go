@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSubtask(t *testing.T, service *TaskService, title string, parentID *uuid.UUID, userID uuid.UUID) *task.Task {
+	resp, err := service.CreateTask(context.Background(), &CreateTaskRequest{Title: title, ParentID: parentID}, userID)
+	require.NoError(t, err)
+	return resp.Task
+}
+
+func TestTaskService_SubtaskDepthAndParent(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	root := createSubtask(t, service, "root", nil, ownerID)
+	leaf := root
+	for i := 0; i < task.MaxDepth; i++ {
+		leaf = createSubtask(t, service, "child", &leaf.Id, ownerID)
+	}
+
+	_, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "too deep", ParentID: &leaf.Id}, ownerID)
+	assert.ErrorIs(t, err, task.ErrMaxDepth)
+
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "orphan", ParentID: &root.Id}, uuid.New())
+	assert.ErrorIs(t, err, task.ErrInvalidParent)
+
+	missing := uuid.New()
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "orphan", ParentID: &missing}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidParent)
+
+	got, err := service.GetTask(ctx, root.Id, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, &task.Progress{Done: 0, Total: 1, Percent: 0}, got.Task.Progress)
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	a := createSubtask(t, service, "a", nil, ownerID)
+	b := createSubtask(t, service, "b", &a.Id, ownerID)
+	c := createSubtask(t, service, "c", nil, ownerID)
+
+	_, err := service.MoveTask(ctx, a.Id, &MoveTaskRequest{ParentID: &b.Id}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidParent, "a task cannot move below its own subtask")
+
+	_, err = service.MoveTask(ctx, a.Id, &MoveTaskRequest{ParentID: &a.Id}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidParent)
+
+	moved, err := service.MoveTask(ctx, a.Id, &MoveTaskRequest{ParentID: &c.Id}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Id, *moved.Task.ParentID)
+	assert.Contains(t, publisher.events[len(publisher.events)-1].Changes, "parent_id")
+
+	moved, err = service.MoveTask(ctx, a.Id, &MoveTaskRequest{}, ownerID)
+	assert.NoError(t, err)
+	assert.Nil(t, moved.Task.ParentID)
+}
+
+func TestTaskService_CompletionRules(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	parent := createSubtask(t, service, "parent", nil, ownerID)
+	first := createSubtask(t, service, "first", &parent.Id, ownerID)
+	second := createSubtask(t, service, "second", &parent.Id, ownerID)
+	createSubtask(t, service, "nested", &second.Id, ownerID)
+
+	_, err := service.CompleteTask(ctx, parent.Id, nil, ownerID)
+	assert.ErrorIs(t, err, task.ErrOpenSubtasks)
+
+	_, err = service.UpdateTask(ctx, parent.Id, &UpdateTaskRequest{Title: "parent", Status: task.StatusDone}, ownerID)
+	assert.ErrorIs(t, err, task.ErrOpenSubtasks)
+
+	_, err = service.CompleteTask(ctx, first.Id, nil, ownerID)
+	assert.NoError(t, err)
+
+	list, err := service.ListTasks(ctx, &ListTasksRequest{TopLevel: true}, ownerID)
+	assert.NoError(t, err)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, &task.Progress{Done: 1, Total: 2, Percent: 50}, list.Tasks[0].Progress)
+
+	publisher.events = nil
+	completed, err := service.CompleteTask(ctx, parent.Id, &CompleteTaskRequest{Force: true}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, &task.Progress{Done: 2, Total: 2, Percent: 100}, completed.Task.Progress)
+	assert.Equal(t, []string{SubjectTaskCompleted, SubjectTaskCompleted, SubjectTaskCompleted}, publisher.subjects())
+
+	children, err := service.ListTasks(ctx, &ListTasksRequest{ParentID: &second.Id}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StatusDone, children.Tasks[0].Status)
+}
+
+func TestTaskService_DeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, nil, nil)
+	ownerID := uuid.New()
+
+	parent := createSubtask(t, service, "parent", nil, ownerID)
+	child := createSubtask(t, service, "child", &parent.Id, ownerID)
+	grandchild := createSubtask(t, service, "grandchild", &child.Id, ownerID)
+
+	assert.NoError(t, service.DeleteTask(ctx, parent.Id, ownerID))
+
+	_, err := service.GetTask(ctx, grandchild.Id, ownerID)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestTaskService_Checklist(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, nil, nil, publisher, nil)
+	ownerID := uuid.New()
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Trip", Checklist: []string{"Passport"}}, ownerID)
+	require.NoError(t, err)
+	require.Len(t, created.Task.Checklist, 1)
+
+	_, err = service.CreateTask(ctx, &CreateTaskRequest{Title: "Trip", Checklist: []string{""}}, ownerID)
+	assert.ErrorIs(t, err, task.ErrInvalidChecklistItem)
+
+	resp, err := service.AddChecklistItem(ctx, created.Task.Id, &AddChecklistItemRequest{Text: "Tickets"}, ownerID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Task.Checklist, 2)
+
+	done := true
+	itemID := resp.Task.Checklist[0].ID
+	resp, err = service.UpdateChecklistItem(ctx, created.Task.Id, itemID, &UpdateChecklistItemRequest{Done: &done}, ownerID)
+	assert.NoError(t, err)
+	assert.True(t, resp.Task.Checklist[0].Done)
+	assert.Contains(t, publisher.events[len(publisher.events)-1].Changes, "checklist")
+
+	_, err = service.UpdateChecklistItem(ctx, created.Task.Id, uuid.New(), &UpdateChecklistItemRequest{Done: &done}, ownerID)
+	assert.ErrorIs(t, err, task.ErrChecklistItemNotFound)
+
+	_, err = service.AddChecklistItem(ctx, created.Task.Id, &AddChecklistItemRequest{Text: "Snoop"}, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	resp, err = service.DeleteChecklistItem(ctx, created.Task.Id, itemID, ownerID)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Task.Checklist, 1)
+	assert.Equal(t, "Tickets", resp.Task.Checklist[0].Text)
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"taskhub/internal/domains/task"
+	"taskhub/internal/domains/workflow"
+
+	"github.com/google/uuid"
+)
+
+// resolveWorkflow returns the workflow a user's tasks in a project follow,
+// or their tasks outside projects when projectID is nil: the project's
+// workflow, else the user's own, else the default workflow.
+func resolveWorkflow(ctx context.Context, store workflow.WorkflowStore, projectID *uuid.UUID, userID uuid.UUID) (*workflow.Workflow, error) {
+	if store == nil {
+		return workflow.DefaultWorkflow(), nil
+	}
+
+	if projectID != nil {
+		w, err := store.FindByScope(ctx, userID, projectID)
+		if err != nil || w != nil {
+			return w, err
+		}
+	}
+
+	w, err := store.FindByScope(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return workflow.DefaultWorkflow(), nil
+	}
+
+	return w, nil
+}
+
+func (s *TaskService) workflowOf(ctx context.Context, t *task.Task) (*workflow.Workflow, error) {
+	return resolveWorkflow(ctx, s.workflowRepo, t.ProjectID, t.UserID)
+}
+
+// moveToCategory moves t to the first state of category its workflow lets it
+// reach, failing with a workflow.TransitionError when there is none. It
+// reports whether the status changed.
+func (s *TaskService) moveToCategory(ctx context.Context, t *task.Task, category task.StatusCategory, userID uuid.UUID) (bool, error) {
+	w, err := s.workflowOf(ctx, t)
+	if err != nil {
+		return false, err
+	}
+
+	state, err := w.Target(t.Status, category)
+	if err != nil {
+		return false, err
+	}
+	if state.Name == t.Status && state.Category == t.StatusCategory {
+		return false, nil
+	}
+
+	t.SetStatus(state.Name, state.Category, userID)
+	return true, nil
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	projectrepo "taskhub/internal/domains/project/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/internal/domains/workflow"
+	workflowrepo "taskhub/internal/domains/workflow/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Workflows(t *testing.T) {
+	ctx := context.Background()
+	projects := projectrepo.NewMemoryProjectRepository()
+	workflows := workflowrepo.NewMemoryWorkflowRepository()
+	workflowService := NewWorkflowService(nil, workflows, projects)
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, projects, workflows, nil, nil)
+	userID := uuid.New()
+
+	_, err := workflowService.CreateWorkflow(ctx, reviewWorkflowRequest(nil), userID)
+	require.NoError(t, err)
+
+	created, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Launch"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("backlog"), created.Task.Status)
+	assert.Equal(t, task.CategoryOpen, created.Task.StatusCategory)
+	taskID := created.Task.Id
+
+	_, err = service.UpdateTask(ctx, taskID, &UpdateTaskRequest{Title: "Launch", Status: "shipped"}, userID)
+	var transitionErr *workflow.TransitionError
+	require.True(t, errors.As(err, &transitionErr))
+	assert.ErrorIs(t, err, workflow.ErrIllegalTransition)
+	assert.Equal(t, []task.TaskStatus{"review"}, transitionErr.Allowed)
+
+	_, err = service.UpdateTask(ctx, taskID, &UpdateTaskRequest{Title: "Launch", Status: task.StatusDone}, userID)
+	assert.ErrorIs(t, err, workflow.ErrUnknownStatus)
+
+	// An empty status keeps the current one.
+	renamed, err := service.UpdateTask(ctx, taskID, &UpdateTaskRequest{Title: "Launch v2"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("backlog"), renamed.Task.Status)
+
+	// Completing needs a path to a closed state.
+	_, err = service.CompleteTask(ctx, taskID, &CompleteTaskRequest{}, userID)
+	assert.ErrorIs(t, err, workflow.ErrIllegalTransition)
+
+	started, err := service.StartTask(ctx, taskID, &StartTaskRequest{}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("review"), started.Task.Status)
+	assert.Equal(t, task.CategoryActive, started.Task.StatusCategory)
+
+	completed, err := service.CompleteTask(ctx, taskID, &CompleteTaskRequest{}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("shipped"), completed.Task.Status)
+	assert.True(t, completed.Task.Closed())
+}
+
+func TestTaskService_ProjectWorkflowWins(t *testing.T) {
+	ctx := context.Background()
+	projects := projectrepo.NewMemoryProjectRepository()
+	workflows := workflowrepo.NewMemoryWorkflowRepository()
+	projectService := NewProjectService(nil, projects, taskrepo.NewMemoryTaskRepository(), nil)
+	workflowService := NewWorkflowService(nil, workflows, projects)
+	service := NewTaskService(nil, taskrepo.NewMemoryTaskRepository(), nil, projects, workflows, nil, nil)
+	userID := uuid.New()
+
+	p, err := projectService.CreateProject(ctx, &SaveProjectRequest{Name: "Ops"}, userID)
+	require.NoError(t, err)
+	projectID := p.Project.Id
+
+	_, err = workflowService.CreateWorkflow(ctx, reviewWorkflowRequest(nil), userID)
+	require.NoError(t, err)
+	req := reviewWorkflowRequest(&projectID)
+	req.Name = "Triage"
+	req.States[0].Name = "triage"
+	req.Transitions[0].From = "triage"
+	req.Transitions[1].To = "triage"
+	_, err = workflowService.CreateWorkflow(ctx, req, userID)
+	require.NoError(t, err)
+
+	inProject, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Pager", ProjectID: &projectID}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("triage"), inProject.Task.Status)
+
+	outside, err := service.CreateTask(ctx, &CreateTaskRequest{Title: "Errand"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("backlog"), outside.Task.Status)
+}
@@ -3,7 +3,6 @@ package app
 import (
 	"context"
 	"taskhub/internal/domains/user"
-	"taskhub/internal/domains/user/repo"
 	"taskhub/pkg/base/entity"
 	"taskhub/pkg/logger"
 	"taskhub/pkg/utils"
@@ -18,10 +17,10 @@ var UserServiceModule = fx.Module(
 
 type UserService struct {
 	logger   *logger.Logger
-	userRepo *repo.UserRepository
+	userRepo user.UserStore
 }
 
-func NewUserService(logger *logger.Logger, userRepo *repo.UserRepository) *UserService {
+func NewUserService(logger *logger.Logger, userRepo user.UserStore) *UserService {
 	return &UserService{logger: logger, userRepo: userRepo}
 }
 
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"taskhub/internal/domains/task"
+	"taskhub/internal/domains/view"
+	"taskhub/pkg/base/entity"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var ViewServiceModule = fx.Module(
+	"view-service",
+	fx.Provide(NewViewService),
+)
+
+var (
+	ErrViewNotFound = errors.New("view not found")
+	ErrInvalidView  = errors.New("view needs a name of at most 100 characters")
+)
+
+const maxViewNameLength = 100
+
+// ViewService manages saved task queries, which the dashboard lists as smart
+// lists.
+type ViewService struct {
+	logger   *logger.Logger
+	viewRepo view.ViewStore
+}
+
+func NewViewService(logger *logger.Logger, viewRepo view.ViewStore) *ViewService {
+	return &ViewService{
+		logger:   logger,
+		viewRepo: viewRepo,
+	}
+}
+
+type SaveViewRequest struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// validate trims the name and checks that the query parses, so a saved view
+// always loads.
+func (req *SaveViewRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxViewNameLength {
+		return ErrInvalidView
+	}
+
+	_, err := task.ParseQuery(req.Query, time.Now())
+	return err
+}
+
+type ViewResponse struct {
+	View *view.View `json:"view"`
+}
+
+type ListViewsResponse struct {
+	Views []*view.View `json:"views"`
+}
+
+func (s *ViewService) CreateView(ctx context.Context, req *SaveViewRequest, userID uuid.UUID) (*ViewResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := s.viewRepo.Create(ctx, &view.View{
+		BaseEntity: entity.BaseEntity{
+			Id:        uuid.New(),
+			CreatedAt: time.Now(),
+			CreatedBy: userID,
+		},
+		UserID: userID,
+		Name:   req.Name,
+		Query:  req.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ViewResponse{View: v}, nil
+}
+
+func (s *ViewService) ListViews(ctx context.Context, userID uuid.UUID) (*ListViewsResponse, error) {
+	views, err := s.viewRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if views == nil {
+		views = []*view.View{}
+	}
+
+	return &ListViewsResponse{Views: views}, nil
+}
+
+func (s *ViewService) GetView(ctx context.Context, viewID uuid.UUID, userID uuid.UUID) (*ViewResponse, error) {
+	v, err := s.ownedView(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ViewResponse{View: v}, nil
+}
+
+func (s *ViewService) UpdateView(ctx context.Context, viewID uuid.UUID, req *SaveViewRequest, userID uuid.UUID) (*ViewResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := s.ownedView(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	v.Name = req.Name
+	v.Query = req.Query
+	v.UpdateAt = &now
+	v.UpdateBy = &userID
+
+	if _, err := s.viewRepo.UpdateById(ctx, viewID, v); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrViewNotFound
+		}
+		return nil, err
+	}
+
+	return &ViewResponse{View: v}, nil
+}
+
+func (s *ViewService) DeleteView(ctx context.Context, viewID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.ownedView(ctx, viewID, userID); err != nil {
+		return err
+	}
+
+	if err := s.viewRepo.DeleteById(ctx, viewID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrViewNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ViewService) ownedView(ctx context.Context, viewID uuid.UUID, userID uuid.UUID) (*view.View, error) {
+	v, err := s.viewRepo.FindById(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrViewNotFound
+	}
+	if v.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return v, nil
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"taskhub/internal/domains/task"
+	"taskhub/internal/domains/view"
+	viewrepo "taskhub/internal/domains/view/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	service := NewViewService(nil, viewrepo.NewMemoryViewRepository())
+	userID := uuid.New()
+
+	created, err := service.CreateView(ctx, &SaveViewRequest{Name: "  Urgent ", Query: "priority:high due:<3d"}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Urgent", created.View.Name)
+
+	_, err = service.CreateView(ctx, &SaveViewRequest{Name: "Backlog", Query: "status:todo"}, userID)
+	assert.NoError(t, err)
+
+	list, err := service.ListViews(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backlog", "Urgent"}, []string{list.Views[0].Name, list.Views[1].Name})
+
+	updated, err := service.UpdateView(ctx, created.View.Id, &SaveViewRequest{Name: "Urgent", Query: "priority:high"}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "priority:high", updated.View.Query)
+
+	got, err := service.GetView(ctx, created.View.Id, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "priority:high", got.View.Query)
+
+	assert.NoError(t, service.DeleteView(ctx, created.View.Id, userID))
+	_, err = service.GetView(ctx, created.View.Id, userID)
+	assert.ErrorIs(t, err, ErrViewNotFound)
+
+	list, err = service.ListViews(ctx, uuid.New())
+	assert.NoError(t, err)
+	assert.Empty(t, list.Views)
+}
+
+func TestViewService_Errors(t *testing.T) {
+	ctx := context.Background()
+	service := NewViewService(nil, viewrepo.NewMemoryViewRepository())
+	userID := uuid.New()
+
+	_, err := service.CreateView(ctx, &SaveViewRequest{Name: " ", Query: "status:todo"}, userID)
+	assert.ErrorIs(t, err, ErrInvalidView)
+
+	_, err = service.CreateView(ctx, &SaveViewRequest{Name: "Broken", Query: "due:someday"}, userID)
+	assert.ErrorIs(t, err, task.ErrInvalidQuery)
+
+	created, err := service.CreateView(ctx, &SaveViewRequest{Name: "Mine", Query: "status:todo"}, userID)
+	assert.NoError(t, err)
+
+	_, err = service.CreateView(ctx, &SaveViewRequest{Name: "Mine", Query: "status:done"}, userID)
+	assert.ErrorIs(t, err, view.ErrNameTaken)
+
+	_, err = service.GetView(ctx, created.View.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	err = service.DeleteView(ctx, created.View.Id, uuid.New())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = service.UpdateView(ctx, uuid.New(), &SaveViewRequest{Name: "Ghost", Query: ""}, userID)
+	assert.ErrorIs(t, err, ErrViewNotFound)
+}
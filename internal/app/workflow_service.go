@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/internal/domains/project"
+	"taskhub/internal/domains/workflow"
+	"taskhub/pkg/base/entity"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var WorkflowServiceModule = fx.Module(
+	"workflow-service",
+	fx.Provide(NewWorkflowService),
+)
+
+var ErrWorkflowNotFound = errors.New("workflow not found")
+
+// WorkflowService manages the workflows that define the statuses of users'
+// tasks.
+type WorkflowService struct {
+	logger       *logger.Logger
+	workflowRepo workflow.WorkflowStore
+	projectRepo  project.ProjectStore
+}
+
+func NewWorkflowService(logger *logger.Logger, workflowRepo workflow.WorkflowStore, projectRepo project.ProjectStore) *WorkflowService {
+	return &WorkflowService{
+		logger:       logger,
+		workflowRepo: workflowRepo,
+		projectRepo:  projectRepo,
+	}
+}
+
+type SaveWorkflowRequest struct {
+	Name string `json:"name"`
+	// ProjectID makes the workflow apply to one of the user's projects
+	// rather than to their tasks outside projects. It is only read on
+	// create.
+	ProjectID   *uuid.UUID            `json:"project_id,omitempty"`
+	States      []workflow.State      `json:"states"`
+	Transitions []workflow.Transition `json:"transitions"`
+}
+
+type WorkflowResponse struct {
+	Workflow *workflow.Workflow `json:"workflow"`
+}
+
+type ListWorkflowsResponse struct {
+	Workflows []*workflow.Workflow `json:"workflows"`
+}
+
+// CreateWorkflow gives the user, or one of their projects, a workflow. It
+// fails with an error wrapping workflow.ErrInvalidWorkflow when the
+// workflow is unusable, ErrUnknownProject when the project is not one of the
+// user's, and workflow.ErrScopeTaken when the user or project already has
+// one. Tasks whose status the workflow lacks keep it until they next move.
+func (s *WorkflowService) CreateWorkflow(ctx context.Context, req *SaveWorkflowRequest, userID uuid.UUID) (*WorkflowResponse, error) {
+	w := &workflow.Workflow{
+		BaseEntity: entity.BaseEntity{
+			Id:        uuid.New(),
+			CreatedAt: time.Now(),
+			CreatedBy: userID,
+		},
+		UserID:      userID,
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		States:      req.States,
+		Transitions: req.Transitions,
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	if req.ProjectID != nil {
+		if s.projectRepo == nil {
+			return nil, ErrUnknownProject
+		}
+		p, err := s.projectRepo.FindById(ctx, *req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil || p.UserID != userID {
+			return nil, ErrUnknownProject
+		}
+	}
+
+	created, err := s.workflowRepo.Create(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowResponse{Workflow: created}, nil
+}
+
+func (s *WorkflowService) ListWorkflows(ctx context.Context, userID uuid.UUID) (*ListWorkflowsResponse, error) {
+	workflows, err := s.workflowRepo.FindByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workflows == nil {
+		workflows = []*workflow.Workflow{}
+	}
+
+	return &ListWorkflowsResponse{Workflows: workflows}, nil
+}
+
+func (s *WorkflowService) GetWorkflow(ctx context.Context, workflowID uuid.UUID, userID uuid.UUID) (*WorkflowResponse, error) {
+	w, err := s.ownedWorkflow(ctx, workflowID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowResponse{Workflow: w}, nil
+}
+
+// EffectiveWorkflow returns the workflow tasks in a project follow, or the
+// user's tasks outside projects when projectID is nil. The default workflow
+// has no id.
+func (s *WorkflowService) EffectiveWorkflow(ctx context.Context, projectID *uuid.UUID, userID uuid.UUID) (*WorkflowResponse, error) {
+	w, err := resolveWorkflow(ctx, s.workflowRepo, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkflowResponse{Workflow: w}, nil
+}
+
+// UpdateWorkflow replaces a workflow's name, states and transitions. It
+// fails like CreateWorkflow on an unusable workflow. Existing tasks keep
+// their status and category until they next move.
+func (s *WorkflowService) UpdateWorkflow(ctx context.Context, workflowID uuid.UUID, req *SaveWorkflowRequest, userID uuid.UUID) (*WorkflowResponse, error) {
+	w, err := s.ownedWorkflow(ctx, workflowID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	w.Name = req.Name
+	w.States = req.States
+	w.Transitions = req.Transitions
+	w.UpdateAt = &now
+	w.UpdateBy = &userID
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.workflowRepo.UpdateById(ctx, workflowID, w); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, err
+	}
+
+	return &WorkflowResponse{Workflow: w}, nil
+}
+
+// DeleteWorkflow deletes a workflow. Its tasks fall back to the user's
+// workflow or the default one.
+func (s *WorkflowService) DeleteWorkflow(ctx context.Context, workflowID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.ownedWorkflow(ctx, workflowID, userID); err != nil {
+		return err
+	}
+
+	if err := s.workflowRepo.DeleteById(ctx, workflowID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWorkflowNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *WorkflowService) ownedWorkflow(ctx context.Context, workflowID uuid.UUID, userID uuid.UUID) (*workflow.Workflow, error) {
+	w, err := s.workflowRepo.FindById(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, ErrWorkflowNotFound
+	}
+	if w.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return w, nil
+}
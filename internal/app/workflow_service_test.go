@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	projectrepo "taskhub/internal/domains/project/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/internal/domains/workflow"
+	workflowrepo "taskhub/internal/domains/workflow/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reviewWorkflowRequest(projectID *uuid.UUID) *SaveWorkflowRequest {
+	return &SaveWorkflowRequest{
+		Name:      "Review",
+		ProjectID: projectID,
+		States: []workflow.State{
+			{Name: "backlog", Category: task.CategoryOpen},
+			{Name: "review", Category: task.CategoryActive},
+			{Name: "shipped", Category: task.CategoryClosed},
+		},
+		Transitions: []workflow.Transition{
+			{From: "backlog", To: "review"},
+			{From: "review", To: "backlog"},
+			{From: "review", To: "shipped"},
+		},
+	}
+}
+
+func TestWorkflowService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	service := NewWorkflowService(nil, workflowrepo.NewMemoryWorkflowRepository(), nil)
+	userID := uuid.New()
+
+	effective, err := service.EffectiveWorkflow(ctx, nil, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Default", effective.Workflow.Name)
+
+	created, err := service.CreateWorkflow(ctx, reviewWorkflowRequest(nil), userID)
+	require.NoError(t, err)
+
+	effective, err = service.EffectiveWorkflow(ctx, nil, userID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Workflow.Id, effective.Workflow.Id)
+
+	req := reviewWorkflowRequest(nil)
+	req.Name = "Release"
+	req.States[2].Name = "released"
+	req.Transitions[2].To = "released"
+	updated, err := service.UpdateWorkflow(ctx, created.Workflow.Id, req, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Release", updated.Workflow.Name)
+
+	got, err := service.GetWorkflow(ctx, created.Workflow.Id, userID)
+	require.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("released"), got.Workflow.States[2].Name)
+
+	list, err := service.ListWorkflows(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, list.Workflows, 1)
+
+	assert.NoError(t, service.DeleteWorkflow(ctx, created.Workflow.Id, userID))
+	_, err = service.GetWorkflow(ctx, created.Workflow.Id, userID)
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
+
+func TestWorkflowService_Errors(t *testing.T) {
+	ctx := context.Background()
+	projects := projectrepo.NewMemoryProjectRepository()
+	projectService := NewProjectService(nil, projects, taskrepo.NewMemoryTaskRepository(), nil)
+	service := NewWorkflowService(nil, workflowrepo.NewMemoryWorkflowRepository(), projects)
+	ownerID, otherID := uuid.New(), uuid.New()
+
+	_, err := service.CreateWorkflow(ctx, &SaveWorkflowRequest{Name: "Empty"}, ownerID)
+	assert.ErrorIs(t, err, workflow.ErrInvalidWorkflow)
+
+	foreign, err := projectService.CreateProject(ctx, &SaveProjectRequest{Name: "Theirs"}, otherID)
+	require.NoError(t, err)
+	_, err = service.CreateWorkflow(ctx, reviewWorkflowRequest(&foreign.Project.Id), ownerID)
+	assert.ErrorIs(t, err, ErrUnknownProject)
+
+	created, err := service.CreateWorkflow(ctx, reviewWorkflowRequest(nil), ownerID)
+	require.NoError(t, err)
+	_, err = service.CreateWorkflow(ctx, reviewWorkflowRequest(nil), ownerID)
+	assert.ErrorIs(t, err, workflow.ErrScopeTaken)
+
+	_, err = service.GetWorkflow(ctx, created.Workflow.Id, otherID)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, service.DeleteWorkflow(ctx, created.Workflow.Id, otherID), ErrUnauthorized)
+	_, err = service.UpdateWorkflow(ctx, created.Workflow.Id, &SaveWorkflowRequest{Name: "Broken"}, ownerID)
+	assert.ErrorIs(t, err, workflow.ErrInvalidWorkflow)
+	_, err = service.GetWorkflow(ctx, uuid.New(), ownerID)
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
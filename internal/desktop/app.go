@@ -114,6 +114,7 @@ func (d *DesktopApp) handleLogin(email, password string) {
 	req := &app.LoginRequest{
 		Email:    email,
 		Password: password,
+		Device:   "TaskHub desktop",
 	}
 
 	resp, err := d.authService.Login(ctx, req)
@@ -122,10 +123,41 @@ func (d *DesktopApp) handleLogin(email, password string) {
 		return
 	}
 
+	if resp.MFARequired {
+		d.promptMFACode(resp.MFAToken, req.Device)
+		return
+	}
+
 	d.currentUser = resp
 	d.showDashboard()
 }
 
+// promptMFACode asks for the second factor of a login and completes it.
+func (d *DesktopApp) promptMFACode(mfaToken, device string) {
+	codeEntry := widget.NewEntry()
+	codeEntry.SetPlaceHolder("6-digit code or recovery code")
+
+	items := []*widget.FormItem{widget.NewFormItem("Code", codeEntry)}
+	dialog.ShowForm("Two-factor authentication", "Verify", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		resp, err := d.authService.LoginMFA(context.Background(), &app.LoginMFARequest{
+			MFAToken: mfaToken,
+			Code:     codeEntry.Text,
+			Device:   device,
+		})
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("login failed: %v", err), d.mainWindow)
+			return
+		}
+
+		d.currentUser = resp
+		d.showDashboard()
+	}, d.mainWindow)
+}
+
 func (d *DesktopApp) showRegisterScreen() {
 	// App title
 	titleLabel := widget.NewLabelWithStyle("Create Account", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
@@ -0,0 +1,138 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidToken = errors.New("invalid personal access token")
+
+const (
+	// TokenPrefix starts every personal access token, telling them apart
+	// from the JWTs of a session.
+	TokenPrefix = "thp_"
+
+	maxNameLength = 100
+)
+
+// Scope is an action a personal access token may perform, written
+// resource:access.
+type Scope string
+
+const (
+	ScopeTasksRead          Scope = "tasks:read"
+	ScopeTasksWrite         Scope = "tasks:write"
+	ScopeProjectsRead       Scope = "projects:read"
+	ScopeProjectsWrite      Scope = "projects:write"
+	ScopeLabelsRead         Scope = "labels:read"
+	ScopeLabelsWrite        Scope = "labels:write"
+	ScopeViewsRead          Scope = "views:read"
+	ScopeViewsWrite         Scope = "views:write"
+	ScopeWorkflowsRead      Scope = "workflows:read"
+	ScopeWorkflowsWrite     Scope = "workflows:write"
+	ScopeNotificationsRead  Scope = "notifications:read"
+	ScopeNotificationsWrite Scope = "notifications:write"
+)
+
+// Scopes lists every scope, in the order the settings page shows them.
+var Scopes = []Scope{
+	ScopeTasksRead, ScopeTasksWrite,
+	ScopeProjectsRead, ScopeProjectsWrite,
+	ScopeLabelsRead, ScopeLabelsWrite,
+	ScopeViewsRead, ScopeViewsWrite,
+	ScopeWorkflowsRead, ScopeWorkflowsWrite,
+	ScopeNotificationsRead, ScopeNotificationsWrite,
+}
+
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// ReadScope and WriteScope return the scopes reading and changing resource
+// need.
+func ReadScope(resource string) Scope {
+	return Scope(resource + ":read")
+}
+
+func WriteScope(resource string) Scope {
+	return Scope(resource + ":write")
+}
+
+// Token is a personal access token a user created for a script or CI job.
+// It acts as the user within its scopes until it expires or is deleted.
+// Only a hash of the token is stored; Prefix is the start of the token, so
+// users can tell their tokens apart.
+type Token struct {
+	Id         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TokenHash  string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t *Token) GetId() uuid.UUID {
+	return t.Id
+}
+
+// Validate checks that the token has a name and known scopes, dropping
+// duplicate scopes. Errors wrap ErrInvalidToken.
+func (t *Token) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" || len(t.Name) > maxNameLength {
+		return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidToken, maxNameLength)
+	}
+	if len(t.Scopes) == 0 {
+		return fmt.Errorf("%w: no scopes", ErrInvalidToken)
+	}
+
+	var scopes []Scope
+	for _, s := range t.Scopes {
+		if !s.Valid() {
+			return fmt.Errorf("%w: unknown scope %q", ErrInvalidToken, s)
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	t.Scopes = scopes
+
+	return nil
+}
+
+// Allows reports whether the token grants scope. A write scope grants the
+// matching read scope too.
+func (t *Token) Allows(scope Scope) bool {
+	if slices.Contains(t.Scopes, scope) {
+		return true
+	}
+	resource, access, _ := strings.Cut(string(scope), ":")
+	return access == "read" && slices.Contains(t.Scopes, WriteScope(resource))
+}
+
+// Expired reports whether the token has expired at now.
+func (t *Token) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// TokenStore persists personal access tokens.
+type TokenStore interface {
+	Create(ctx context.Context, t *Token) error
+	FindById(ctx context.Context, id uuid.UUID) (*Token, error)
+	FindByHash(ctx context.Context, hash string) (*Token, error)
+	// FindByUserId returns a user's tokens, newest first.
+	FindByUserId(ctx context.Context, userID uuid.UUID) ([]*Token, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+	// DeleteByUserId revokes every token of a user.
+	DeleteByUserId(ctx context.Context, userID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
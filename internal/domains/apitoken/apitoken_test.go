@@ -0,0 +1,43 @@
+package apitoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken_Validate(t *testing.T) {
+	tok := &Token{Name: "  ci  ", Scopes: []Scope{ScopeTasksRead, ScopeTasksRead, ScopeLabelsWrite}}
+	assert.NoError(t, tok.Validate())
+	assert.Equal(t, "ci", tok.Name)
+	assert.Equal(t, []Scope{ScopeTasksRead, ScopeLabelsWrite}, tok.Scopes)
+
+	for _, tok := range []*Token{
+		{Name: " ", Scopes: []Scope{ScopeTasksRead}},
+		{Name: "ci"},
+		{Name: "ci", Scopes: []Scope{"tasks:delete"}},
+	} {
+		assert.ErrorIs(t, tok.Validate(), ErrInvalidToken)
+	}
+}
+
+func TestToken_Allows(t *testing.T) {
+	tok := &Token{Scopes: []Scope{ScopeTasksWrite, ScopeLabelsRead}}
+
+	assert.True(t, tok.Allows(ReadScope("tasks")))
+	assert.True(t, tok.Allows(WriteScope("tasks")))
+	assert.True(t, tok.Allows(ReadScope("labels")))
+	assert.False(t, tok.Allows(WriteScope("labels")))
+	assert.False(t, tok.Allows(ReadScope("projects")))
+}
+
+func TestToken_Expired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&Token{}).Expired(now))
+
+	expiresAt := now.Add(time.Minute)
+	tok := &Token{ExpiresAt: &expiresAt}
+	assert.False(t, tok.Expired(now))
+	assert.True(t, tok.Expired(expiresAt))
+}
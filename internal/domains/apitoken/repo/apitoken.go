@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/internal/domains/apitoken"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var TokenRepositoryModule = fx.Module(
+	"apitoken-repo",
+	fx.Provide(fx.Annotate(NewTokenRepository, fx.As(new(apitoken.TokenStore)))),
+)
+
+type TokenRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ apitoken.TokenStore = (*TokenRepository)(nil)
+
+func NewTokenRepository(config *config.Config, logger *logger.Logger) *TokenRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &TokenRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+const tokenColumns = `id, user_id, name, prefix, token_hash, scopes, expires_at, last_used_at, created_at`
+
+func (r *TokenRepository) Create(ctx context.Context, t *apitoken.Token) error {
+	query := `INSERT INTO personal_access_tokens (id, user_id, name, prefix, token_hash, scopes, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = string(s)
+	}
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, t.Id, t.UserID, t.Name, t.Prefix, t.TokenHash, pq.Array(scopes), t.ExpiresAt, t.CreatedAt)
+	return err
+}
+
+func (r *TokenRepository) FindById(ctx context.Context, id uuid.UUID) (*apitoken.Token, error) {
+	query := `SELECT ` + tokenColumns + ` FROM personal_access_tokens WHERE id = $1`
+
+	t, err := scanToken(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (r *TokenRepository) FindByHash(ctx context.Context, hash string) (*apitoken.Token, error) {
+	query := `SELECT ` + tokenColumns + ` FROM personal_access_tokens WHERE token_hash = $1`
+
+	t, err := scanToken(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (r *TokenRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*apitoken.Token, error) {
+	query := `SELECT ` + tokenColumns + ` FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*apitoken.Token
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
+}
+
+func scanToken(row interface{ Scan(...any) error }) (*apitoken.Token, error) {
+	var t apitoken.Token
+	var scopes []string
+	var expiresAt, lastUsedAt sql.NullTime
+
+	err := row.Scan(&t.Id, &t.UserID, &t.Name, &t.Prefix, &t.TokenHash, pq.Array(&scopes), &expiresAt, &lastUsedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range scopes {
+		t.Scopes = append(t.Scopes, apitoken.Scope(s))
+	}
+	if expiresAt.Valid {
+		t.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &t, nil
+}
+
+func (r *TokenRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM personal_access_tokens WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *TokenRepository) DeleteByUserId(ctx context.Context, userID uuid.UUID) error {
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM personal_access_tokens WHERE user_id = $1`, userID)
+	return err
+}
+
+func (r *TokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = $1 WHERE id = $2`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, id)
+	return err
+}
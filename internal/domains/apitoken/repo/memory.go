@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"taskhub/internal/domains/apitoken"
+	baserepo "taskhub/pkg/base/repo"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryTokenRepositoryModule = fx.Module(
+	"apitoken-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryTokenRepository, fx.As(new(apitoken.TokenStore)))),
+)
+
+// MemoryTokenRepository is an in-memory apitoken.TokenStore for tests and
+// local development.
+type MemoryTokenRepository struct {
+	store *baserepo.MemoryRepository[*apitoken.Token]
+}
+
+var _ apitoken.TokenStore = (*MemoryTokenRepository)(nil)
+
+func NewMemoryTokenRepository() *MemoryTokenRepository {
+	return &MemoryTokenRepository{
+		store: baserepo.NewMemoryRepository(cloneToken),
+	}
+}
+
+func cloneToken(t *apitoken.Token) *apitoken.Token {
+	c := *t
+	c.Scopes = slices.Clone(t.Scopes)
+	return &c
+}
+
+func (r *MemoryTokenRepository) Create(ctx context.Context, t *apitoken.Token) error {
+	_, err := r.store.Create(ctx, t)
+	return err
+}
+
+func (r *MemoryTokenRepository) FindById(ctx context.Context, id uuid.UUID) (*apitoken.Token, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryTokenRepository) FindByHash(ctx context.Context, hash string) (*apitoken.Token, error) {
+	tokens, err := r.store.FindAll(ctx, func(t *apitoken.Token) bool {
+		return t.TokenHash == hash
+	})
+	if err != nil || len(tokens) == 0 {
+		return nil, err
+	}
+	return tokens[0], nil
+}
+
+func (r *MemoryTokenRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*apitoken.Token, error) {
+	tokens, err := r.store.FindAll(ctx, func(t *apitoken.Token) bool {
+		return t.UserID == userID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+func (r *MemoryTokenRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
+
+func (r *MemoryTokenRepository) DeleteByUserId(ctx context.Context, userID uuid.UUID) error {
+	tokens, err := r.FindByUserId(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		if err := r.store.Delete(ctx, t.Id); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := r.store.Modify(ctx, id, func(existing *apitoken.Token) error {
+		existing.LastUsedAt = &at
+		return nil
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/apitoken"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTokenRepository()
+	userID := uuid.New()
+	now := time.Now()
+
+	older := &apitoken.Token{Id: uuid.New(), UserID: userID, Name: "ci", TokenHash: "a", Scopes: []apitoken.Scope{apitoken.ScopeTasksRead}, CreatedAt: now.Add(-time.Hour)}
+	newer := &apitoken.Token{Id: uuid.New(), UserID: userID, Name: "backup", TokenHash: "b", Scopes: []apitoken.Scope{apitoken.ScopeTasksWrite}, CreatedAt: now}
+	other := &apitoken.Token{Id: uuid.New(), UserID: uuid.New(), Name: "other", TokenHash: "c", CreatedAt: now}
+	for _, tok := range []*apitoken.Token{older, newer, other} {
+		require.NoError(t, r.Create(ctx, tok))
+	}
+
+	found, err := r.FindByHash(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, newer.Id, found.Id)
+	found, err = r.FindByHash(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	tokens, err := r.FindByUserId(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, newer.Id, tokens[0].Id)
+	assert.Equal(t, older.Id, tokens[1].Id)
+
+	require.NoError(t, r.TouchLastUsed(ctx, older.Id, now))
+	require.NoError(t, r.TouchLastUsed(ctx, uuid.New(), now))
+	found, err = r.FindById(ctx, older.Id)
+	require.NoError(t, err)
+	assert.Equal(t, now, *found.LastUsedAt)
+
+	require.NoError(t, r.DeleteById(ctx, older.Id))
+	assert.ErrorIs(t, r.DeleteById(ctx, older.Id), sql.ErrNoRows)
+	found, err = r.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -0,0 +1,34 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+)
+
+var ErrNameTaken = errors.New("label name already taken")
+
+// Label is a user's free-form tag for tasks. Color is a #rrggbb hex color.
+type Label struct {
+	entity.BaseEntity
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Color  string    `json:"color"`
+}
+
+// LabelStore persists labels. Names are unique per user; Create and
+// UpdateById report a clash as ErrNameTaken. Deleting a label detaches it
+// from its tasks.
+type LabelStore interface {
+	Create(ctx context.Context, l *Label) (*Label, error)
+	FindById(ctx context.Context, id uuid.UUID) (*Label, error)
+	// FindByIds returns the labels with the given ids that exist, ordered
+	// by name.
+	FindByIds(ctx context.Context, ids []uuid.UUID) ([]*Label, error)
+	// FindByUserId returns the user's labels ordered by name.
+	FindByUserId(ctx context.Context, userID uuid.UUID) ([]*Label, error)
+	UpdateById(ctx context.Context, id uuid.UUID, l *Label) (*Label, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/config"
+	"taskhub/internal/domains/label"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var LabelRepositoryModule = fx.Module(
+	"label-repo",
+	fx.Provide(fx.Annotate(NewLabelRepository, fx.As(new(label.LabelStore)))),
+)
+
+type LabelRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ label.LabelStore = (*LabelRepository)(nil)
+
+func NewLabelRepository(config *config.Config, logger *logger.Logger) *LabelRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &LabelRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// uniqueViolation maps the (user_id, name) constraint to label.ErrNameTaken.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return label.ErrNameTaken
+	}
+	return err
+}
+
+const labelColumns = `id, user_id, name, color, created_at, created_by, updated_at`
+
+func (r *LabelRepository) Create(ctx context.Context, l *label.Label) (*label.Label, error) {
+	query := `INSERT INTO labels (id, user_id, name, color, created_at, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, l.Id, l.UserID, l.Name, l.Color, l.CreatedAt, l.CreatedBy)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return l, nil
+}
+
+func (r *LabelRepository) FindById(ctx context.Context, id uuid.UUID) (*label.Label, error) {
+	query := `SELECT ` + labelColumns + ` FROM labels WHERE id = $1`
+
+	l, err := scanLabel(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return l, err
+}
+
+func (r *LabelRepository) FindByIds(ctx context.Context, ids []uuid.UUID) ([]*label.Label, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+
+	return r.findLabels(ctx, `SELECT `+labelColumns+` FROM labels WHERE id = ANY($1::uuid[]) ORDER BY name`, pq.Array(strs))
+}
+
+func (r *LabelRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*label.Label, error) {
+	return r.findLabels(ctx, `SELECT `+labelColumns+` FROM labels WHERE user_id = $1 ORDER BY name`, userID)
+}
+
+func (r *LabelRepository) findLabels(ctx context.Context, query string, args ...interface{}) ([]*label.Label, error) {
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*label.Label
+	for rows.Next() {
+		l, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+
+	return labels, rows.Err()
+}
+
+func scanLabel(row interface{ Scan(...any) error }) (*label.Label, error) {
+	var l label.Label
+	var updatedAt sql.NullTime
+
+	if err := row.Scan(&l.Id, &l.UserID, &l.Name, &l.Color, &l.CreatedAt, &l.CreatedBy, &updatedAt); err != nil {
+		return nil, err
+	}
+	if updatedAt.Valid {
+		l.UpdateAt = &updatedAt.Time
+	}
+
+	return &l, nil
+}
+
+func (r *LabelRepository) UpdateById(ctx context.Context, id uuid.UUID, l *label.Label) (*label.Label, error) {
+	query := `UPDATE labels SET name = $1, color = $2, updated_at = $3, updated_by = $4 WHERE id = $5`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, l.Name, l.Color, l.UpdateAt, l.UpdateBy, id)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	l.Id = id
+	return l, nil
+}
+
+// DeleteById deletes a label. The task_labels foreign key cascades, so the
+// label drops off its tasks too.
+func (r *LabelRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM labels WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/label"
+	baserepo "taskhub/pkg/base/repo"
+
+	"github.com/google/uuid"
+)
+
+// MemoryLabelRepository is an in-memory label.LabelStore for tests and local
+// development. Unlike LabelRepository, deleting a label leaves its id on the
+// tasks of a memory task repository; the task service skips unknown labels.
+type MemoryLabelRepository struct {
+	// mu makes the name uniqueness check atomic with the write.
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*label.Label]
+}
+
+var _ label.LabelStore = (*MemoryLabelRepository)(nil)
+
+func NewMemoryLabelRepository() *MemoryLabelRepository {
+	return &MemoryLabelRepository{
+		store: baserepo.NewMemoryRepository(cloneLabel),
+	}
+}
+
+func cloneLabel(l *label.Label) *label.Label {
+	c := *l
+	return &c
+}
+
+func (r *MemoryLabelRepository) nameTaken(ctx context.Context, l *label.Label) (bool, error) {
+	clashes, err := r.store.FindAll(ctx, func(existing *label.Label) bool {
+		return existing.UserID == l.UserID && existing.Name == l.Name && existing.Id != l.Id
+	})
+	return len(clashes) > 0, err
+}
+
+func (r *MemoryLabelRepository) Create(ctx context.Context, l *label.Label) (*label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken, err := r.nameTaken(ctx, l)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, label.ErrNameTaken
+	}
+
+	return r.store.Create(ctx, l)
+}
+
+func (r *MemoryLabelRepository) FindById(ctx context.Context, id uuid.UUID) (*label.Label, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryLabelRepository) FindByIds(ctx context.Context, ids []uuid.UUID) ([]*label.Label, error) {
+	return r.findSorted(ctx, func(l *label.Label) bool {
+		return slices.Contains(ids, l.Id)
+	})
+}
+
+func (r *MemoryLabelRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*label.Label, error) {
+	return r.findSorted(ctx, func(l *label.Label) bool {
+		return l.UserID == userID
+	})
+}
+
+func (r *MemoryLabelRepository) findSorted(ctx context.Context, match func(*label.Label) bool) ([]*label.Label, error) {
+	labels, err := r.store.FindAll(ctx, match)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+
+	return labels, nil
+}
+
+func (r *MemoryLabelRepository) UpdateById(ctx context.Context, id uuid.UUID, l *label.Label) (*label.Label, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.store.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	existing.Name = l.Name
+	taken, err := r.nameTaken(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, label.ErrNameTaken
+	}
+
+	err = r.store.Modify(ctx, id, func(existing *label.Label) error {
+		existing.Name = l.Name
+		existing.Color = l.Color
+		existing.UpdateAt = l.UpdateAt
+		existing.UpdateBy = l.UpdateBy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Id = id
+	return l, nil
+}
+
+func (r *MemoryLabelRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
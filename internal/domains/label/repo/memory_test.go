@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/label"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLabel(userID uuid.UUID, name string) *label.Label {
+	return &label.Label{
+		BaseEntity: entity.BaseEntity{Id: uuid.New(), CreatedAt: time.Now(), CreatedBy: userID},
+		UserID:     userID,
+		Name:       name,
+		Color:      "#6b7280",
+	}
+}
+
+func TestMemoryLabelRepository_NamesAreUniquePerUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryLabelRepository()
+	userID := uuid.New()
+
+	bug, err := r.Create(ctx, newLabel(userID, "bug"))
+	assert.NoError(t, err)
+	_, err = r.Create(ctx, newLabel(userID, "bug"))
+	assert.ErrorIs(t, err, label.ErrNameTaken)
+	_, err = r.Create(ctx, newLabel(uuid.New(), "bug"))
+	assert.NoError(t, err)
+
+	chore, _ := r.Create(ctx, newLabel(userID, "chore"))
+	_, err = r.UpdateById(ctx, chore.Id, &label.Label{Name: "bug"})
+	assert.ErrorIs(t, err, label.ErrNameTaken)
+	_, err = r.UpdateById(ctx, chore.Id, &label.Label{Name: "admin", Color: "#ff0000"})
+	assert.NoError(t, err)
+
+	labels, err := r.FindByUserId(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "admin", labels[0].Name)
+	assert.Equal(t, "#ff0000", labels[0].Color)
+
+	found, err := r.FindByIds(ctx, []uuid.UUID{bug.Id, uuid.New()})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	assert.NoError(t, r.DeleteById(ctx, chore.Id))
+	assert.Equal(t, sql.ErrNoRows, r.DeleteById(ctx, chore.Id))
+}
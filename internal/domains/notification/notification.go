@@ -1,7 +1,67 @@
 package notification
 
-import "taskhub/pkg/base/entity"
+import (
+	"context"
+	"taskhub/pkg/base/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	TypeReminder      NotificationType = "reminder"
+	TypeTaskCreated   NotificationType = "task_created"
+	TypeTaskUpdated   NotificationType = "task_updated"
+	TypeTaskCompleted NotificationType = "task_completed"
+	TypeTaskDeleted   NotificationType = "task_deleted"
+	TypeTaskReopened  NotificationType = "task_reopened"
+)
 
 type Notification struct {
 	entity.BaseEntity
+	UserID  uuid.UUID        `json:"user_id"`
+	TaskID  *uuid.UUID       `json:"task_id,omitempty"`
+	Type    NotificationType `json:"type"`
+	Title   string           `json:"title"`
+	Message string           `json:"message"`
+	ReadAt  *time.Time       `json:"read_at,omitempty"`
+}
+
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+func (n *Notification) MarkAsRead() {
+	if n.ReadAt != nil {
+		return
+	}
+	now := time.Now()
+	n.ReadAt = &now
+}
+
+type NotificationFilter struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Limit      int
+}
+
+// NotificationStore persists the per-user notification inbox. Create is
+// idempotent on the notification id so redelivered events are stored once.
+type NotificationStore interface {
+	Create(ctx context.Context, n *Notification) (*Notification, error)
+	FindByUserId(ctx context.Context, filter *NotificationFilter) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// ReminderLog records which reminder thresholds have fired for a task
+// deadline so each threshold is sent exactly once, even across replicas.
+type ReminderLog interface {
+	// Claim records the reminder and reports whether this caller won it.
+	Claim(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) (bool, error)
+	// Release forgets a claimed reminder so it can be retried.
+	Release(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) error
 }
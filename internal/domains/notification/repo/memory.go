@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/notification"
+	baserepo "taskhub/pkg/base/repo"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type reminderKey struct {
+	taskID    uuid.UUID
+	deadline  int64
+	threshold time.Duration
+}
+
+// MemoryReminderLog is an in-memory notification.ReminderLog for tests and
+// local development.
+type MemoryReminderLog struct {
+	mu      sync.Mutex
+	claimed map[reminderKey]time.Time
+}
+
+var _ notification.ReminderLog = (*MemoryReminderLog)(nil)
+
+func NewMemoryReminderLog() *MemoryReminderLog {
+	return &MemoryReminderLog{claimed: make(map[reminderKey]time.Time)}
+}
+
+func (l *MemoryReminderLog) Claim(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := reminderKey{taskID: taskID, deadline: deadline.UnixNano(), threshold: threshold}
+	if _, ok := l.claimed[key]; ok {
+		return false, nil
+	}
+	l.claimed[key] = time.Now()
+
+	return true, nil
+}
+
+func (l *MemoryReminderLog) Release(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.claimed, reminderKey{taskID: taskID, deadline: deadline.UnixNano(), threshold: threshold})
+	return nil
+}
+
+// MemoryNotificationRepository is an in-memory notification.NotificationStore
+// for tests and local development.
+type MemoryNotificationRepository struct {
+	store *baserepo.MemoryRepository[*notification.Notification]
+}
+
+var _ notification.NotificationStore = (*MemoryNotificationRepository)(nil)
+
+func NewMemoryNotificationRepository() *MemoryNotificationRepository {
+	return &MemoryNotificationRepository{
+		store: baserepo.NewMemoryRepository(cloneNotification),
+	}
+}
+
+func cloneNotification(n *notification.Notification) *notification.Notification {
+	c := *n
+	return &c
+}
+
+func (r *MemoryNotificationRepository) Create(ctx context.Context, n *notification.Notification) (*notification.Notification, error) {
+	existing, err := r.store.FindById(ctx, n.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return n, nil
+	}
+
+	return r.store.Create(ctx, n)
+}
+
+func (r *MemoryNotificationRepository) FindByUserId(ctx context.Context, filter *notification.NotificationFilter) ([]*notification.Notification, error) {
+	notifications, err := r.store.FindAll(ctx, func(n *notification.Notification) bool {
+		return n.UserID == filter.UserID && (!filter.UnreadOnly || !n.IsRead())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(notifications) > filter.Limit {
+		notifications = notifications[:filter.Limit]
+	}
+
+	return notifications, nil
+}
+
+func (r *MemoryNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	unread, err := r.FindByUserId(ctx, &notification.NotificationFilter{UserID: userID, UnreadOnly: true})
+	return len(unread), err
+}
+
+func (r *MemoryNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	return r.store.Modify(ctx, id, func(n *notification.Notification) error {
+		if n.UserID != userID {
+			return sql.ErrNoRows
+		}
+		n.MarkAsRead()
+		return nil
+	})
+}
+
+func (r *MemoryNotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	unread, err := r.FindByUserId(ctx, &notification.NotificationFilter{UserID: userID, UnreadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+
+	var marked int64
+	for _, n := range unread {
+		if err := r.MarkAsRead(ctx, n.Id, userID); err == nil {
+			marked++
+		}
+	}
+
+	return marked, nil
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/notification"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNotification(userID uuid.UUID, createdAt time.Time) *notification.Notification {
+	return &notification.Notification{
+		BaseEntity: entity.BaseEntity{Id: uuid.New(), CreatedAt: createdAt},
+		UserID:     userID,
+		Type:       notification.TypeReminder,
+		Title:      "Reminder",
+	}
+}
+
+func TestMemoryNotificationRepository_CreateIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryNotificationRepository()
+	n := newNotification(uuid.New(), time.Now())
+
+	_, err := repo.Create(ctx, n)
+	require.NoError(t, err)
+	n.Title = "Changed"
+	_, err = repo.Create(ctx, n)
+	require.NoError(t, err)
+
+	found, err := repo.FindByUserId(ctx, &notification.NotificationFilter{UserID: n.UserID})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Reminder", found[0].Title)
+}
+
+func TestMemoryNotificationRepository_FindByUserId(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryNotificationRepository()
+	userID := uuid.New()
+	now := time.Now()
+
+	older, _ := repo.Create(ctx, newNotification(userID, now.Add(-time.Hour)))
+	newer, _ := repo.Create(ctx, newNotification(userID, now))
+	repo.Create(ctx, newNotification(uuid.New(), now))
+
+	found, err := repo.FindByUserId(ctx, &notification.NotificationFilter{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, newer.Id, found[0].Id)
+	assert.Equal(t, older.Id, found[1].Id)
+
+	found, err = repo.FindByUserId(ctx, &notification.NotificationFilter{UserID: userID, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+}
+
+func TestMemoryNotificationRepository_MarkAsRead(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryNotificationRepository()
+	userID := uuid.New()
+	n, _ := repo.Create(ctx, newNotification(userID, time.Now()))
+	repo.Create(ctx, newNotification(userID, time.Now()))
+
+	assert.Error(t, repo.MarkAsRead(ctx, n.Id, uuid.New()))
+	require.NoError(t, repo.MarkAsRead(ctx, n.Id, userID))
+
+	unread, err := repo.CountUnread(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+
+	marked, err := repo.MarkAllAsRead(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), marked)
+
+	unread, _ = repo.CountUnread(ctx, userID)
+	assert.Equal(t, 0, unread)
+}
@@ -1,4 +1,131 @@
 package repo
 
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"taskhub/config"
+	"taskhub/internal/domains/notification"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var NotificationRepositoryModule = fx.Module(
+	"notification-repo",
+	fx.Provide(fx.Annotate(NewNotificationRepository, fx.As(new(notification.NotificationStore)))),
+)
+
 type NotificationRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ notification.NotificationStore = (*NotificationRepository)(nil)
+
+func NewNotificationRepository(config *config.Config, logger *logger.Logger) *NotificationRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &NotificationRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) (*notification.Notification, error) {
+	query := `INSERT INTO notifications (id, user_id, task_id, type, title, message, read_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              ON CONFLICT (id) DO NOTHING`
+
+	_, err := r.conn.ExecContext(ctx, query,
+		n.Id, n.UserID, n.TaskID, n.Type, n.Title, n.Message, n.ReadAt, n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func (r *NotificationRepository) FindByUserId(ctx context.Context, filter *notification.NotificationFilter) ([]*notification.Notification, error) {
+	query := `SELECT id, user_id, task_id, type, title, message, read_at, created_at
+              FROM notifications WHERE user_id = $1`
+
+	if filter.UnreadOnly {
+		query += " AND read_at IS NULL"
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := r.conn.QueryContext(ctx, query, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*notification.Notification
+	for rows.Next() {
+		var n notification.Notification
+		var taskID sql.NullString
+		var readAt sql.NullTime
+
+		if err := rows.Scan(&n.Id, &n.UserID, &taskID, &n.Type, &n.Title, &n.Message, &readAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		if taskID.Valid {
+			tid, _ := uuid.Parse(taskID.String)
+			n.TaskID = &tid
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.conn.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`
+
+	result, err := r.conn.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+
+	result, err := r.conn.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
 }
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/internal/domains/notification"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var ReminderRepositoryModule = fx.Module(
+	"reminder-repo",
+	fx.Provide(fx.Annotate(NewReminderRepository, fx.As(new(notification.ReminderLog)))),
+)
+
+type ReminderRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ notification.ReminderLog = (*ReminderRepository)(nil)
+
+func NewReminderRepository(config *config.Config, logger *logger.Logger) *ReminderRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &ReminderRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *ReminderRepository) Claim(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) (bool, error) {
+	query := `INSERT INTO task_reminders (task_id, deadline, threshold_minutes, sent_at)
+              VALUES ($1, $2, $3, NOW())
+              ON CONFLICT DO NOTHING`
+
+	result, err := r.conn.ExecContext(ctx, query, taskID, deadline, int(threshold.Minutes()))
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+func (r *ReminderRepository) Release(ctx context.Context, taskID uuid.UUID, deadline time.Time, threshold time.Duration) error {
+	query := `DELETE FROM task_reminders WHERE task_id = $1 AND deadline = $2 AND threshold_minutes = $3`
+
+	_, err := r.conn.ExecContext(ctx, query, taskID, deadline, int(threshold.Minutes()))
+	return err
+}
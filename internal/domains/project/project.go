@@ -0,0 +1,38 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/base/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNameTaken = errors.New("project name already taken")
+
+// Project groups a user's tasks. DefaultPriority is given to tasks created
+// in the project without a priority.
+type Project struct {
+	entity.BaseEntity
+	UserID          uuid.UUID         `json:"user_id"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	DefaultPriority task.TaskPriority `json:"default_priority"`
+	// ArchivedAt is set while the project and its tasks are archived.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// ProjectStore persists projects. Names are unique per user; Create and
+// UpdateById report a clash as ErrNameTaken. Deleting a project takes its
+// tasks out of it.
+type ProjectStore interface {
+	Create(ctx context.Context, p *Project) (*Project, error)
+	FindById(ctx context.Context, id uuid.UUID) (*Project, error)
+	// FindByUserId returns the user's projects ordered by name, leaving out
+	// archived ones unless archived is set.
+	FindByUserId(ctx context.Context, userID uuid.UUID, archived bool) ([]*Project, error)
+	UpdateById(ctx context.Context, id uuid.UUID, p *Project) (*Project, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+}
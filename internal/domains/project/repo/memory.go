@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/project"
+	baserepo "taskhub/pkg/base/repo"
+
+	"github.com/google/uuid"
+)
+
+// MemoryProjectRepository is an in-memory project.ProjectStore for tests and
+// local development. Unlike ProjectRepository, deleting a project leaves its
+// id on the tasks of a memory task repository.
+type MemoryProjectRepository struct {
+	// mu makes the name uniqueness check atomic with the write.
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*project.Project]
+}
+
+var _ project.ProjectStore = (*MemoryProjectRepository)(nil)
+
+func NewMemoryProjectRepository() *MemoryProjectRepository {
+	return &MemoryProjectRepository{
+		store: baserepo.NewMemoryRepository(cloneProject),
+	}
+}
+
+func cloneProject(p *project.Project) *project.Project {
+	c := *p
+	return &c
+}
+
+func (r *MemoryProjectRepository) nameTaken(ctx context.Context, p *project.Project) (bool, error) {
+	clashes, err := r.store.FindAll(ctx, func(existing *project.Project) bool {
+		return existing.UserID == p.UserID && existing.Name == p.Name && existing.Id != p.Id
+	})
+	return len(clashes) > 0, err
+}
+
+func (r *MemoryProjectRepository) Create(ctx context.Context, p *project.Project) (*project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken, err := r.nameTaken(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, project.ErrNameTaken
+	}
+
+	return r.store.Create(ctx, p)
+}
+
+func (r *MemoryProjectRepository) FindById(ctx context.Context, id uuid.UUID) (*project.Project, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryProjectRepository) FindByUserId(ctx context.Context, userID uuid.UUID, archived bool) ([]*project.Project, error) {
+	projects, err := r.store.FindAll(ctx, func(p *project.Project) bool {
+		return p.UserID == userID && (archived || p.ArchivedAt == nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	return projects, nil
+}
+
+func (r *MemoryProjectRepository) UpdateById(ctx context.Context, id uuid.UUID, p *project.Project) (*project.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.store.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	existing.Name = p.Name
+	taken, err := r.nameTaken(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, project.ErrNameTaken
+	}
+
+	err = r.store.Modify(ctx, id, func(existing *project.Project) error {
+		existing.Name = p.Name
+		existing.Description = p.Description
+		existing.DefaultPriority = p.DefaultPriority
+		existing.ArchivedAt = p.ArchivedAt
+		existing.UpdateAt = p.UpdateAt
+		existing.UpdateBy = p.UpdateBy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Id = id
+	return p, nil
+}
+
+func (r *MemoryProjectRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
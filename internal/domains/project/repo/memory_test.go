@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/project"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProject(userID uuid.UUID, name string) *project.Project {
+	return &project.Project{
+		BaseEntity:      entity.BaseEntity{Id: uuid.New(), CreatedAt: time.Now(), CreatedBy: userID},
+		UserID:          userID,
+		Name:            name,
+		DefaultPriority: task.PriorityMedium,
+	}
+}
+
+func TestMemoryProjectRepository_NamesAreUniquePerUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryProjectRepository()
+	userID := uuid.New()
+
+	_, err := r.Create(ctx, newProject(userID, "Launch"))
+	assert.NoError(t, err)
+	_, err = r.Create(ctx, newProject(userID, "Launch"))
+	assert.ErrorIs(t, err, project.ErrNameTaken)
+	_, err = r.Create(ctx, newProject(uuid.New(), "Launch"))
+	assert.NoError(t, err)
+
+	chores, _ := r.Create(ctx, newProject(userID, "Chores"))
+	_, err = r.UpdateById(ctx, chores.Id, &project.Project{Name: "Launch"})
+	assert.ErrorIs(t, err, project.ErrNameTaken)
+
+	assert.NoError(t, r.DeleteById(ctx, chores.Id))
+	assert.Equal(t, sql.ErrNoRows, r.DeleteById(ctx, chores.Id))
+}
+
+func TestMemoryProjectRepository_Archived(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryProjectRepository()
+	userID := uuid.New()
+
+	r.Create(ctx, newProject(userID, "Live"))
+	old, _ := r.Create(ctx, newProject(userID, "Old"))
+
+	now := time.Now()
+	_, err := r.UpdateById(ctx, old.Id, &project.Project{Name: "Old", DefaultPriority: task.PriorityLow, ArchivedAt: &now})
+	assert.NoError(t, err)
+
+	projects, err := r.FindByUserId(ctx, userID, false)
+	assert.NoError(t, err)
+	assert.Len(t, projects, 1)
+	assert.Equal(t, "Live", projects[0].Name)
+
+	projects, err = r.FindByUserId(ctx, userID, true)
+	assert.NoError(t, err)
+	assert.Len(t, projects, 2)
+	assert.Equal(t, "Old", projects[1].Name)
+	assert.NotNil(t, projects[1].ArchivedAt)
+	assert.Equal(t, task.PriorityLow, projects[1].DefaultPriority)
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/config"
+	"taskhub/internal/domains/project"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var ProjectRepositoryModule = fx.Module(
+	"project-repo",
+	fx.Provide(fx.Annotate(NewProjectRepository, fx.As(new(project.ProjectStore)))),
+)
+
+type ProjectRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ project.ProjectStore = (*ProjectRepository)(nil)
+
+func NewProjectRepository(config *config.Config, logger *logger.Logger) *ProjectRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &ProjectRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// uniqueViolation maps the (user_id, name) constraint to
+// project.ErrNameTaken.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return project.ErrNameTaken
+	}
+	return err
+}
+
+const projectColumns = `id, user_id, name, description, default_priority, archived_at, created_at, created_by, updated_at`
+
+func (r *ProjectRepository) Create(ctx context.Context, p *project.Project) (*project.Project, error) {
+	query := `INSERT INTO projects (id, user_id, name, description, default_priority, created_at, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, p.Id, p.UserID, p.Name, p.Description, p.DefaultPriority, p.CreatedAt, p.CreatedBy)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return p, nil
+}
+
+func (r *ProjectRepository) FindById(ctx context.Context, id uuid.UUID) (*project.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1`
+
+	p, err := scanProject(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *ProjectRepository) FindByUserId(ctx context.Context, userID uuid.UUID, archived bool) ([]*project.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE user_id = $1`
+	if !archived {
+		query += ` AND archived_at IS NULL`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*project.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+func scanProject(row interface{ Scan(...any) error }) (*project.Project, error) {
+	var p project.Project
+	var archivedAt, updatedAt sql.NullTime
+
+	err := row.Scan(&p.Id, &p.UserID, &p.Name, &p.Description, &p.DefaultPriority, &archivedAt, &p.CreatedAt, &p.CreatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		p.ArchivedAt = &archivedAt.Time
+	}
+	if updatedAt.Valid {
+		p.UpdateAt = &updatedAt.Time
+	}
+
+	return &p, nil
+}
+
+func (r *ProjectRepository) UpdateById(ctx context.Context, id uuid.UUID, p *project.Project) (*project.Project, error) {
+	query := `UPDATE projects SET name = $1, description = $2, default_priority = $3, archived_at = $4, updated_at = $5, updated_by = $6
+              WHERE id = $7`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, p.Name, p.Description, p.DefaultPriority, p.ArchivedAt, p.UpdateAt, p.UpdateBy, id)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	p.Id = id
+	return p, nil
+}
+
+// DeleteById deletes a project. The tasks.project_id foreign key sets its
+// tasks' project to NULL.
+func (r *ProjectRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
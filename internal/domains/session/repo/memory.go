@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/internal/domains/session"
+	baserepo "taskhub/pkg/base/repo"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryRefreshTokenRepositoryModule = fx.Module(
+	"refresh-token-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryRefreshTokenRepository, fx.As(new(session.RefreshTokenStore)))),
+)
+
+// MemoryRefreshTokenRepository is an in-memory session.RefreshTokenStore for
+// tests and local development.
+type MemoryRefreshTokenRepository struct {
+	store *baserepo.MemoryRepository[*session.RefreshToken]
+}
+
+var _ session.RefreshTokenStore = (*MemoryRefreshTokenRepository)(nil)
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		store: baserepo.NewMemoryRepository(cloneRefreshToken),
+	}
+}
+
+func cloneRefreshToken(t *session.RefreshToken) *session.RefreshToken {
+	c := *t
+	return &c
+}
+
+func (r *MemoryRefreshTokenRepository) Create(ctx context.Context, t *session.RefreshToken) error {
+	_, err := r.store.Create(ctx, t)
+	return err
+}
+
+func (r *MemoryRefreshTokenRepository) FindById(ctx context.Context, id uuid.UUID) (*session.RefreshToken, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryRefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	rotated := false
+	err := r.store.Modify(ctx, id, func(existing *session.RefreshToken) error {
+		if existing.RotatedAt == nil && existing.RevokedAt == nil {
+			existing.RotatedAt = &at
+			rotated = true
+		}
+		return nil
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return rotated, err
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	return r.revoke(ctx, at, func(t *session.RefreshToken) bool {
+		return t.FamilyID == familyID
+	})
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeByUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.revoke(ctx, at, func(t *session.RefreshToken) bool {
+		return t.UserID == userID
+	})
+}
+
+func (r *MemoryRefreshTokenRepository) revoke(ctx context.Context, at time.Time, match func(*session.RefreshToken) bool) error {
+	tokens, err := r.store.FindAll(ctx, func(t *session.RefreshToken) bool {
+		return t.RevokedAt == nil && match(t)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		err := r.store.Modify(ctx, t.Id, func(existing *session.RefreshToken) error {
+			if existing.RevokedAt == nil {
+				existing.RevokedAt = &at
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/session"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRefreshTokenRepository_RotateAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRefreshTokenRepository()
+	userID, familyID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	first := &session.RefreshToken{Id: uuid.New(), FamilyID: familyID, UserID: userID, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	second := &session.RefreshToken{Id: uuid.New(), FamilyID: familyID, UserID: userID, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	other := &session.RefreshToken{Id: uuid.New(), FamilyID: uuid.New(), UserID: userID, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	for _, tok := range []*session.RefreshToken{first, second, other} {
+		require.NoError(t, r.Create(ctx, tok))
+	}
+
+	rotated, err := r.MarkRotated(ctx, first.Id, now)
+	require.NoError(t, err)
+	assert.True(t, rotated)
+	rotated, err = r.MarkRotated(ctx, first.Id, now)
+	require.NoError(t, err)
+	assert.False(t, rotated)
+	rotated, err = r.MarkRotated(ctx, uuid.New(), now)
+	require.NoError(t, err)
+	assert.False(t, rotated)
+
+	require.NoError(t, r.RevokeFamily(ctx, familyID, now))
+	found, err := r.FindById(ctx, second.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+	rotated, err = r.MarkRotated(ctx, second.Id, now)
+	require.NoError(t, err)
+	assert.False(t, rotated)
+
+	found, err = r.FindById(ctx, other.Id)
+	require.NoError(t, err)
+	assert.Nil(t, found.RevokedAt)
+
+	later := now.Add(time.Minute)
+	require.NoError(t, r.RevokeByUser(ctx, userID, later))
+	found, err = r.FindById(ctx, other.Id)
+	require.NoError(t, err)
+	assert.Equal(t, later, *found.RevokedAt)
+	found, err = r.FindById(ctx, second.Id)
+	require.NoError(t, err)
+	assert.Equal(t, now, *found.RevokedAt)
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/internal/domains/session"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var RefreshTokenRepositoryModule = fx.Module(
+	"refresh-token-repo",
+	fx.Provide(fx.Annotate(NewRefreshTokenRepository, fx.As(new(session.RefreshTokenStore)))),
+)
+
+type RefreshTokenRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ session.RefreshTokenStore = (*RefreshTokenRepository)(nil)
+
+func NewRefreshTokenRepository(config *config.Config, logger *logger.Logger) *RefreshTokenRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &RefreshTokenRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, t *session.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, family_id, user_id, device, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, t.Id, t.FamilyID, t.UserID, t.Device, t.ExpiresAt, t.CreatedAt)
+	return err
+}
+
+func (r *RefreshTokenRepository) FindById(ctx context.Context, id uuid.UUID) (*session.RefreshToken, error) {
+	query := `SELECT id, family_id, user_id, device, expires_at, created_at, rotated_at, revoked_at
+              FROM refresh_tokens WHERE id = $1`
+
+	var t session.RefreshToken
+	var rotatedAt, revokedAt sql.NullTime
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id).Scan(
+		&t.Id, &t.FamilyID, &t.UserID, &t.Device, &t.ExpiresAt, &t.CreatedAt, &rotatedAt, &revokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if rotatedAt.Valid {
+		t.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+
+	return &t, nil
+}
+
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	query := `UPDATE refresh_tokens SET rotated_at = $1 WHERE id = $2 AND rotated_at IS NULL AND revoked_at IS NULL`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, familyID)
+	return err
+}
+
+func (r *RefreshTokenRepository) RevokeByUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, userID)
+	return err
+}
@@ -0,0 +1,43 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Id is
+// the token's jti. Each login starts a family, and every refresh replaces
+// the presented token with a new one in the same family.
+type RefreshToken struct {
+	Id       uuid.UUID `json:"id"`
+	FamilyID uuid.UUID `json:"family_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	// Device is the user agent that logged in.
+	Device    string    `json:"device"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	// RotatedAt is set once the token has been exchanged for its successor,
+	// RevokedAt once its family has been revoked.
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (t *RefreshToken) GetId() uuid.UUID {
+	return t.Id
+}
+
+// RefreshTokenStore persists refresh tokens.
+type RefreshTokenStore interface {
+	Create(ctx context.Context, t *RefreshToken) error
+	FindById(ctx context.Context, id uuid.UUID) (*RefreshToken, error)
+	// MarkRotated sets RotatedAt on a live token that has not been rotated
+	// yet, reporting whether it did. Of two concurrent refreshes with the
+	// same token, only one succeeds.
+	MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// RevokeFamily revokes every token of a family, and RevokeByUser every
+	// token of a user. Tokens already revoked keep their RevokedAt.
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+	RevokeByUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,21 @@
+package task
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidDependency = errors.New("invalid dependency")
+	ErrDependencyExists  = errors.New("dependency already exists")
+	ErrDependencyCycle   = errors.New("dependency would create a cycle")
+	ErrBlocked           = errors.New("task is blocked by open tasks")
+)
+
+// Dependency is an edge of the dependency graph: TaskID cannot start or
+// finish until BlockedByID is done.
+type Dependency struct {
+	TaskID      uuid.UUID `json:"task_id"`
+	BlockedByID uuid.UUID `json:"blocked_by_id"`
+}
@@ -0,0 +1,162 @@
+package task
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeAction is what happened to a task in a history entry.
+type ChangeAction string
+
+const (
+	ActionCreated ChangeAction = "created"
+	ActionUpdated ChangeAction = "updated"
+	ActionDeleted ChangeAction = "deleted"
+)
+
+// Change is an entry in a task's history: the task being created or deleted,
+// or one of its fields changing. Old and New are the field's JSON values
+// before and after the change, and null when it had none.
+type Change struct {
+	Id        uuid.UUID       `json:"id"`
+	TaskID    uuid.UUID       `json:"task_id"`
+	ActorID   uuid.UUID       `json:"actor_id"`
+	Action    ChangeAction    `json:"action"`
+	Field     string          `json:"field,omitempty"`
+	Old       json.RawMessage `json:"old"`
+	New       json.RawMessage `json:"new"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// FieldBlockedBy is the field dependency changes are recorded under: New is
+// the blocker added, Old the blocker removed.
+const FieldBlockedBy = "blocked_by"
+
+// FieldChange is the before and after value of a task field.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Diff returns the user-editable fields that differ between before and
+// after, keyed by their JSON name.
+func Diff(before, after *Task) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+
+	if before.Title != after.Title {
+		changes["title"] = FieldChange{From: before.Title, To: after.Title}
+	}
+	if before.Description != after.Description {
+		changes["description"] = FieldChange{From: before.Description, To: after.Description}
+	}
+	if before.Status != after.Status {
+		changes["status"] = FieldChange{From: before.Status, To: after.Status}
+	}
+	if before.Priority != after.Priority {
+		changes["priority"] = FieldChange{From: before.Priority, To: after.Priority}
+	}
+	if !sameTime(before.Deadline, after.Deadline) {
+		changes["deadline"] = FieldChange{From: before.Deadline, To: after.Deadline}
+	}
+	if !sameID(before.ParentID, after.ParentID) {
+		changes["parent_id"] = FieldChange{From: before.ParentID, To: after.ParentID}
+	}
+	if !sameID(before.ProjectID, after.ProjectID) {
+		changes["project_id"] = FieldChange{From: before.ProjectID, To: after.ProjectID}
+	}
+	if !slices.Equal(before.Checklist, after.Checklist) {
+		changes["checklist"] = FieldChange{From: before.Checklist, To: after.Checklist}
+	}
+	if !slices.Equal(before.LabelIDs, after.LabelIDs) {
+		changes["labels"] = FieldChange{From: before.LabelIDs, To: after.LabelIDs}
+	}
+	if before.RecurrenceRule() != after.RecurrenceRule() {
+		changes["recurrence"] = FieldChange{From: before.RecurrenceRule(), To: after.RecurrenceRule()}
+	}
+
+	return changes
+}
+
+// RecurrenceRule returns the task's RRULE, or "" when it does not repeat.
+func (t *Task) RecurrenceRule() string {
+	if t.Recurrence == nil {
+		return ""
+	}
+	return t.Recurrence.Rule
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// NewChange builds a history entry, encoding the field's old and new values
+// as JSON.
+func NewChange(taskID, actorID uuid.UUID, action ChangeAction, field string, oldValue, newValue any, at time.Time) (*Change, error) {
+	c := &Change{
+		Id:        uuid.New(),
+		TaskID:    taskID,
+		ActorID:   actorID,
+		Action:    action,
+		Field:     field,
+		CreatedAt: at,
+	}
+
+	var err error
+	if c.Old, err = encodeValue(oldValue); err != nil {
+		return nil, err
+	}
+	if c.New, err = encodeValue(newValue); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// UpdateChanges returns a history entry for each field that differs between
+// before and after, ordered by field name.
+func UpdateChanges(before, after *Task, actorID uuid.UUID, at time.Time) ([]*Change, error) {
+	diff := Diff(before, after)
+	fields := make([]string, 0, len(diff))
+	for field := range diff {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := make([]*Change, 0, len(fields))
+	for _, field := range fields {
+		c, err := NewChange(before.Id, actorID, ActionUpdated, field, diff[field].From, diff[field].To, at)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, nil
+}
+
+// encodeValue encodes a field value, leaving absent values nil so they are
+// stored as NULL.
+func encodeValue(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
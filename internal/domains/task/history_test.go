@@ -0,0 +1,52 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	deadline := time.Now()
+	sameDeadline := deadline.Add(0)
+	before := &Task{Title: "A", Status: StatusTodo, Priority: PriorityLow, Deadline: &deadline}
+
+	after := *before
+	after.Deadline = &sameDeadline
+	assert.Empty(t, Diff(before, &after))
+
+	after.Status = StatusDone
+	after.Deadline = nil
+	assert.Equal(t, map[string]FieldChange{
+		"status":   {From: StatusTodo, To: StatusDone},
+		"deadline": {From: &deadline, To: (*time.Time)(nil)},
+	}, Diff(before, &after))
+}
+
+func TestUpdateChanges(t *testing.T) {
+	deadline := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	before := &Task{Title: "A", Status: StatusTodo, Deadline: &deadline}
+	before.Id = uuid.New()
+	after := *before
+	after.Status = StatusDone
+	after.Deadline = nil
+	actorID, at := uuid.New(), time.Now()
+
+	changes, err := UpdateChanges(before, &after, actorID, at)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, "deadline", changes[0].Field)
+	assert.JSONEq(t, `"2026-03-10T15:00:00Z"`, string(changes[0].Old))
+	assert.Nil(t, changes[0].New)
+
+	assert.Equal(t, "status", changes[1].Field)
+	assert.Equal(t, ActionUpdated, changes[1].Action)
+	assert.Equal(t, before.Id, changes[1].TaskID)
+	assert.Equal(t, actorID, changes[1].ActorID)
+	assert.JSONEq(t, `"todo"`, string(changes[1].Old))
+	assert.JSONEq(t, `"done"`, string(changes[1].New))
+}
@@ -0,0 +1,273 @@
+package task
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type SortField string
+
+const (
+	SortCreatedAt SortField = "created_at"
+	SortDeadline  SortField = "deadline"
+	SortPriority  SortField = "priority"
+	SortTitle     SortField = "title"
+	// SortRelevance orders full-text search results by rank and is only
+	// valid with a search.
+	SortRelevance SortField = "relevance"
+)
+
+// Sort orders a task listing. Ties are broken by id in the same direction so
+// every task has a unique position and keyset cursors never skip or repeat
+// rows.
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// DefaultSort lists the newest tasks first.
+var DefaultSort = Sort{Field: SortCreatedAt, Desc: true}
+
+// ParseSort parses a field name and an "asc" or "desc" order. Empty values
+// fall back to DefaultSort's field and to descending order.
+func ParseSort(field, order string) (Sort, error) {
+	s := DefaultSort
+	if field != "" {
+		s.Field = SortField(field)
+	}
+
+	switch strings.ToLower(order) {
+	case "", "desc":
+		s.Desc = true
+	case "asc":
+		s.Desc = false
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+
+	switch s.Field {
+	case SortCreatedAt, SortDeadline, SortPriority, SortTitle, SortRelevance:
+		return s, nil
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return string(s.Field) + ":desc"
+	}
+	return string(s.Field) + ":asc"
+}
+
+// PriorityRank orders priorities from low to high; unknown priorities sort
+// below low.
+func PriorityRank(p TaskPriority) int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// infinity stands in for a missing deadline so tasks without one sort after
+// every dated task in ascending order.
+var infinity = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+func deadlineKey(t *Task) time.Time {
+	if t.Deadline == nil {
+		return infinity
+	}
+	return *t.Deadline
+}
+
+func rank(t *Task) float64 {
+	if t.Match == nil {
+		return 0
+	}
+	return t.Match.Rank
+}
+
+// Compare reports whether a sorts before (-1), after (1) or at the same
+// position as b.
+func (s Sort) Compare(a, b *Task) int {
+	c := 0
+	switch s.Field {
+	case SortCreatedAt:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case SortDeadline:
+		c = deadlineKey(a).Compare(deadlineKey(b))
+	case SortPriority:
+		c = PriorityRank(a.Priority) - PriorityRank(b.Priority)
+	case SortTitle:
+		c = strings.Compare(a.Title, b.Title)
+	case SortRelevance:
+		c = cmp.Compare(rank(a), rank(b))
+	}
+	if c == 0 {
+		c = bytes.Compare(a.Id[:], b.Id[:])
+	}
+
+	switch {
+	case c == 0:
+		return 0
+	case s.Desc == (c < 0):
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Cursor is the position of the last task on a page. It is opaque to
+// clients; Encode and DecodeCursor round-trip it through a URL-safe string.
+type Cursor struct {
+	Sort  Sort
+	Value string
+	ID    uuid.UUID
+}
+
+// CursorAt returns the cursor positioned at t under s.
+func CursorAt(s Sort, t *Task) *Cursor {
+	c := &Cursor{Sort: s, ID: t.Id}
+	switch s.Field {
+	case SortCreatedAt:
+		c.Value = t.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortDeadline:
+		if t.Deadline == nil {
+			c.Value = "infinity"
+		} else {
+			c.Value = t.Deadline.UTC().Format(time.RFC3339Nano)
+		}
+	case SortPriority:
+		c.Value = strconv.Itoa(PriorityRank(t.Priority))
+	case SortTitle:
+		c.Value = t.Title
+	case SortRelevance:
+		c.Value = strconv.FormatFloat(rank(t), 'g', -1, 64)
+	}
+	return c
+}
+
+type cursorPayload struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(cursorPayload{Sort: c.Sort.String(), Value: c.Value, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode. A cursor only makes sense
+// under the sort it was issued for, so a mismatch is reported as
+// ErrInvalidCursor.
+func DecodeCursor(encoded string, s Sort) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil || p.Sort != s.String() {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{Sort: s, Value: p.Value, ID: p.ID}
+	if _, err := c.position(); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// position rebuilds a task holding only the cursor's sort key and id, so it
+// can be compared with Sort.Compare.
+func (c *Cursor) position() (*Task, error) {
+	t := &Task{}
+	t.Id = c.ID
+	switch c.Sort.Field {
+	case SortCreatedAt:
+		at, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, err
+		}
+		t.CreatedAt = at
+	case SortDeadline:
+		if c.Value != "infinity" {
+			at, err := time.Parse(time.RFC3339Nano, c.Value)
+			if err != nil {
+				return nil, err
+			}
+			t.Deadline = &at
+		}
+	case SortPriority:
+		rank, err := strconv.Atoi(c.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range []TaskPriority{PriorityLow, PriorityMedium, PriorityHigh} {
+			if PriorityRank(p) == rank {
+				t.Priority = p
+			}
+		}
+	case SortTitle:
+		t.Title = c.Value
+	case SortRelevance:
+		r, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return nil, err
+		}
+		t.Match = &SearchMatch{Rank: r}
+	}
+	return t, nil
+}
+
+// Follows reports whether t comes after the cursor.
+func (c *Cursor) Follows(t *Task) bool {
+	pos, err := c.position()
+	if err != nil {
+		return false
+	}
+	return c.Sort.Compare(pos, t) < 0
+}
+
+// PageRequest selects one page of a listing.
+type PageRequest struct {
+	Sort  Sort
+	Limit int
+	// After is the cursor of the previous page's last task; nil starts at
+	// the beginning.
+	After *Cursor
+}
+
+// Page is one page of tasks. Next is nil on the last page; Total counts every
+// task matching the filter across all pages.
+type Page struct {
+	Tasks []*Task
+	Next  *Cursor
+	Total int
+}
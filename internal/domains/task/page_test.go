@@ -0,0 +1,75 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	s, err := ParseSort("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSort, s)
+
+	s, err = ParseSort("deadline", "ASC")
+	assert.NoError(t, err)
+	assert.Equal(t, Sort{Field: SortDeadline}, s)
+
+	_, err = ParseSort("status", "asc")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = ParseSort("title", "sideways")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestSort_Compare(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	dated := &Task{Title: "b", Priority: PriorityLow, Deadline: &soon}
+	undated := &Task{Title: "a", Priority: PriorityHigh}
+
+	assert.Equal(t, -1, Sort{Field: SortDeadline}.Compare(dated, undated))
+	assert.Equal(t, 1, Sort{Field: SortDeadline, Desc: true}.Compare(dated, undated))
+	assert.Equal(t, -1, Sort{Field: SortPriority}.Compare(dated, undated))
+	assert.Equal(t, 1, Sort{Field: SortTitle}.Compare(dated, undated))
+
+	dated.Id = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	twin := *dated
+	twin.Id = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	assert.Equal(t, -1, Sort{Field: SortTitle}.Compare(dated, &twin))
+	assert.Equal(t, 0, Sort{Field: SortTitle}.Compare(dated, dated))
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	deadline := time.Date(2025, 3, 1, 9, 30, 0, 123456000, time.UTC)
+	tasks := []*Task{
+		{Title: "Report", Priority: PriorityMedium, Deadline: &deadline},
+		{Title: "Undated"},
+	}
+
+	for _, field := range []SortField{SortCreatedAt, SortDeadline, SortPriority, SortTitle} {
+		for _, tk := range tasks {
+			tk.Id = uuid.New()
+			tk.CreatedAt = time.Now()
+			s := Sort{Field: field}
+
+			cursor, err := DecodeCursor(CursorAt(s, tk).Encode(), s)
+			assert.NoError(t, err, field)
+			assert.Equal(t, tk.Id, cursor.ID)
+			assert.False(t, cursor.Follows(tk), field)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tk := &Task{Title: "Task"}
+	tk.Id = uuid.New()
+	encoded := CursorAt(Sort{Field: SortTitle}, tk).Encode()
+
+	_, err := DecodeCursor(encoded, Sort{Field: SortTitle, Desc: true})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodeCursor("not a cursor", DefaultSort)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,259 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+type QueryField string
+
+const (
+	QueryStatus   QueryField = "status"
+	QueryCategory QueryField = "category"
+	QueryPriority QueryField = "priority"
+	QueryDue      QueryField = "due"
+	QueryCreated  QueryField = "created"
+)
+
+var queryFields = []QueryField{QueryStatus, QueryCategory, QueryPriority, QueryDue, QueryCreated}
+
+// Condition is one field filter of a Query.
+type Condition struct {
+	Field  QueryField
+	Negate bool
+	// Values lists the accepted statuses, categories or priorities.
+	Values []string
+	// From and To bound due or created to [From, To). A zero bound is open.
+	From time.Time
+	To   time.Time
+	// Missing matches tasks without a deadline (due:none).
+	Missing bool
+}
+
+// Query is a parsed task query such as
+//
+//	status:todo,in_progress priority:high due:<7d created:>2026-01-01 -"waiting on"
+//
+// Field filters are ANDed and a leading - negates one. Status, category and
+// priority take comma-separated alternatives; category is one of open,
+// active and closed, so it works across workflows. Due and created take an optional
+// comparison (<, <=, >, >=) and a date (2006-01-02), an RFC 3339 time, today,
+// tomorrow, yesterday or an offset from now such as 7d, -2w or 12h; without
+// a comparison they match the whole day. due:none matches tasks without a
+// deadline. Everything else, including words such as re:budget or URLs that
+// only look like a field, is full-text search in web search syntax.
+type Query struct {
+	Conditions []Condition
+	Text       string
+}
+
+// ParseQuery parses q, resolving relative dates against now. Errors wrap
+// ErrInvalidQuery and describe the offending term.
+func ParseQuery(q string, now time.Time) (*Query, error) {
+	query := &Query{}
+	var text []string
+
+	for _, token := range tokenizeQuery(q) {
+		body, negate := strings.CutPrefix(token, "-")
+		key, value, ok := strings.Cut(body, ":")
+		field := QueryField(strings.ToLower(key))
+		if !ok || !slices.Contains(queryFields, field) {
+			text = append(text, token)
+			continue
+		}
+
+		cond, err := parseCondition(field, value, now)
+		if err != nil {
+			return nil, err
+		}
+		cond.Negate = negate
+		query.Conditions = append(query.Conditions, cond)
+	}
+
+	query.Text = strings.Join(text, " ")
+	return query, nil
+}
+
+// tokenizeQuery splits q on whitespace outside double quotes.
+func tokenizeQuery(q string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+func parseCondition(field QueryField, value string, now time.Time) (Condition, error) {
+	cond := Condition{Field: field}
+	if value == "" {
+		return cond, fmt.Errorf("%w: %s needs a value", ErrInvalidQuery, field)
+	}
+
+	switch field {
+	case QueryStatus:
+		for _, v := range strings.Split(value, ",") {
+			if slices.Contains(statusCategories, StatusCategory(v)) {
+				return cond, fmt.Errorf("%w: %q is a category; use category:%s", ErrInvalidQuery, v, v)
+			}
+			if !TaskStatus(v).Valid() {
+				return cond, fmt.Errorf("%w: unknown status %q", ErrInvalidQuery, v)
+			}
+			cond.Values = append(cond.Values, v)
+		}
+	case QueryCategory:
+		for _, v := range strings.Split(value, ",") {
+			if !slices.Contains(statusCategories, StatusCategory(v)) {
+				return cond, fmt.Errorf("%w: unknown category %q", ErrInvalidQuery, v)
+			}
+			cond.Values = append(cond.Values, v)
+		}
+	case QueryPriority:
+		for _, v := range strings.Split(value, ",") {
+			if !slices.Contains([]TaskPriority{PriorityLow, PriorityMedium, PriorityHigh}, TaskPriority(v)) {
+				return cond, fmt.Errorf("%w: unknown priority %q", ErrInvalidQuery, v)
+			}
+			cond.Values = append(cond.Values, v)
+		}
+	case QueryDue, QueryCreated:
+		if field == QueryDue && value == "none" {
+			cond.Missing = true
+			return cond, nil
+		}
+		if err := cond.parseRange(value, now); err != nil {
+			return cond, err
+		}
+	default:
+		return cond, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, field)
+	}
+
+	return cond, nil
+}
+
+func (c *Condition) parseRange(value string, now time.Time) error {
+	op := ""
+	for _, prefix := range []string{"<=", ">=", "<", ">", "="} {
+		if rest, ok := strings.CutPrefix(value, prefix); ok {
+			op, value = prefix, rest
+			break
+		}
+	}
+
+	at, wholeDay, err := parseQueryTime(value, now)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidQuery, c.Field, err)
+	}
+
+	end := at
+	if wholeDay {
+		end = at.AddDate(0, 0, 1)
+	}
+
+	switch op {
+	case "<":
+		c.To = at
+	case "<=":
+		c.To = end
+	case ">":
+		c.From = end
+	case ">=":
+		c.From = at
+	default:
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+		c.From, c.To = day, day.AddDate(0, 0, 1)
+	}
+
+	return nil
+}
+
+// parseQueryTime resolves a date value. wholeDay reports whether it names a
+// calendar day rather than an instant.
+func parseQueryTime(value string, now time.Time) (at time.Time, wholeDay bool, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "today":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	}
+
+	if d, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return d, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+
+	if len(value) > 1 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil {
+			switch value[len(value)-1] {
+			case 'h':
+				return now.Add(time.Duration(n) * time.Hour), false, nil
+			case 'd':
+				return now.AddDate(0, 0, n), false, nil
+			case 'w':
+				return now.AddDate(0, 0, 7*n), false, nil
+			}
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("cannot parse %q as a date", value)
+}
+
+// Matches reports whether t satisfies c. A task without a deadline is outside
+// every due range, so a negated range matches it.
+func (c Condition) Matches(t *Task) bool {
+	var ok bool
+	switch c.Field {
+	case QueryStatus:
+		ok = slices.Contains(c.Values, string(t.Status))
+	case QueryCategory:
+		ok = slices.Contains(c.Values, string(t.StatusCategory))
+	case QueryPriority:
+		ok = slices.Contains(c.Values, string(t.Priority))
+	case QueryDue:
+		if c.Missing {
+			ok = t.Deadline == nil
+		} else {
+			ok = t.Deadline != nil && c.inRange(*t.Deadline)
+		}
+	case QueryCreated:
+		ok = c.inRange(t.CreatedAt)
+	}
+	return ok != c.Negate
+}
+
+func (c Condition) inRange(at time.Time) bool {
+	if !c.From.IsZero() && at.Before(c.From) {
+		return false
+	}
+	if !c.To.IsZero() && !at.Before(c.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var queryNow = time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(`status:todo,in_progress -priority:low "waiting on" report`, queryNow)
+	assert.NoError(t, err)
+	assert.Equal(t, `"waiting on" report`, q.Text)
+	assert.Equal(t, []Condition{
+		{Field: QueryStatus, Values: []string{"todo", "in_progress"}},
+		{Field: QueryPriority, Negate: true, Values: []string{"low"}},
+	}, q.Conditions)
+
+	q, err = ParseQuery(`"status:done" 10:30`, queryNow)
+	assert.NoError(t, err)
+	assert.Empty(t, q.Conditions)
+	assert.Equal(t, `"status:done" 10:30`, q.Text)
+
+	q, err = ParseQuery(`re:budget note:foo https://example.com/a Priority:high`, queryNow)
+	assert.NoError(t, err)
+	assert.Equal(t, `re:budget note:foo https://example.com/a`, q.Text, "only known fields are filters")
+	assert.Equal(t, []Condition{{Field: QueryPriority, Values: []string{"high"}}}, q.Conditions)
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, q := range []string{"status:", "status:open", "priority:urgent", "due:soon", "created:<2d3"} {
+		_, err := ParseQuery(q, queryNow)
+		assert.ErrorIs(t, err, ErrInvalidQuery, q)
+	}
+}
+
+func TestParseQuery_Dates(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query    string
+		from, to time.Time
+	}{
+		{"due:today", today, today.AddDate(0, 0, 1)},
+		{"due:tomorrow", today.AddDate(0, 0, 1), today.AddDate(0, 0, 2)},
+		{"due:<7d", time.Time{}, queryNow.AddDate(0, 0, 7)},
+		{"due:<=2026-03-12", time.Time{}, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"created:>2026-01-01", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"created:>=-12h", queryNow.Add(-12 * time.Hour), time.Time{}},
+		{"created:2026-03-09T08:00:00Z", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, queryNow)
+		if assert.NoError(t, err, tt.query) && assert.Len(t, q.Conditions, 1) {
+			assert.Equal(t, tt.from, q.Conditions[0].From, tt.query)
+			assert.Equal(t, tt.to, q.Conditions[0].To, tt.query)
+		}
+	}
+
+	q, err := ParseQuery("due:none", queryNow)
+	assert.NoError(t, err)
+	assert.True(t, q.Conditions[0].Missing)
+}
+
+func TestCondition_Matches(t *testing.T) {
+	tomorrow := queryNow.AddDate(0, 0, 1)
+	dated := &Task{Status: StatusTodo, Priority: PriorityHigh, Deadline: &tomorrow}
+	dated.CreatedAt = queryNow.AddDate(0, 0, -3)
+	undated := &Task{Status: StatusDone, Priority: PriorityLow}
+	undated.CreatedAt = queryNow
+
+	matches := func(q string, tk *Task) bool {
+		query, err := ParseQuery(q, queryNow)
+		assert.NoError(t, err, q)
+		for _, c := range query.Conditions {
+			if !c.Matches(tk) {
+				return false
+			}
+		}
+		return true
+	}
+
+	assert.True(t, matches("status:todo,in_progress priority:high", dated))
+	assert.False(t, matches("status:todo", undated))
+	assert.True(t, matches("-status:todo", undated))
+
+	assert.True(t, matches("due:tomorrow", dated))
+	assert.True(t, matches("due:<7d", dated))
+	assert.False(t, matches("due:<7d", undated))
+	assert.True(t, matches("-due:<7d", undated))
+	assert.True(t, matches("due:none", undated))
+	assert.False(t, matches("due:none", dated))
+
+	assert.True(t, matches("created:<today", dated))
+	assert.False(t, matches("created:<today", undated))
+	assert.True(t, matches("created:>=-1d", undated))
+}
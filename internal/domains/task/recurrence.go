@@ -0,0 +1,350 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRRule       = errors.New("invalid recurrence rule")
+	ErrRecurrenceDeadline = errors.New("recurring task needs a deadline")
+)
+
+// Recurrence makes a task one occurrence of a repeating series. Completing
+// the occurrence creates the next one from Template.
+type Recurrence struct {
+	// Rule is the series' RRULE in the canonical form of RRule.String.
+	Rule     string    `json:"rule"`
+	SeriesID uuid.UUID `json:"series_id"`
+	// Occurrence numbers the occurrences of a series from 1.
+	Occurrence int `json:"occurrence"`
+	// Template holds the series' fields as of this occurrence. Editing only
+	// this occurrence changes the task but leaves the template alone.
+	Template SeriesTemplate `json:"template"`
+}
+
+// SeriesTemplate is what the next occurrence of a series is created from.
+// Deadline is when this occurrence was scheduled, so moving one occurrence
+// does not shift the ones after it.
+type SeriesTemplate struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Priority    TaskPriority `json:"priority"`
+	Deadline    time.Time    `json:"deadline"`
+}
+
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry. N picks the Nth such weekday of the month,
+// counting from the end when negative; 0 means every one.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// RRule is the supported subset of an RFC 5545 recurrence rule: FREQ of
+// DAILY, WEEKLY, MONTHLY or YEARLY with INTERVAL, BYDAY, COUNT and UNTIL.
+// Weeks start on Monday. Ordinal BYDAY entries such as 2TU or -1FR are only
+// allowed with MONTHLY, and YEARLY takes no BYDAY.
+type RRule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	// Count caps the number of occurrences and Until the last one's date.
+	// At most one of them is set.
+	Count int
+	Until *time.Time
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+const maxRRuleInterval = 1000
+
+// untilLayouts are the UNTIL forms accepted: UTC and floating date-times,
+// which are read as UTC, and dates.
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// ParseRRule parses a rule such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE. An
+// RRULE: prefix is allowed. Errors wrap ErrInvalidRRule.
+func ParseRRule(s string) (*RRule, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRRule)
+	}
+
+	r := &RRule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if !slices.Contains([]Frequency{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}, r.Freq) {
+				err = fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = positiveInt(key, value, maxRRuleInterval)
+		case "COUNT":
+			r.Count, err = positiveInt(key, value, 0)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRRule)
+	case r.Count > 0 && r.Until != nil:
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRRule)
+	case r.Freq == FreqYearly && len(r.ByDay) > 0:
+		return nil, fmt.Errorf("%w: BYDAY is not supported with YEARLY", ErrInvalidRRule)
+	}
+	if r.Freq != FreqMonthly {
+		for _, day := range r.ByDay {
+			if day.N != 0 {
+				return nil, fmt.Errorf("%w: ordinal BYDAY needs FREQ=MONTHLY", ErrInvalidRRule)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func positiveInt(key, value string, limit int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || (limit > 0 && n > limit) {
+		return 0, fmt.Errorf("invalid %s %s", key, value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (*time.Time, error) {
+	for _, layout := range untilLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day.
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid UNTIL %s", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %s", entry)
+		}
+		code, ordinal := entry[len(entry)-2:], entry[:len(entry)-2]
+
+		weekday := slices.Index(weekdayCodes, code)
+		if weekday < 0 {
+			return nil, fmt.Errorf("invalid BYDAY %s", entry)
+		}
+
+		n := 0
+		if ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %s", entry)
+			}
+		}
+
+		day := WeekdayNum{Weekday: time.Weekday(weekday), N: n}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// String formats the rule canonically.
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayCodes[day.Weekday]
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayouts[0]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence after prev, the scheduled time of occurrence
+// number n, keeping prev's time of day. It reports false when the series
+// ends at prev.
+func (r *RRule) Next(prev time.Time, n int) (time.Time, bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case FreqDaily:
+		next, ok = r.nextDaily(prev)
+	case FreqWeekly:
+		next, ok = r.nextWeekly(prev)
+	case FreqMonthly:
+		next, ok = r.nextMonthly(prev)
+	case FreqYearly:
+		next, ok = r.nextYearly(prev)
+	}
+
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// onDay returns t moved by the given calendar offsets, keeping its wall
+// clock time across DST changes.
+func onDay(t time.Time, years, months, days int) time.Time {
+	return time.Date(t.Year()+years, t.Month()+time.Month(months), t.Day()+days,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func (r *RRule) matchesWeekday(d time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == d {
+			return true
+		}
+	}
+	return len(r.ByDay) == 0
+}
+
+func (r *RRule) nextDaily(prev time.Time) (time.Time, bool) {
+	// Stepping by the interval repeats the same weekdays every 7 steps.
+	for step := 1; step <= 7; step++ {
+		next := onDay(prev, 0, 0, step*r.Interval)
+		if r.matchesWeekday(next.Weekday()) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *RRule) nextWeekly(prev time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return onDay(prev, 0, 0, 7*r.Interval), true
+	}
+
+	// Days since the Monday starting prev's week.
+	offset := (int(prev.Weekday()) + 6) % 7
+	for day := 1; day <= 7*r.Interval+7; day++ {
+		next := onDay(prev, 0, 0, day)
+		if (offset+day)/7%r.Interval == 0 && r.matchesWeekday(next.Weekday()) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *RRule) nextMonthly(prev time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		// Months without prev's day of the month are skipped. The months
+		// visited repeat within 12 steps.
+		for step := 1; step <= 12; step++ {
+			next := onDay(prev, 0, step*r.Interval, 0)
+			if next.Day() == prev.Day() {
+				return next, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	// An ordinal such as 5FR can be missing for several months in a row.
+	for step := 0; step <= 100; step++ {
+		first := time.Date(prev.Year(), prev.Month()+time.Month(step*r.Interval), 1,
+			prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+		for _, day := range r.monthDays(first) {
+			next := onDay(first, 0, 0, day-1)
+			if next.After(prev) {
+				return next, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthDays returns the days of first's month matching BYDAY, in order.
+func (r *RRule) monthDays(first time.Time) []int {
+	length := onDay(first, 0, 1, -1).Day()
+
+	var days []int
+	for _, wd := range r.ByDay {
+		var matching []int
+		for day := 1 + (int(wd.Weekday)-int(first.Weekday())+7)%7; day <= length; day += 7 {
+			matching = append(matching, day)
+		}
+
+		switch {
+		case wd.N == 0:
+			days = append(days, matching...)
+		case wd.N > 0 && wd.N <= len(matching):
+			days = append(days, matching[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matching):
+			days = append(days, matching[len(matching)+wd.N])
+		}
+	}
+
+	slices.Sort(days)
+	return slices.Compact(days)
+}
+
+func (r *RRule) nextYearly(prev time.Time) (time.Time, bool) {
+	// February 29 only comes back in a leap year.
+	for step := 1; step <= 8; step++ {
+		next := onDay(prev, step*r.Interval, 0, 0)
+		if next.Month() == prev.Month() && next.Day() == prev.Day() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
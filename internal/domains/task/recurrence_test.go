@@ -0,0 +1,100 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:freq=weekly;byday=mo,we,mo;interval=2;until=20260501")
+	require.NoError(t, err)
+	assert.Equal(t, FreqWeekly, r.Freq)
+	assert.Equal(t, 2, r.Interval)
+	assert.Equal(t, []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}}, r.ByDay)
+	assert.Equal(t, time.Date(2026, 5, 1, 23, 59, 59, 0, time.UTC), *r.Until)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20260501T235959Z", r.String())
+
+	r, err = ParseRRule("FREQ=MONTHLY;BYDAY=-1FR;COUNT=3")
+	require.NoError(t, err)
+	assert.Equal(t, []WeekdayNum{{Weekday: time.Friday, N: -1}}, r.ByDay)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", r.String())
+}
+
+func TestParseRRule_Errors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT",
+	} {
+		_, err := ParseRRule(rule)
+		assert.ErrorIs(t, err, ErrInvalidRRule, rule)
+	}
+}
+
+func TestRRule_Next(t *testing.T) {
+	// Tuesday, March 10 2026.
+	start := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d, year int) time.Time {
+		return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		rule string
+		from time.Time
+		want []time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=3", start, []time.Time{day(3, 13, 2026), day(3, 16, 2026)}},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", day(3, 13, 2026), []time.Time{day(3, 16, 2026), day(3, 17, 2026)}},
+		{"FREQ=WEEKLY", start, []time.Time{day(3, 17, 2026), day(3, 24, 2026)}},
+		{"FREQ=WEEKLY;BYDAY=MO,TH", start, []time.Time{day(3, 12, 2026), day(3, 16, 2026), day(3, 19, 2026)}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", start, []time.Time{day(3, 12, 2026), day(3, 23, 2026), day(3, 26, 2026)}},
+		{"FREQ=MONTHLY", start, []time.Time{day(4, 10, 2026), day(5, 10, 2026)}},
+		{"FREQ=MONTHLY", day(1, 31, 2026), []time.Time{day(3, 31, 2026), day(5, 31, 2026)}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", start, []time.Time{day(3, 27, 2026), day(4, 24, 2026)}},
+		{"FREQ=MONTHLY;INTERVAL=2;BYDAY=1MO", start, []time.Time{day(5, 4, 2026), day(7, 6, 2026)}},
+		{"FREQ=YEARLY", day(2, 29, 2024), []time.Time{day(2, 29, 2028)}},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRRule(tt.rule)
+		require.NoError(t, err, tt.rule)
+
+		prev := tt.from
+		for i, want := range tt.want {
+			next, ok := r.Next(prev, i+1)
+			if assert.True(t, ok, tt.rule) {
+				assert.Equal(t, want, next, tt.rule)
+			}
+			prev = next
+		}
+	}
+}
+
+func TestRRule_NextEnds(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	r, _ := ParseRRule("FREQ=DAILY;COUNT=2")
+	_, ok := r.Next(start, 1)
+	assert.True(t, ok)
+	_, ok = r.Next(start, 2)
+	assert.False(t, ok)
+
+	r, _ = ParseRRule("FREQ=WEEKLY;UNTIL=20260320")
+	_, ok = r.Next(start, 1)
+	assert.True(t, ok)
+	_, ok = r.Next(start.AddDate(0, 0, 7), 2)
+	assert.False(t, ok)
+}
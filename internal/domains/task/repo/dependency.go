@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/db"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// dependencyLockKey serializes dependency inserts so two concurrent edges
+// cannot close a cycle that neither sees on its own. The lock is held until
+// the surrounding transaction ends.
+const dependencyLockKey int64 = 0x7461736b646570 // "taskdep"
+
+func (r *TaskRepository) AddDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	if taskID == blockerID {
+		return task.ErrDependencyCycle
+	}
+
+	conn := db.Conn(ctx, r.conn)
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, dependencyLockKey); err != nil {
+		return err
+	}
+
+	// The new edge closes a cycle when taskID is already upstream of the
+	// blocker.
+	query := `WITH RECURSIVE upstream(id) AS (
+                  SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+                  UNION
+                  SELECT d.blocked_by_id FROM task_dependencies d JOIN upstream u ON d.task_id = u.id
+              )
+              SELECT EXISTS (SELECT 1 FROM upstream WHERE id = $2)`
+
+	var cycle bool
+	if err := conn.QueryRowContext(ctx, query, blockerID, taskID).Scan(&cycle); err != nil {
+		return err
+	}
+	if cycle {
+		return task.ErrDependencyCycle
+	}
+
+	_, err := conn.ExecContext(ctx, `INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2)`, taskID, blockerID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return task.ErrDependencyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.recordOwnerChange(ctx, taskID, task.FieldBlockedBy, nil, blockerID)
+}
+
+func (r *TaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx,
+		`DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2`, taskID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return r.recordOwnerChange(ctx, taskID, task.FieldBlockedBy, blockerID, nil)
+}
+
+func (r *TaskRepository) FindBlockers(ctx context.Context, taskID uuid.UUID) ([]*task.Task, error) {
+	return r.findLinked(ctx, `SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1`, taskID)
+}
+
+func (r *TaskRepository) FindDependents(ctx context.Context, taskID uuid.UUID) ([]*task.Task, error) {
+	return r.findLinked(ctx, `SELECT task_id FROM task_dependencies WHERE blocked_by_id = $1`, taskID)
+}
+
+// findLinked returns the live tasks whose ids the subquery selects, oldest
+// first.
+func (r *TaskRepository) findLinked(ctx context.Context, subquery string, taskID uuid.UUID) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks
+              WHERE id IN (` + subquery + `) AND deleted_at IS NULL
+              ORDER BY created_at ASC, id ASC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows, false)
+}
+
+func (r *TaskRepository) OpenBlockers(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	blockers := map[uuid.UUID][]uuid.UUID{}
+	if len(taskIDs) == 0 {
+		return blockers, nil
+	}
+
+	ids := make([]string, len(taskIDs))
+	for i, id := range taskIDs {
+		ids[i] = id.String()
+	}
+
+	query := `SELECT d.task_id, d.blocked_by_id
+              FROM task_dependencies d
+              JOIN tasks b ON b.id = d.blocked_by_id
+              WHERE d.task_id = ANY($1::uuid[]) AND b.deleted_at IS NULL AND b.status_category != $2
+              ORDER BY d.created_at ASC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, pq.Array(ids), task.CategoryClosed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, blockerID uuid.UUID
+		if err := rows.Scan(&taskID, &blockerID); err != nil {
+			return nil, err
+		}
+		blockers[taskID] = append(blockers[taskID], blockerID)
+	}
+
+	return blockers, rows.Err()
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/db"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jsonParam passes a JSON value to a nullable JSONB parameter; lib/pq would
+// send a nil slice as an empty string rather than NULL.
+func jsonParam(raw json.RawMessage) interface{} {
+	if raw == nil {
+		return nil
+	}
+	return []byte(raw)
+}
+
+// recordChanges appends changes to their tasks' history. Callers run it in
+// the transaction of the mutation that made them.
+func (r *TaskRepository) recordChanges(ctx context.Context, changes ...*task.Change) error {
+	query := `INSERT INTO task_history (id, task_id, actor_id, action, field, old_value, new_value, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	conn := db.Conn(ctx, r.conn)
+	for _, c := range changes {
+		_, err := conn.ExecContext(ctx, query,
+			c.Id, c.TaskID, c.ActorID, c.Action, c.Field, jsonParam(c.Old), jsonParam(c.New), c.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordOwnerChange records a change to a task's field on behalf of its
+// owner, for mutations that do not say who made them.
+func (r *TaskRepository) recordOwnerChange(ctx context.Context, taskID uuid.UUID, field string, oldValue, newValue any) error {
+	var ownerID uuid.UUID
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, `SELECT user_id FROM tasks WHERE id = $1`, taskID).Scan(&ownerID)
+	if err != nil {
+		return err
+	}
+
+	c, err := task.NewChange(taskID, ownerID, task.ActionUpdated, field, oldValue, newValue, time.Now())
+	if err != nil {
+		return err
+	}
+	return r.recordChanges(ctx, c)
+}
+
+func (r *TaskRepository) FindHistory(ctx context.Context, taskID uuid.UUID) ([]*task.Change, error) {
+	query := `SELECT id, task_id, actor_id, action, field, old_value, new_value, created_at
+              FROM task_history WHERE task_id = $1 ORDER BY created_at ASC, field ASC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*task.Change
+	for rows.Next() {
+		var c task.Change
+		var oldValue, newValue []byte
+		if err := rows.Scan(&c.Id, &c.TaskID, &c.ActorID, &c.Action, &c.Field, &oldValue, &newValue, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.Old, c.New = oldValue, newValue
+		changes = append(changes, &c)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,522 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/task"
+	baserepo "taskhub/pkg/base/repo"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryTaskRepositoryModule = fx.Module(
+	"task-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryTaskRepository, fx.As(new(task.TaskStore)))),
+)
+
+// MemoryTaskRepository is an in-memory task.TaskStore for tests and local
+// development. It mirrors the soft-delete and ordering behaviour of
+// TaskRepository.
+type MemoryTaskRepository struct {
+	store *baserepo.MemoryRepository[*task.Task]
+
+	// mu guards dependencies so the cycle check and insert are atomic.
+	mu           sync.Mutex
+	dependencies []task.Dependency
+
+	historyMu sync.Mutex
+	history   []*task.Change
+}
+
+func NewMemoryTaskRepository() *MemoryTaskRepository {
+	return &MemoryTaskRepository{
+		store: baserepo.NewMemoryRepository(cloneTask),
+	}
+}
+
+func cloneTask(t *task.Task) *task.Task {
+	c := *t
+	c.Checklist = slices.Clone(t.Checklist)
+	c.Recurrence = cloneRecurrence(t.Recurrence)
+	c.LabelIDs = slices.Clone(t.LabelIDs)
+	return &c
+}
+
+func cloneRecurrence(rec *task.Recurrence) *task.Recurrence {
+	if rec == nil {
+		return nil
+	}
+	c := *rec
+	return &c
+}
+
+// record appends changes to their tasks' history.
+func (r *MemoryTaskRepository) record(changes ...*task.Change) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+	r.history = append(r.history, changes...)
+}
+
+// recordOwnerChange records a change to a task's field on behalf of its
+// owner, for mutations that do not say who made them.
+func (r *MemoryTaskRepository) recordOwnerChange(ctx context.Context, taskID uuid.UUID, field string, oldValue, newValue any) error {
+	t, err := r.store.FindById(ctx, taskID)
+	if err != nil || t == nil {
+		return err
+	}
+
+	c, err := task.NewChange(taskID, t.UserID, task.ActionUpdated, field, oldValue, newValue, time.Now())
+	if err != nil {
+		return err
+	}
+	r.record(c)
+	return nil
+}
+
+func (r *MemoryTaskRepository) Create(ctx context.Context, t *task.Task) (*task.Task, error) {
+	fillStatusCategory(t)
+	created, err := task.NewChange(t.Id, t.CreatedBy, task.ActionCreated, "", nil, nil, t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err = r.store.Create(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	r.record(created)
+	return t, nil
+}
+
+func (r *MemoryTaskRepository) UpdateById(ctx context.Context, id uuid.UUID, t *task.Task) (*task.Task, error) {
+	fillStatusCategory(t)
+	var changes []*task.Change
+	err := r.store.Modify(ctx, id, func(existing *task.Task) error {
+		before := cloneTask(existing)
+		existing.Title = t.Title
+		existing.Description = t.Description
+		existing.Status = t.Status
+		existing.StatusCategory = t.StatusCategory
+		existing.Priority = t.Priority
+		existing.Deadline = t.Deadline
+		existing.UpdateAt = t.UpdateAt
+		existing.UpdateBy = t.UpdateBy
+		existing.ParentID = t.ParentID
+		existing.ProjectID = t.ProjectID
+		existing.Checklist = slices.Clone(t.Checklist)
+		existing.Recurrence = cloneRecurrence(t.Recurrence)
+		existing.LabelIDs = slices.Clone(t.LabelIDs)
+
+		actorID, at := existing.UserID, time.Now()
+		if t.UpdateBy != nil {
+			actorID = *t.UpdateBy
+		}
+		if t.UpdateAt != nil {
+			at = *t.UpdateAt
+		}
+		var err error
+		changes, err = task.UpdateChanges(before, existing, actorID, at)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.record(changes...)
+
+	t.Id = id
+	return t, nil
+}
+
+func (r *MemoryTaskRepository) FindById(ctx context.Context, id uuid.UUID) (*task.Task, error) {
+	t, err := r.store.FindById(ctx, id)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if t.DeletedAt != nil {
+		return nil, nil
+	}
+
+	return t, nil
+}
+
+func (r *MemoryTaskRepository) FindAll(ctx context.Context, filter *task.TaskFilter) ([]*task.Task, error) {
+	tasks, err := r.store.FindAll(ctx, func(t *task.Task) bool {
+		if t.DeletedAt != nil {
+			return false
+		}
+		if filter == nil {
+			return t.ArchivedAt == nil
+		}
+		if filter.Archived != (t.ArchivedAt != nil) {
+			return false
+		}
+		if filter.Status != nil && t.Status != *filter.Status {
+			return false
+		}
+		if filter.Priority != nil && t.Priority != *filter.Priority {
+			return false
+		}
+		if filter.UserID != nil && t.UserID != *filter.UserID {
+			return false
+		}
+		if filter.Deadline != nil && (t.Deadline == nil || t.Deadline.After(*filter.Deadline)) {
+			return false
+		}
+		if filter.ParentID != nil && (t.ParentID == nil || *t.ParentID != *filter.ParentID) {
+			return false
+		}
+		if filter.TopLevel && t.ParentID != nil {
+			return false
+		}
+		if filter.ProjectID != nil && (t.ProjectID == nil || *t.ProjectID != *filter.ProjectID) {
+			return false
+		}
+		if filter.SeriesID != nil && (t.Recurrence == nil || t.Recurrence.SeriesID != *filter.SeriesID) {
+			return false
+		}
+		if len(filter.LabelsAny) > 0 && !slices.ContainsFunc(filter.LabelsAny, func(id uuid.UUID) bool { return slices.Contains(t.LabelIDs, id) }) {
+			return false
+		}
+		for _, id := range filter.LabelsAll {
+			if !slices.Contains(t.LabelIDs, id) {
+				return false
+			}
+		}
+		for _, c := range filter.Conditions {
+			if !c.Matches(t) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil && filter.Search != "" {
+		tasks = search(tasks, task.ParseSearch(filter.Search))
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+
+	return tasks, nil
+}
+
+// search keeps the tasks matching q and sets their Match, approximating the
+// ranking and headlines of the Postgres full-text search.
+func search(tasks []*task.Task, q *task.SearchQuery) []*task.Task {
+	var matched []*task.Task
+	for _, t := range tasks {
+		rank, ok := q.Match(t)
+		if !ok {
+			continue
+		}
+		t.Match = &task.SearchMatch{
+			Rank:    rank,
+			Title:   task.HighlightHTML(q.Headline(t.Title, 0)),
+			Snippet: task.HighlightHTML(q.Headline(t.Description, snippetWords)),
+		}
+		matched = append(matched, t)
+	}
+	return matched
+}
+
+func (r *MemoryTaskRepository) FindPage(ctx context.Context, filter *task.TaskFilter, page *task.PageRequest) (*task.Page, error) {
+	tasks, err := r.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return page.Sort.Compare(tasks[i], tasks[j]) < 0
+	})
+
+	result := &task.Page{Total: len(tasks)}
+	for _, t := range tasks {
+		if page.After != nil && !page.After.Follows(t) {
+			continue
+		}
+		if len(result.Tasks) == page.Limit {
+			result.Next = task.CursorAt(page.Sort, result.Tasks[len(result.Tasks)-1])
+			break
+		}
+		result.Tasks = append(result.Tasks, t)
+	}
+
+	return result, nil
+}
+
+func (r *MemoryTaskRepository) FindByUserId(ctx context.Context, userID uuid.UUID, filter *task.TaskFilter) ([]*task.Task, error) {
+	if filter == nil {
+		filter = &task.TaskFilter{}
+	}
+	filter.UserID = &userID
+	return r.FindAll(ctx, filter)
+}
+
+func (r *MemoryTaskRepository) DeleteById(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	now := time.Now()
+	deleted, err := task.NewChange(id, userID, task.ActionDeleted, "", nil, nil, now)
+	if err != nil {
+		return err
+	}
+
+	err = r.store.Modify(ctx, id, func(existing *task.Task) error {
+		existing.DeletedAt = &now
+		existing.DeletedBy = &userID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.record(deleted)
+	return nil
+}
+
+func (r *MemoryTaskRepository) MarkAsCompleted(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	var changes []*task.Change
+	err := r.store.Modify(ctx, id, func(existing *task.Task) error {
+		before := cloneTask(existing)
+		existing.MarkAsCompleted(userID)
+
+		var err error
+		changes, err = task.UpdateChanges(before, existing, userID, *existing.UpdateAt)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	r.record(changes...)
+	return nil
+}
+
+func (r *MemoryTaskRepository) FindTasksNearDeadline(ctx context.Context, hoursAhead int) ([]*task.Task, error) {
+	now := time.Now()
+	cutoff := now.Add(time.Duration(hoursAhead) * time.Hour)
+
+	tasks, err := r.store.FindAll(ctx, func(t *task.Task) bool {
+		return t.DeletedAt == nil &&
+			t.ArchivedAt == nil &&
+			!t.Closed() &&
+			t.Deadline != nil &&
+			t.Deadline.After(now) &&
+			!t.Deadline.After(cutoff)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Deadline.Before(*tasks[j].Deadline)
+	})
+
+	return tasks, nil
+}
+
+func (r *MemoryTaskRepository) ArchiveByProject(ctx context.Context, projectID uuid.UUID, archivedAt *time.Time) error {
+	tasks, err := r.store.FindAll(ctx, func(t *task.Task) bool {
+		return t.DeletedAt == nil && t.ProjectID != nil && *t.ProjectID == projectID
+	})
+	if err != nil {
+		return err
+	}
+
+	// Projects belong to one user, so the owner archived the tasks.
+	now := time.Now()
+	for _, t := range tasks {
+		unchanged := t.ArchivedAt == nil && archivedAt == nil ||
+			t.ArchivedAt != nil && archivedAt != nil && t.ArchivedAt.Equal(*archivedAt)
+		if unchanged {
+			continue
+		}
+		c, err := task.NewChange(t.Id, t.UserID, task.ActionUpdated, "archived_at", t.ArchivedAt, archivedAt, now)
+		if err != nil {
+			return err
+		}
+
+		err = r.store.Modify(ctx, t.Id, func(existing *task.Task) error {
+			existing.ArchivedAt = archivedAt
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		r.record(c)
+	}
+
+	return nil
+}
+
+func (r *MemoryTaskRepository) FindChildren(ctx context.Context, parentID uuid.UUID) ([]*task.Task, error) {
+	tasks, err := r.FindAll(ctx, &task.TaskFilter{ParentID: &parentID})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+
+	return tasks, nil
+}
+
+func (r *MemoryTaskRepository) SubtaskProgress(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]task.Progress, error) {
+	children, err := r.store.FindAll(ctx, func(t *task.Task) bool {
+		return t.DeletedAt == nil && t.ArchivedAt == nil && t.ParentID != nil && slices.Contains(parentIDs, *t.ParentID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[uuid.UUID][2]int{}
+	for _, child := range children {
+		c := counts[*child.ParentID]
+		if child.Closed() {
+			c[0]++
+		}
+		c[1]++
+		counts[*child.ParentID] = c
+	}
+
+	progress := make(map[uuid.UUID]task.Progress, len(counts))
+	for id, c := range counts {
+		progress[id] = task.NewProgress(c[0], c[1])
+	}
+
+	return progress, nil
+}
+
+func (r *MemoryTaskRepository) AddDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The new edge closes a cycle when taskID is already upstream of the
+	// blocker.
+	seen := map[uuid.UUID]bool{}
+	queue := []uuid.UUID{blockerID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == taskID {
+			return task.ErrDependencyCycle
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		for _, d := range r.dependencies {
+			if d.TaskID == id {
+				queue = append(queue, d.BlockedByID)
+			}
+		}
+	}
+
+	for _, d := range r.dependencies {
+		if d.TaskID == taskID && d.BlockedByID == blockerID {
+			return task.ErrDependencyExists
+		}
+	}
+
+	r.dependencies = append(r.dependencies, task.Dependency{TaskID: taskID, BlockedByID: blockerID})
+	return r.recordOwnerChange(ctx, taskID, task.FieldBlockedBy, nil, blockerID)
+}
+
+func (r *MemoryTaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, d := range r.dependencies {
+		if d.TaskID == taskID && d.BlockedByID == blockerID {
+			r.dependencies = slices.Delete(r.dependencies, i, i+1)
+			return r.recordOwnerChange(ctx, taskID, task.FieldBlockedBy, blockerID, nil)
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func (r *MemoryTaskRepository) FindBlockers(ctx context.Context, taskID uuid.UUID) ([]*task.Task, error) {
+	return r.findLinked(ctx, func(d task.Dependency) (uuid.UUID, bool) {
+		return d.BlockedByID, d.TaskID == taskID
+	})
+}
+
+func (r *MemoryTaskRepository) FindDependents(ctx context.Context, taskID uuid.UUID) ([]*task.Task, error) {
+	return r.findLinked(ctx, func(d task.Dependency) (uuid.UUID, bool) {
+		return d.TaskID, d.BlockedByID == taskID
+	})
+}
+
+// findLinked returns the live tasks at the other end of the edges that
+// link accepts, oldest first.
+func (r *MemoryTaskRepository) findLinked(ctx context.Context, link func(task.Dependency) (uuid.UUID, bool)) ([]*task.Task, error) {
+	r.mu.Lock()
+	var ids []uuid.UUID
+	for _, d := range r.dependencies {
+		if id, ok := link(d); ok {
+			ids = append(ids, id)
+		}
+	}
+	r.mu.Unlock()
+
+	tasks, err := r.store.FindAll(ctx, func(t *task.Task) bool {
+		return t.DeletedAt == nil && slices.Contains(ids, t.Id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+
+	return tasks, nil
+}
+
+func (r *MemoryTaskRepository) OpenBlockers(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	r.mu.Lock()
+	dependencies := slices.Clone(r.dependencies)
+	r.mu.Unlock()
+
+	blockers := map[uuid.UUID][]uuid.UUID{}
+	for _, d := range dependencies {
+		if !slices.Contains(taskIDs, d.TaskID) {
+			continue
+		}
+
+		blocker, err := r.FindById(ctx, d.BlockedByID)
+		if err != nil {
+			return nil, err
+		}
+		if blocker != nil && !blocker.Closed() {
+			blockers[d.TaskID] = append(blockers[d.TaskID], d.BlockedByID)
+		}
+	}
+
+	return blockers, nil
+}
+
+func (r *MemoryTaskRepository) FindHistory(ctx context.Context, taskID uuid.UUID) ([]*task.Change, error) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	var changes []*task.Change
+	for _, c := range r.history {
+		if c.TaskID == taskID {
+			copied := *c
+			changes = append(changes, &copied)
+		}
+	}
+
+	return changes, nil
+}
+
+var _ task.TaskStore = (*MemoryTaskRepository)(nil)
@@ -0,0 +1,300 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/task"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTaskRepository_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+
+	created, err := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Task"}, userID))
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.DeleteById(ctx, created.Id, userID))
+
+	found, err := r.FindById(ctx, created.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	tasks, err := r.FindByUserId(ctx, userID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestMemoryTaskRepository_FindAllFilters(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	soon := time.Now().Add(time.Hour)
+
+	first := task.NewTask(ctx, &task.Task{Title: "First", Priority: task.PriorityHigh, Deadline: &soon}, userID)
+	r.Create(ctx, first)
+	second := task.NewTask(ctx, &task.Task{Title: "Second", Priority: task.PriorityLow}, userID)
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+	r.Create(ctx, second)
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Other"}, otherUserID))
+
+	tasks, err := r.FindByUserId(ctx, userID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, "Second", tasks[0].Title)
+
+	priority := task.PriorityHigh
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{Priority: &priority})
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "First", tasks[0].Title)
+
+	deadline := time.Now().Add(2 * time.Hour)
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{Deadline: &deadline})
+	assert.Len(t, tasks, 1)
+
+	work, home := uuid.New(), uuid.New()
+	r.UpdateById(ctx, first.Id, &task.Task{Title: "First", LabelIDs: []uuid.UUID{work, home}})
+	r.UpdateById(ctx, second.Id, &task.Task{Title: "Second", LabelIDs: []uuid.UUID{work}})
+
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{LabelsAny: []uuid.UUID{home, uuid.New()}})
+	assert.Len(t, tasks, 1)
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{LabelsAny: []uuid.UUID{work}})
+	assert.Len(t, tasks, 2)
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{LabelsAll: []uuid.UUID{work, home}})
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "First", tasks[0].Title)
+}
+
+func TestMemoryTaskRepository_ArchiveByProject(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+	projectID := uuid.New()
+
+	inProject, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Launch", ProjectID: &projectID}, userID))
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Chores"}, userID))
+
+	tasks, _ := r.FindByUserId(ctx, userID, &task.TaskFilter{ProjectID: &projectID})
+	assert.Len(t, tasks, 1)
+
+	now := time.Now()
+	require.NoError(t, r.ArchiveByProject(ctx, projectID, &now))
+
+	tasks, _ = r.FindByUserId(ctx, userID, nil)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Chores", tasks[0].Title)
+
+	tasks, _ = r.FindByUserId(ctx, userID, &task.TaskFilter{Archived: true})
+	require.Len(t, tasks, 1)
+	assert.Equal(t, inProject.Id, tasks[0].Id)
+
+	found, err := r.FindById(ctx, inProject.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, found.ArchivedAt)
+
+	require.NoError(t, r.ArchiveByProject(ctx, projectID, nil))
+	tasks, _ = r.FindByUserId(ctx, userID, nil)
+	assert.Len(t, tasks, 2)
+}
+
+func TestMemoryTaskRepository_MarkAsCompleted(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+
+	created, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Task"}, userID))
+
+	assert.NoError(t, r.MarkAsCompleted(ctx, created.Id, userID))
+	found, _ := r.FindById(ctx, created.Id)
+	assert.Equal(t, task.StatusDone, found.Status)
+
+	assert.Equal(t, sql.ErrNoRows, r.MarkAsCompleted(ctx, uuid.New(), userID))
+}
+
+func TestMemoryTaskRepository_FindTasksNearDeadline(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+	soon := time.Now().Add(30 * time.Minute)
+	later := time.Now().Add(48 * time.Hour)
+	overdue := time.Now().Add(-time.Hour)
+
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Soon", Deadline: &soon}, userID))
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Overdue", Deadline: &overdue}, userID))
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Later", Deadline: &later}, userID))
+	r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "None"}, userID))
+
+	tasks, err := r.FindTasksNearDeadline(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "Soon", tasks[0].Title)
+}
+
+func TestMemoryTaskRepository_FindPage(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+	base := time.Now()
+
+	priorities := []task.TaskPriority{task.PriorityLow, task.PriorityHigh, task.PriorityMedium}
+	for i := range 7 {
+		tk := task.NewTask(ctx, &task.Task{Title: string(rune('a' + i)), Priority: priorities[i%3]}, userID)
+		tk.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		if i%2 == 0 {
+			deadline := base.Add(time.Duration(7-i) * time.Hour)
+			tk.Deadline = &deadline
+		}
+		r.Create(ctx, tk)
+	}
+
+	for _, s := range []task.Sort{
+		task.DefaultSort,
+		{Field: task.SortDeadline},
+		{Field: task.SortPriority, Desc: true},
+		{Field: task.SortTitle},
+	} {
+		all, err := r.FindPage(ctx, &task.TaskFilter{UserID: &userID}, &task.PageRequest{Sort: s, Limit: 100})
+		assert.NoError(t, err)
+		assert.Len(t, all.Tasks, 7)
+		assert.Nil(t, all.Next)
+
+		var walked []*task.Task
+		page := &task.PageRequest{Sort: s, Limit: 3}
+		for {
+			result, err := r.FindPage(ctx, &task.TaskFilter{UserID: &userID}, page)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, result.Total)
+			walked = append(walked, result.Tasks...)
+			if result.Next == nil {
+				break
+			}
+			page.After = result.Next
+		}
+		assert.Equal(t, all.Tasks, walked, s.String())
+	}
+}
+
+func TestMemoryTaskRepository_Subtasks(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+
+	item, _ := task.NewChecklistItem("step")
+	parent, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Parent", Checklist: []task.ChecklistItem{item}}, userID))
+	first, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "First", ParentID: &parent.Id}, userID))
+	second, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Second", ParentID: &parent.Id}, userID))
+	assert.NoError(t, r.MarkAsCompleted(ctx, first.Id, userID))
+
+	children, err := r.FindChildren(ctx, parent.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, []string{children[0].Title, children[1].Title})
+
+	progress, err := r.SubtaskProgress(ctx, []uuid.UUID{parent.Id, second.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]task.Progress{parent.Id: task.NewProgress(1, 2)}, progress)
+
+	topLevel, err := r.FindAll(ctx, &task.TaskFilter{TopLevel: true})
+	assert.NoError(t, err)
+	assert.Len(t, topLevel, 1)
+
+	// The checklist is copied, so callers cannot edit the stored task.
+	found, _ := r.FindById(ctx, parent.Id)
+	found.Checklist[0].Done = true
+	found, _ = r.FindById(ctx, parent.Id)
+	assert.False(t, found.Checklist[0].Done)
+}
+
+func TestMemoryTaskRepository_Dependencies(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	userID := uuid.New()
+
+	design, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Design"}, userID))
+	build, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Build"}, userID))
+	ship, _ := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Ship"}, userID))
+
+	assert.NoError(t, r.AddDependency(ctx, build.Id, design.Id))
+	assert.NoError(t, r.AddDependency(ctx, ship.Id, build.Id))
+	assert.ErrorIs(t, r.AddDependency(ctx, ship.Id, build.Id), task.ErrDependencyExists)
+	assert.ErrorIs(t, r.AddDependency(ctx, design.Id, ship.Id), task.ErrDependencyCycle)
+	assert.ErrorIs(t, r.AddDependency(ctx, design.Id, design.Id), task.ErrDependencyCycle)
+
+	blockers, err := r.FindBlockers(ctx, build.Id)
+	assert.NoError(t, err)
+	require.Len(t, blockers, 1)
+	assert.Equal(t, design.Id, blockers[0].Id)
+
+	dependents, err := r.FindDependents(ctx, build.Id)
+	assert.NoError(t, err)
+	require.Len(t, dependents, 1)
+	assert.Equal(t, ship.Id, dependents[0].Id)
+
+	assert.NoError(t, r.MarkAsCompleted(ctx, design.Id, userID))
+	open, err := r.OpenBlockers(ctx, []uuid.UUID{design.Id, build.Id, ship.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{ship.Id: {build.Id}}, open)
+
+	assert.NoError(t, r.RemoveDependency(ctx, ship.Id, build.Id))
+	assert.ErrorIs(t, r.RemoveDependency(ctx, ship.Id, build.Id), sql.ErrNoRows)
+}
+
+func TestMemoryTaskRepository_History(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryTaskRepository()
+	ownerID, editorID := uuid.New(), uuid.New()
+
+	created, err := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Draft", Priority: task.PriorityLow}, ownerID))
+	require.NoError(t, err)
+	blocker, err := r.Create(ctx, task.NewTask(ctx, &task.Task{Title: "Blocker"}, ownerID))
+	require.NoError(t, err)
+
+	edited, err := r.FindById(ctx, created.Id)
+	require.NoError(t, err)
+	edited.Title = "Final"
+	edited.SetStatus(task.StatusInProgress, task.CategoryActive, editorID)
+	_, err = r.UpdateById(ctx, created.Id, edited)
+	require.NoError(t, err)
+
+	// Saving without changes records nothing.
+	_, err = r.UpdateById(ctx, created.Id, edited)
+	require.NoError(t, err)
+
+	require.NoError(t, r.AddDependency(ctx, created.Id, blocker.Id))
+	require.NoError(t, r.MarkAsCompleted(ctx, created.Id, editorID))
+	require.NoError(t, r.DeleteById(ctx, created.Id, ownerID))
+
+	history, err := r.FindHistory(ctx, created.Id)
+	require.NoError(t, err)
+	require.Len(t, history, 6)
+
+	assert.Equal(t, task.ActionCreated, history[0].Action)
+	assert.Equal(t, ownerID, history[0].ActorID)
+
+	assert.Equal(t, "status", history[1].Field)
+	assert.Equal(t, editorID, history[1].ActorID)
+	assert.JSONEq(t, `"todo"`, string(history[1].Old))
+	assert.JSONEq(t, `"in_progress"`, string(history[1].New))
+	assert.Equal(t, "title", history[2].Field)
+	assert.JSONEq(t, `"Final"`, string(history[2].New))
+
+	assert.Equal(t, task.FieldBlockedBy, history[3].Field)
+	assert.Equal(t, ownerID, history[3].ActorID)
+	assert.Nil(t, history[3].Old)
+	assert.JSONEq(t, `"`+blocker.Id.String()+`"`, string(history[3].New))
+
+	assert.Equal(t, "status", history[4].Field)
+	assert.JSONEq(t, `"done"`, string(history[4].New))
+	assert.Equal(t, task.ActionDeleted, history[5].Action)
+
+	others, err := r.FindHistory(ctx, blocker.Id)
+	require.NoError(t, err)
+	assert.Len(t, others, 1)
+}
@@ -3,20 +3,24 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"taskhub/config"
 	"taskhub/internal/domains/task"
 	"taskhub/pkg/db"
 	"taskhub/pkg/logger"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/fx"
 )
 
 var TaskRepositoryModule = fx.Module(
 	"task-repo",
-	fx.Provide(NewTaskRepository),
+	fx.Provide(fx.Annotate(NewTaskRepository, fx.As(new(task.TaskStore)))),
 )
 
 type TaskRepository struct {
@@ -24,6 +28,8 @@ type TaskRepository struct {
 	logger *logger.Logger
 }
 
+var _ task.TaskStore = (*TaskRepository)(nil)
+
 func NewTaskRepository(config *config.Config, logger *logger.Logger) *TaskRepository {
 	conn := db.NewDB(config).GetConnection()
 	return &TaskRepository{
@@ -32,28 +38,133 @@ func NewTaskRepository(config *config.Config, logger *logger.Logger) *TaskReposi
 	}
 }
 
+// checklistJSON encodes a checklist for the JSONB checklist column, which
+// holds an empty array rather than NULL.
+func checklistJSON(items []task.ChecklistItem) ([]byte, error) {
+	if items == nil {
+		items = []task.ChecklistItem{}
+	}
+	return json.Marshal(items)
+}
+
+// recurrenceJSON encodes a recurrence for the nullable JSONB recurrence
+// column.
+func recurrenceJSON(rec *task.Recurrence) ([]byte, error) {
+	if rec == nil {
+		return nil, nil
+	}
+	return json.Marshal(rec)
+}
+
+// uuidArray wraps ids for a uuid[] parameter.
+func uuidArray(ids []uuid.UUID) interface{} {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return pq.Array(strs)
+}
+
+func distinctIDs(ids []uuid.UUID) []uuid.UUID {
+	var distinct []uuid.UUID
+	for _, id := range ids {
+		if !slices.Contains(distinct, id) {
+			distinct = append(distinct, id)
+		}
+	}
+	return distinct
+}
+
+// setLabels replaces the task's rows in task_labels. Callers run it in the
+// transaction that saves the task.
+func (r *TaskRepository) setLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error {
+	conn := db.Conn(ctx, r.conn)
+	if _, err := conn.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = $1`, taskID); err != nil {
+		return err
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	_, err := conn.ExecContext(ctx, `INSERT INTO task_labels (task_id, label_id) SELECT $1, unnest($2::uuid[])`, taskID, uuidArray(labelIDs))
+	return err
+}
+
+// fillStatusCategory gives a task built without a status category the
+// category of its status in the default workflow.
+func fillStatusCategory(t *task.Task) {
+	if t.StatusCategory == "" {
+		t.StatusCategory = task.DefaultCategory(t.Status)
+	}
+}
+
 func (r *TaskRepository) Create(ctx context.Context, t *task.Task) (*task.Task, error) {
-	query := `INSERT INTO tasks (id, title, description, status, priority, deadline, user_id, created_at, created_by)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	query := `INSERT INTO tasks (id, title, description, status, status_category, priority, deadline, user_id, created_at, created_by, parent_id, checklist,
+              recurrence, project_id)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
+
+	fillStatusCategory(t)
+	checklist, err := checklistJSON(t.Checklist)
+	if err != nil {
+		return nil, err
+	}
+	recurrence, err := recurrenceJSON(t.Recurrence)
+	if err != nil {
+		return nil, err
+	}
 
 	var id uuid.UUID
-	err := r.conn.QueryRowContext(ctx, query,
-		t.Id, t.Title, t.Description, t.Status, t.Priority, t.Deadline, t.UserID, t.CreatedAt, t.CreatedBy,
+	err = db.Conn(ctx, r.conn).QueryRowContext(ctx, query,
+		t.Id, t.Title, t.Description, t.Status, t.StatusCategory, t.Priority, t.Deadline, t.UserID, t.CreatedAt, t.CreatedBy, t.ParentID, checklist,
+		recurrence, t.ProjectID,
 	).Scan(&id)
 	if err != nil {
 		return nil, err
 	}
+	if len(t.LabelIDs) > 0 {
+		if err := r.setLabels(ctx, id, t.LabelIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := task.NewChange(id, t.CreatedBy, task.ActionCreated, "", nil, nil, t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordChanges(ctx, created); err != nil {
+		return nil, err
+	}
 
 	t.Id = id
 	return t, nil
 }
 
 func (r *TaskRepository) UpdateById(ctx context.Context, id uuid.UUID, t *task.Task) (*task.Task, error) {
-	query := `UPDATE tasks SET title = $1, description = $2, status = $3, priority = $4, deadline = $5, updated_at = $6, updated_by = $7
-              WHERE id = $8`
+	query := `UPDATE tasks SET title = $1, description = $2, status = $3, status_category = $4, priority = $5, deadline = $6, updated_at = $7,
+              updated_by = $8, parent_id = $9, checklist = $10, recurrence = $11, project_id = $12
+              WHERE id = $13`
 
-	result, err := r.conn.ExecContext(ctx, query,
-		t.Title, t.Description, t.Status, t.Priority, t.Deadline, t.UpdateAt, t.UpdateBy, id,
+	fillStatusCategory(t)
+	checklist, err := checklistJSON(t.Checklist)
+	if err != nil {
+		return nil, err
+	}
+	recurrence, err := recurrenceJSON(t.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	// The row is locked until the surrounding transaction ends, so the
+	// recorded changes are against the version being replaced.
+	conn := db.Conn(ctx, r.conn)
+	before, err := scanTask(conn.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1 FOR UPDATE`, id), false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := conn.ExecContext(ctx, query,
+		t.Title, t.Description, t.Status, t.StatusCategory, t.Priority, t.Deadline, t.UpdateAt, t.UpdateBy, t.ParentID, checklist, recurrence,
+		t.ProjectID, id,
 	)
 	if err != nil {
 		return nil, err
@@ -63,22 +174,33 @@ func (r *TaskRepository) UpdateById(ctx context.Context, id uuid.UUID, t *task.T
 	if rowsAffected == 0 {
 		return nil, sql.ErrNoRows
 	}
+	if err := r.setLabels(ctx, id, t.LabelIDs); err != nil {
+		return nil, err
+	}
+
+	actorID, at := before.UserID, time.Now()
+	if t.UpdateBy != nil {
+		actorID = *t.UpdateBy
+	}
+	if t.UpdateAt != nil {
+		at = *t.UpdateAt
+	}
+	changes, err := task.UpdateChanges(before, t, actorID, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordChanges(ctx, changes...); err != nil {
+		return nil, err
+	}
 
 	t.Id = id
 	return t, nil
 }
 
 func (r *TaskRepository) FindById(ctx context.Context, id uuid.UUID) (*task.Task, error) {
-	query := `SELECT id, title, description, status, priority, deadline, user_id, created_at, created_by, updated_at, updated_by
-              FROM tasks WHERE id = $1 AND deleted_at IS NULL`
-
-	var t task.Task
-	var deadline, updatedAt sql.NullTime
-	var updatedBy sql.NullString
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
 
-	err := r.conn.QueryRowContext(ctx, query, id).Scan(
-		&t.Id, &t.Title, &t.Description, &t.Status, &t.Priority, &deadline, &t.UserID, &t.CreatedAt, &t.CreatedBy, &updatedAt, &updatedBy,
-	)
+	t, err := scanTask(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id), false)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -86,95 +208,311 @@ func (r *TaskRepository) FindById(ctx context.Context, id uuid.UUID) (*task.Task
 		return nil, err
 	}
 
-	if deadline.Valid {
-		t.Deadline = &deadline.Time
-	}
-	if updatedAt.Valid {
-		t.UpdateAt = &updatedAt.Time
+	return t, nil
+}
+
+const taskColumns = `id, title, description, status, status_category, priority, deadline, user_id, created_at, created_by, updated_at, updated_by, parent_id, checklist, recurrence,
+	project_id, archived_at, ARRAY(SELECT label_id FROM task_labels WHERE task_id = tasks.id ORDER BY label_id)`
+
+// snippetWords caps the length of search snippets.
+const snippetWords = 20
+
+// Options for ts_headline; the markers are turned into <mark> tags by
+// task.HighlightHTML once the text around them is escaped.
+var (
+	titleHeadlineOptions   = "HighlightAll=true, StartSel=" + task.HighlightStart + ", StopSel=" + task.HighlightStop
+	snippetHeadlineOptions = fmt.Sprintf("MaxWords=%d, MinWords=5, MaxFragments=2, StartSel=%s, StopSel=%s", snippetWords, task.HighlightStart, task.HighlightStop)
+)
+
+// sortKeys are the SQL expressions behind each sort field. They match the
+// keyset indexes in migration 0005 and task.Sort.Compare: missing deadlines
+// sort as infinity and priorities by rank. The relevance key depends on the
+// query and is built by sortKey.
+var sortKeys = map[task.SortField]struct {
+	expr string
+	cast string
+}{
+	task.SortCreatedAt: {"created_at", "timestamp"},
+	task.SortDeadline:  {"COALESCE(deadline, 'infinity'::timestamp)", "timestamp"},
+	task.SortPriority:  {"CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 ELSE 0 END", "int"},
+	task.SortTitle:     {"title", "text"},
+}
+
+func sortKey(field task.SortField, tsquery string) (expr string, cast string) {
+	if field == task.SortRelevance && tsquery != "" {
+		return rankExpr(tsquery), "real"
 	}
-	if updatedBy.Valid {
-		uid, _ := uuid.Parse(updatedBy.String)
-		t.UpdateBy = &uid
+	key, ok := sortKeys[field]
+	if !ok {
+		key = sortKeys[task.SortCreatedAt]
 	}
+	return key.expr, key.cast
+}
 
-	return &t, nil
+func rankExpr(tsquery string) string {
+	return "ts_rank_cd(search_vector, " + tsquery + ")"
 }
 
-func (r *TaskRepository) FindAll(ctx context.Context, filter *task.TaskFilter) ([]*task.Task, error) {
-	query := `SELECT id, title, description, status, priority, deadline, user_id, created_at, created_by, updated_at, updated_by
-              FROM tasks WHERE deleted_at IS NULL`
+// selectColumns lists taskColumns plus, for a search, the rank and
+// headlines scanned into task.Match.
+func selectColumns(tsquery string) string {
+	if tsquery == "" {
+		return taskColumns
+	}
+	return taskColumns + fmt.Sprintf(`, %s, ts_headline('english', title, %s, '%s'), ts_headline('english', COALESCE(description, ''), %s, '%s')`,
+		rankExpr(tsquery), tsquery, titleHeadlineOptions, tsquery, snippetHeadlineOptions)
+}
 
-	args := []interface{}{}
-	argIndex := 1
+// whereClause renders filter as SQL conditions on live tasks, numbering
+// placeholders after the given args. Archived tasks are left out unless
+// filter.Archived is set. For a search it also returns the tsquery
+// expression to rank and highlight with.
+func whereClause(filter *task.TaskFilter, args []interface{}) (string, []interface{}, string) {
+	conditions := []string{"deleted_at IS NULL"}
+	tsquery := ""
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
 
-	if filter != nil {
-		conditions := []string{}
+	if filter != nil && filter.Archived {
+		conditions = append(conditions, "archived_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "archived_at IS NULL")
+	}
 
+	if filter != nil {
 		if filter.Status != nil {
-			conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-			args = append(args, *filter.Status)
-			argIndex++
+			conditions = append(conditions, "status = "+arg(*filter.Status))
 		}
 
 		if filter.Priority != nil {
-			conditions = append(conditions, fmt.Sprintf("priority = $%d", argIndex))
-			args = append(args, *filter.Priority)
-			argIndex++
+			conditions = append(conditions, "priority = "+arg(*filter.Priority))
 		}
 
 		if filter.UserID != nil {
-			conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
-			args = append(args, *filter.UserID)
-			argIndex++
+			conditions = append(conditions, "user_id = "+arg(*filter.UserID))
 		}
 
 		if filter.Deadline != nil {
-			conditions = append(conditions, fmt.Sprintf("deadline <= $%d", argIndex))
-			args = append(args, *filter.Deadline)
-			argIndex++
+			conditions = append(conditions, "deadline <= "+arg(*filter.Deadline))
+		}
+
+		if filter.ParentID != nil {
+			conditions = append(conditions, "parent_id = "+arg(*filter.ParentID))
+		}
+
+		if filter.TopLevel {
+			conditions = append(conditions, "parent_id IS NULL")
+		}
+
+		if filter.ProjectID != nil {
+			conditions = append(conditions, "project_id = "+arg(*filter.ProjectID))
+		}
+
+		if filter.SeriesID != nil {
+			conditions = append(conditions, "recurrence->>'series_id' = "+arg(filter.SeriesID.String()))
+		}
+
+		if len(filter.LabelsAny) > 0 {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM task_labels WHERE task_id = tasks.id AND label_id = ANY("+arg(uuidArray(filter.LabelsAny))+"::uuid[]))")
+		}
+
+		if len(filter.LabelsAll) > 0 {
+			all := distinctIDs(filter.LabelsAll)
+			conditions = append(conditions, "(SELECT COUNT(*) FROM task_labels WHERE task_id = tasks.id AND label_id = ANY("+arg(uuidArray(all))+"::uuid[])) = "+arg(len(all)))
+		}
+
+		for _, c := range filter.Conditions {
+			conditions = append(conditions, conditionSQL(c, arg))
 		}
 
-		if len(conditions) > 0 {
-			query += " AND " + strings.Join(conditions, " AND ")
+		if filter.Search != "" {
+			tsquery = "websearch_to_tsquery('english', " + arg(filter.Search) + ")"
+			conditions = append(conditions, "search_vector @@ "+tsquery)
 		}
 	}
 
-	query += " ORDER BY created_at DESC"
+	return " WHERE " + strings.Join(conditions, " AND "), args, tsquery
+}
 
-	rows, err := r.conn.QueryContext(ctx, query, args...)
-	if err != nil {
+// queryColumns maps query fields to task columns.
+var queryColumns = map[task.QueryField]string{
+	task.QueryStatus:   "status",
+	task.QueryCategory: "status_category",
+	task.QueryPriority: "priority",
+	task.QueryDue:      "deadline",
+	task.QueryCreated:  "created_at",
+}
+
+// conditionSQL compiles a query condition into a parameterized SQL
+// predicate. A negated predicate treats NULL as false, so tasks without a
+// deadline match a negated due range, like task.Condition.Matches.
+func conditionSQL(c task.Condition, arg func(interface{}) string) string {
+	column := queryColumns[c.Field]
+
+	var parts []string
+	switch {
+	case len(c.Values) > 0:
+		parts = append(parts, column+" = ANY("+arg(pq.Array(c.Values))+")")
+	case c.Missing:
+		parts = append(parts, column+" IS NULL")
+	default:
+		if !c.From.IsZero() {
+			parts = append(parts, column+" >= "+arg(c.From))
+		}
+		if !c.To.IsZero() {
+			parts = append(parts, column+" < "+arg(c.To))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, column+" IS NOT NULL")
+		}
+	}
+
+	predicate := "(" + strings.Join(parts, " AND ") + ")"
+	if c.Negate {
+		return "NOT COALESCE(" + predicate + ", false)"
+	}
+	return predicate
+}
+
+// scanTask scans a row of selectColumns.
+func scanTask(row interface{ Scan(...any) error }, withMatch bool) (*task.Task, error) {
+	var t task.Task
+	var deadline, updatedAt, archivedAt sql.NullTime
+	var updatedBy, parentID, projectID sql.NullString
+	var checklist, recurrence []byte
+	var labelIDs []string
+
+	dest := []interface{}{
+		&t.Id, &t.Title, &t.Description, &t.Status, &t.StatusCategory, &t.Priority, &deadline, &t.UserID, &t.CreatedAt, &t.CreatedBy, &updatedAt, &updatedBy,
+		&parentID, &checklist, &recurrence, &projectID, &archivedAt, pq.Array(&labelIDs),
+	}
+	var match task.SearchMatch
+	if withMatch {
+		dest = append(dest, &match.Rank, &match.Title, &match.Snippet)
+	}
+
+	if err := row.Scan(dest...); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
+	if deadline.Valid {
+		t.Deadline = &deadline.Time
+	}
+	if updatedAt.Valid {
+		t.UpdateAt = &updatedAt.Time
+	}
+	if updatedBy.Valid {
+		uid, _ := uuid.Parse(updatedBy.String)
+		t.UpdateBy = &uid
+	}
+	if parentID.Valid {
+		pid, _ := uuid.Parse(parentID.String)
+		t.ParentID = &pid
+	}
+	if projectID.Valid {
+		pid, _ := uuid.Parse(projectID.String)
+		t.ProjectID = &pid
+	}
+	if archivedAt.Valid {
+		t.ArchivedAt = &archivedAt.Time
+	}
+	if len(checklist) > 0 {
+		if err := json.Unmarshal(checklist, &t.Checklist); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range labelIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		t.LabelIDs = append(t.LabelIDs, id)
+	}
+	if len(recurrence) > 0 {
+		t.Recurrence = &task.Recurrence{}
+		if err := json.Unmarshal(recurrence, t.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+	if withMatch {
+		match.Title = task.HighlightHTML(match.Title)
+		match.Snippet = task.HighlightHTML(match.Snippet)
+		t.Match = &match
+	}
+
+	return &t, nil
+}
+
+func scanTasks(rows *sql.Rows, withMatch bool) ([]*task.Task, error) {
 	var tasks []*task.Task
 	for rows.Next() {
-		var t task.Task
-		var deadline, updatedAt sql.NullTime
-		var updatedBy sql.NullString
-
-		err := rows.Scan(
-			&t.Id, &t.Title, &t.Description, &t.Status, &t.Priority, &deadline, &t.UserID, &t.CreatedAt, &t.CreatedBy, &updatedAt, &updatedBy,
-		)
+		t, err := scanTask(rows, withMatch)
 		if err != nil {
 			return nil, err
 		}
+		tasks = append(tasks, t)
+	}
 
-		if deadline.Valid {
-			t.Deadline = &deadline.Time
-		}
-		if updatedAt.Valid {
-			t.UpdateAt = &updatedAt.Time
-		}
-		if updatedBy.Valid {
-			uid, _ := uuid.Parse(updatedBy.String)
-			t.UpdateBy = &uid
-		}
+	return tasks, rows.Err()
+}
 
-		tasks = append(tasks, &t)
+func (r *TaskRepository) FindAll(ctx context.Context, filter *task.TaskFilter) ([]*task.Task, error) {
+	where, args, tsquery := whereClause(filter, nil)
+	query := `SELECT ` + selectColumns(tsquery) + ` FROM tasks` + where + ` ORDER BY created_at DESC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return tasks, nil
+	return scanTasks(rows, tsquery != "")
+}
+
+func (r *TaskRepository) FindPage(ctx context.Context, filter *task.TaskFilter, page *task.PageRequest) (*task.Page, error) {
+	where, args, tsquery := whereClause(filter, nil)
+
+	result := &task.Page{}
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks`+where, args...).Scan(&result.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	key, cast := sortKey(page.Sort.Field, tsquery)
+	dir, cmp := "ASC", ">"
+	if page.Sort.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if page.After != nil {
+		args = append(args, page.After.Value, page.After.ID)
+		where += fmt.Sprintf(" AND (%s, id) %s ($%d::%s, $%d)", key, cmp, len(args)-1, cast, len(args))
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	args = append(args, page.Limit+1)
+	query := fmt.Sprintf(`SELECT %s FROM tasks%s ORDER BY %s %s, id %s LIMIT $%d`, selectColumns(tsquery), where, key, dir, dir, len(args))
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows, tsquery != "")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) > page.Limit {
+		tasks = tasks[:page.Limit]
+		result.Next = task.CursorAt(page.Sort, tasks[len(tasks)-1])
+	}
+	result.Tasks = tasks
+
+	return result, nil
 }
 
 func (r *TaskRepository) FindByUserId(ctx context.Context, userID uuid.UUID, filter *task.TaskFilter) ([]*task.Task, error) {
@@ -186,9 +524,10 @@ func (r *TaskRepository) FindByUserId(ctx context.Context, userID uuid.UUID, fil
 }
 
 func (r *TaskRepository) DeleteById(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	query := `UPDATE tasks SET deleted_at = NOW(), deleted_by = $1 WHERE id = $2`
+	query := `UPDATE tasks SET deleted_at = $1, deleted_by = $2 WHERE id = $3`
 
-	result, err := r.conn.ExecContext(ctx, query, userID, id)
+	now := time.Now()
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, now, userID, id)
 	if err != nil {
 		return err
 	}
@@ -198,66 +537,137 @@ func (r *TaskRepository) DeleteById(ctx context.Context, id uuid.UUID, userID uu
 		return sql.ErrNoRows
 	}
 
-	return nil
+	deleted, err := task.NewChange(id, userID, task.ActionDeleted, "", nil, nil, now)
+	if err != nil {
+		return err
+	}
+	return r.recordChanges(ctx, deleted)
 }
 
 func (r *TaskRepository) MarkAsCompleted(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	query := `UPDATE tasks SET status = $1, updated_at = NOW(), updated_by = $2 WHERE id = $3`
-
-	result, err := r.conn.ExecContext(ctx, query, task.StatusDone, userID, id)
+	query := `UPDATE tasks t SET status = $1, status_category = $2, updated_at = $3, updated_by = $4
+              FROM (SELECT id, status FROM tasks WHERE id = $5 FOR UPDATE) old
+              WHERE t.id = old.id
+              RETURNING old.status`
+
+	now := time.Now()
+	var oldStatus task.TaskStatus
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, task.StatusDone, task.CategoryClosed, now, userID, id).Scan(&oldStatus)
 	if err != nil {
 		return err
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if oldStatus == task.StatusDone {
+		return nil
 	}
 
-	return nil
+	c, err := task.NewChange(id, userID, task.ActionUpdated, "status", oldStatus, task.StatusDone, now)
+	if err != nil {
+		return err
+	}
+	return r.recordChanges(ctx, c)
 }
 
 func (r *TaskRepository) FindTasksNearDeadline(ctx context.Context, hoursAhead int) ([]*task.Task, error) {
-	query := `SELECT id, title, description, status, priority, deadline, user_id, created_at, created_by, updated_at, updated_by
+	query := `SELECT ` + taskColumns + `
               FROM tasks
               WHERE deleted_at IS NULL
-              AND status != $1
+              AND archived_at IS NULL
+              AND status_category != $1
               AND deadline IS NOT NULL
+              AND deadline > NOW()
               AND deadline <= NOW() + INTERVAL '1 hour' * $2
               ORDER BY deadline ASC`
 
-	rows, err := r.conn.QueryContext(ctx, query, task.StatusDone, hoursAhead)
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, task.CategoryClosed, hoursAhead)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tasks []*task.Task
-	for rows.Next() {
-		var t task.Task
-		var deadline, updatedAt sql.NullTime
-		var updatedBy sql.NullString
+	return scanTasks(rows, false)
+}
 
-		err := rows.Scan(
-			&t.Id, &t.Title, &t.Description, &t.Status, &t.Priority, &deadline, &t.UserID, &t.CreatedAt, &t.CreatedBy, &updatedAt, &updatedBy,
-		)
-		if err != nil {
-			return nil, err
-		}
+func (r *TaskRepository) ArchiveByProject(ctx context.Context, projectID uuid.UUID, archivedAt *time.Time) error {
+	query := `UPDATE tasks t SET archived_at = $1
+              FROM (SELECT id, archived_at FROM tasks WHERE project_id = $2 AND deleted_at IS NULL FOR UPDATE) old
+              WHERE t.id = old.id AND old.archived_at IS DISTINCT FROM $1::timestamp
+              RETURNING t.id, t.user_id, old.archived_at`
 
-		if deadline.Valid {
-			t.Deadline = &deadline.Time
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, archivedAt, projectID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Projects belong to one user, so the owner archived the tasks.
+	now := time.Now()
+	var changes []*task.Change
+	for rows.Next() {
+		var id, ownerID uuid.UUID
+		var old sql.NullTime
+		if err := rows.Scan(&id, &ownerID, &old); err != nil {
+			return err
 		}
-		if updatedAt.Valid {
-			t.UpdateAt = &updatedAt.Time
+
+		var oldValue *time.Time
+		if old.Valid {
+			oldValue = &old.Time
 		}
-		if updatedBy.Valid {
-			uid, _ := uuid.Parse(updatedBy.String)
-			t.UpdateBy = &uid
+		c, err := task.NewChange(id, ownerID, task.ActionUpdated, "archived_at", oldValue, archivedAt, now)
+		if err != nil {
+			return err
 		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return r.recordChanges(ctx, changes...)
+}
+
+func (r *TaskRepository) FindChildren(ctx context.Context, parentID uuid.UUID) ([]*task.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL AND archived_at IS NULL ORDER BY created_at ASC, id ASC`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows, false)
+}
 
-		tasks = append(tasks, &t)
+func (r *TaskRepository) SubtaskProgress(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]task.Progress, error) {
+	progress := map[uuid.UUID]task.Progress{}
+	if len(parentIDs) == 0 {
+		return progress, nil
+	}
+
+	ids := make([]string, len(parentIDs))
+	for i, id := range parentIDs {
+		ids[i] = id.String()
+	}
+
+	query := `SELECT parent_id, COUNT(*) FILTER (WHERE status_category = $1), COUNT(*)
+              FROM tasks
+              WHERE parent_id = ANY($2::uuid[]) AND deleted_at IS NULL AND archived_at IS NULL
+              GROUP BY parent_id`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, task.CategoryClosed, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID uuid.UUID
+		var done, total int
+		if err := rows.Scan(&parentID, &done, &total); err != nil {
+			return nil, err
+		}
+		progress[parentID] = task.NewProgress(done, total)
 	}
 
-	return tasks, nil
+	return progress, rows.Err()
 }
@@ -0,0 +1,236 @@
+package task
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight markers wrap matched words in raw snippets. They are private-use
+// runes so they cannot collide with user text; HighlightHTML turns them into
+// <mark> tags after escaping everything else.
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+// SearchMatch describes why a task matched a full-text search. Title and
+// Snippet are HTML-safe with matched words wrapped in <mark>.
+type SearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+}
+
+// HighlightHTML escapes a raw snippet and turns its highlight markers into
+// <mark> tags.
+func HighlightHTML(raw string) string {
+	escaped := html.EscapeString(raw)
+	return strings.NewReplacer(HighlightStart, "<mark>", HighlightStop, "</mark>").Replace(escaped)
+}
+
+// SearchQuery is a parsed web search in the syntax of Postgres'
+// websearch_to_tsquery: words are ANDed, "quoted text" is a phrase, a
+// leading - excludes a word or phrase, and OR separates alternatives. It
+// backs the in-memory repository; Postgres parses the query itself.
+type SearchQuery struct {
+	// any holds the OR'd alternatives; each is a list of ANDed terms.
+	any [][]searchTerm
+}
+
+type searchTerm struct {
+	words   []string
+	exclude bool
+}
+
+func ParseSearch(q string) *SearchQuery {
+	query := &SearchQuery{}
+	var group []searchTerm
+	exclude := false
+
+	for len(q) > 0 {
+		r := rune(q[0])
+		switch {
+		case unicode.IsSpace(r):
+			q = q[1:]
+		case r == '-':
+			exclude = true
+			q = q[1:]
+		case r == '"':
+			end := strings.IndexByte(q[1:], '"')
+			phrase := q[1:]
+			if end >= 0 {
+				phrase, q = q[1:end+1], q[end+2:]
+			} else {
+				q = ""
+			}
+			if words := searchWords(phrase); len(words) > 0 {
+				group = append(group, searchTerm{words: words, exclude: exclude})
+			}
+			exclude = false
+		default:
+			end := strings.IndexFunc(q, unicode.IsSpace)
+			if end < 0 {
+				end = len(q)
+			}
+			token := q[:end]
+			q = q[end:]
+			if strings.EqualFold(token, "or") && !exclude {
+				if len(group) > 0 {
+					query.any = append(query.any, group)
+					group = nil
+				}
+				continue
+			}
+			for _, word := range searchWords(token) {
+				group = append(group, searchTerm{words: []string{word}, exclude: exclude})
+			}
+			exclude = false
+		}
+	}
+	if len(group) > 0 {
+		query.any = append(query.any, group)
+	}
+
+	return query
+}
+
+// searchWords splits text into lowercase words of letters and digits.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// wordMatches approximates stemming by letting a query word match any word
+// it prefixes, so "run" finds "running".
+func wordMatches(word, term string) bool {
+	return strings.HasPrefix(word, term)
+}
+
+// hits returns the index of every word in text that starts an occurrence of
+// the term.
+func (term searchTerm) hits(words []string) []int {
+	var hits []int
+	for i := 0; i+len(term.words) <= len(words); i++ {
+		match := true
+		for j, w := range term.words {
+			if !wordMatches(words[i+j], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+// Match scores t against the query and reports whether it matched. Title
+// hits weigh more than description hits, like the weights on the Postgres
+// search vector.
+func (q *SearchQuery) Match(t *Task) (float64, bool) {
+	title := searchWords(t.Title)
+	description := searchWords(t.Description)
+
+	best, matched := 0.0, false
+	for _, group := range q.any {
+		rank, ok := 0.0, true
+		for _, term := range group {
+			n := len(term.hits(title))*10 + len(term.hits(description))
+			if term.exclude {
+				ok = ok && n == 0
+			} else {
+				ok = ok && n > 0
+				rank += float64(n)
+			}
+		}
+		if ok {
+			matched = true
+			best = max(best, rank)
+		}
+	}
+
+	return best, matched
+}
+
+// Headline returns text with matched words wrapped in highlight markers.
+// With maxWords > 0 it is cut to a window of that many words around the
+// first match.
+func (q *SearchQuery) Headline(text string, maxWords int) string {
+	spans := wordSpans(text)
+	lower := make([]string, len(spans))
+	for i, sp := range spans {
+		lower[i] = strings.ToLower(text[sp[0]:sp[1]])
+	}
+
+	marked := make([]bool, len(lower))
+	first := -1
+	for _, group := range q.any {
+		for _, term := range group {
+			if term.exclude {
+				continue
+			}
+			for _, i := range term.hits(lower) {
+				for j := range term.words {
+					marked[i+j] = true
+				}
+				if first < 0 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	from, to := 0, len(spans)
+	if maxWords > 0 && len(spans) > maxWords {
+		from = max(first-maxWords/4, 0)
+		to = min(from+maxWords, len(spans))
+	}
+	if from == to {
+		return ""
+	}
+
+	var b strings.Builder
+	pos := spans[from][0]
+	if from == 0 {
+		pos = 0
+	}
+	for i := from; i < to; i++ {
+		b.WriteString(text[pos:spans[i][0]])
+		word := text[spans[i][0]:spans[i][1]]
+		if marked[i] {
+			b.WriteString(HighlightStart + word + HighlightStop)
+		} else {
+			b.WriteString(word)
+		}
+		pos = spans[i][1]
+	}
+	if to == len(spans) {
+		b.WriteString(text[pos:])
+	}
+
+	return b.String()
+}
+
+// wordSpans returns the byte offsets of each word in text, using the same
+// word definition as searchWords.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,48 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQuery_Match(t *testing.T) {
+	report := &Task{Title: "Quarterly report", Description: "Draft the sales numbers"}
+	budget := &Task{Title: "Budget review", Description: "Check the quarterly sales budget"}
+
+	cases := []struct {
+		query  string
+		report bool
+		budget bool
+	}{
+		{"quarterly", true, true},
+		{"quarterly sales", true, true},
+		{"quarterly -budget", true, false},
+		{`"sales budget"`, false, true},
+		{`"budget sales"`, false, false},
+		{"draft or review", true, true},
+		{"draft OR missing", true, false},
+		{"QUARTER", true, true},
+	}
+	for _, tc := range cases {
+		q := ParseSearch(tc.query)
+		_, ok := q.Match(report)
+		assert.Equal(t, tc.report, ok, tc.query)
+		_, ok = q.Match(budget)
+		assert.Equal(t, tc.budget, ok, tc.query)
+	}
+
+	titleRank, _ := ParseSearch("budget").Match(budget)
+	descriptionRank, _ := ParseSearch("check").Match(budget)
+	assert.Greater(t, titleRank, descriptionRank)
+}
+
+func TestSearchQuery_Headline(t *testing.T) {
+	q := ParseSearch(`report -draft`)
+
+	assert.Equal(t, "Quarterly "+HighlightStart+"report"+HighlightStop+"!", q.Headline("Quarterly report!", 0))
+	assert.Equal(t, "Quarterly <mark>report</mark> &lt;b&gt;", HighlightHTML(q.Headline("Quarterly report <b>", 0)))
+
+	long := "one two three four five six seven eight report nine ten eleven twelve"
+	assert.Equal(t, "eight "+HighlightStart+"report"+HighlightStop+" nine ten eleven twelve", q.Headline(long, 6))
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidParent         = errors.New("invalid parent task")
+	ErrMaxDepth              = errors.New("subtasks are nested too deep")
+	ErrOpenSubtasks          = errors.New("task has open subtasks")
+	ErrInvalidChecklistItem  = errors.New("checklist item needs text of at most 500 characters")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+)
+
+// MaxDepth is how many levels of subtasks a top-level task can have below
+// it. A top-level task is at depth 0.
+const MaxDepth = 5
+
+const maxChecklistTextLength = 500
+
+// ChecklistItem is a step stored inline on its task. Unlike subtasks, items
+// have no status, deadline or events of their own.
+type ChecklistItem struct {
+	ID   uuid.UUID `json:"id"`
+	Text string    `json:"text"`
+	Done bool      `json:"done"`
+}
+
+// NewChecklistItem trims text and returns an open item, or
+// ErrInvalidChecklistItem when text is empty or too long.
+func NewChecklistItem(text string) (ChecklistItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxChecklistTextLength {
+		return ChecklistItem{}, ErrInvalidChecklistItem
+	}
+	return ChecklistItem{ID: uuid.New(), Text: text}, nil
+}
+
+// ChecklistItem returns a pointer to the item with the given id so it can be
+// edited in place, or nil.
+func (t *Task) ChecklistItem(id uuid.UUID) *ChecklistItem {
+	for i := range t.Checklist {
+		if t.Checklist[i].ID == id {
+			return &t.Checklist[i]
+		}
+	}
+	return nil
+}
+
+// RemoveChecklistItem deletes the item with the given id and reports whether
+// it existed.
+func (t *Task) RemoveChecklistItem(id uuid.UUID) bool {
+	for i, item := range t.Checklist {
+		if item.ID == id {
+			t.Checklist = append(t.Checklist[:i:i], t.Checklist[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ChecklistDone counts the checked and total checklist items.
+func (t *Task) ChecklistDone() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+// Progress summarizes a task's direct subtasks.
+type Progress struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
+}
+
+func NewProgress(done, total int) Progress {
+	p := Progress{Done: done, Total: total}
+	if total > 0 {
+		p.Percent = done * 100 / total
+	}
+	return p
+}
+
+// Open reports whether any subtask is unfinished.
+func (p *Progress) Open() bool {
+	return p != nil && p.Done < p.Total
+}
@@ -0,0 +1,54 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChecklistItem(t *testing.T) {
+	item, err := NewChecklistItem("  Book venue ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Book venue", item.Text)
+	assert.False(t, item.Done)
+	assert.NotEqual(t, uuid.Nil, item.ID)
+
+	_, err = NewChecklistItem("   ")
+	assert.ErrorIs(t, err, ErrInvalidChecklistItem)
+
+	_, err = NewChecklistItem(strings.Repeat("x", 501))
+	assert.ErrorIs(t, err, ErrInvalidChecklistItem)
+}
+
+func TestTask_Checklist(t *testing.T) {
+	first, _ := NewChecklistItem("first")
+	second, _ := NewChecklistItem("second")
+	tk := &Task{Checklist: []ChecklistItem{first, second}}
+
+	tk.ChecklistItem(second.ID).Done = true
+	done, total := tk.ChecklistDone()
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 2, total)
+
+	assert.Nil(t, tk.ChecklistItem(uuid.New()))
+	assert.False(t, tk.RemoveChecklistItem(uuid.New()))
+
+	assert.True(t, tk.RemoveChecklistItem(first.ID))
+	assert.Len(t, tk.Checklist, 1)
+	assert.Equal(t, second.ID, tk.Checklist[0].ID)
+}
+
+func TestNewProgress(t *testing.T) {
+	assert.Equal(t, Progress{Done: 1, Total: 3, Percent: 33}, NewProgress(1, 3))
+	assert.Equal(t, Progress{}, NewProgress(0, 0))
+
+	p := NewProgress(2, 2)
+	assert.False(t, p.Open())
+	p = NewProgress(1, 2)
+	assert.True(t, p.Open())
+
+	var none *Progress
+	assert.False(t, none.Open())
+}
@@ -2,6 +2,9 @@ package task
 
 import (
 	"context"
+	"regexp"
+	"slices"
+	"taskhub/internal/domains/label"
 	"taskhub/pkg/base/entity"
 	"time"
 
@@ -11,12 +14,53 @@ import (
 type TaskStatus string
 type TaskPriority string
 
+// The statuses of the default workflow. Other workflows name their own.
 const (
 	StatusTodo       TaskStatus = "todo"
 	StatusInProgress TaskStatus = "in_progress"
 	StatusDone       TaskStatus = "done"
 )
 
+// StatusCategory groups the statuses of every workflow: open work has not
+// started, active work is under way and closed work is finished.
+type StatusCategory string
+
+const (
+	CategoryOpen   StatusCategory = "open"
+	CategoryActive StatusCategory = "active"
+	CategoryClosed StatusCategory = "closed"
+)
+
+var statusCategories = []StatusCategory{CategoryOpen, CategoryActive, CategoryClosed}
+
+// statusName is the form of a status; the tasks.status column holds at most
+// 20 characters.
+var statusName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+// Valid reports whether s is well formed: a lowercase letter followed by up
+// to 19 lowercase letters, digits and underscores.
+func (s TaskStatus) Valid() bool {
+	return statusName.MatchString(string(s))
+}
+
+// Valid reports whether c is one of the three categories.
+func (c StatusCategory) Valid() bool {
+	return slices.Contains(statusCategories, c)
+}
+
+// DefaultCategory returns the category status has in the default workflow.
+// Statuses it does not know are open.
+func DefaultCategory(status TaskStatus) StatusCategory {
+	switch status {
+	case StatusInProgress:
+		return CategoryActive
+	case StatusDone:
+		return CategoryClosed
+	default:
+		return CategoryOpen
+	}
+}
+
 const (
 	PriorityLow    TaskPriority = "low"
 	PriorityMedium TaskPriority = "medium"
@@ -25,28 +69,65 @@ const (
 
 type Task struct {
 	entity.BaseEntity
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Status      TaskStatus   `json:"status"`
-	Priority    TaskPriority `json:"priority"`
-	Deadline    *time.Time   `json:"deadline,omitempty"`
-	UserID      uuid.UUID    `json:"user_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	// StatusCategory is the category of Status in the task's workflow.
+	StatusCategory StatusCategory `json:"status_category"`
+	Priority       TaskPriority   `json:"priority"`
+	Deadline       *time.Time     `json:"deadline,omitempty"`
+	UserID         uuid.UUID      `json:"user_id"`
+	// ParentID is set on subtasks.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	// ProjectID is set on tasks in a project. ArchivedAt is set while that
+	// project is archived; archived tasks are left out of listings.
+	ProjectID  *uuid.UUID      `json:"project_id,omitempty"`
+	ArchivedAt *time.Time      `json:"archived_at,omitempty"`
+	Checklist  []ChecklistItem `json:"checklist,omitempty"`
+	// Recurrence is set on the occurrences of a recurring task.
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// LabelIDs are the attached labels, sorted. The task service resolves
+	// them into Labels.
+	LabelIDs []uuid.UUID    `json:"-"`
+	Labels   []*label.Label `json:"labels,omitempty"`
+	// Progress counts finished subtasks. It is computed by the task service
+	// and only set on tasks that have subtasks.
+	Progress *Progress `json:"progress,omitempty"`
+	// Blocked is set while any task this one depends on is open; BlockedBy
+	// lists those tasks. Both are computed by the task service.
+	Blocked   bool        `json:"blocked"`
+	BlockedBy []uuid.UUID `json:"blocked_by,omitempty"`
+	// Match is set on full-text search results.
+	Match *SearchMatch `json:"match,omitempty"`
 }
 
+// NewTask creates a task for userID from the fields of t. Without a status,
+// it starts in the default workflow's todo.
 func NewTask(ctx context.Context, t *Task, userID uuid.UUID) *Task {
 	now := time.Now()
+	status, category := t.Status, t.StatusCategory
+	if status == "" {
+		status, category = StatusTodo, CategoryOpen
+	}
+
 	return &Task{
 		BaseEntity: entity.BaseEntity{
 			Id:        uuid.New(),
 			CreatedAt: now,
 			CreatedBy: userID,
 		},
-		Title:       t.Title,
-		Description: t.Description,
-		Status:      StatusTodo,
-		Priority:    t.Priority,
-		Deadline:    t.Deadline,
-		UserID:      userID,
+		Title:          t.Title,
+		Description:    t.Description,
+		Status:         status,
+		StatusCategory: category,
+		Priority:       t.Priority,
+		Deadline:       t.Deadline,
+		UserID:         userID,
+		ParentID:       t.ParentID,
+		ProjectID:      t.ProjectID,
+		Checklist:      t.Checklist,
+		Recurrence:     t.Recurrence,
+		LabelIDs:       t.LabelIDs,
 	}
 }
 
@@ -55,18 +136,93 @@ type TaskFilter struct {
 	Priority *TaskPriority
 	UserID   *uuid.UUID
 	Deadline *time.Time
+	// Search is a full-text query in web search syntax; see SearchQuery.
+	Search string
+	// Conditions are the field filters of a Query.
+	Conditions []Condition
+	// ParentID lists the subtasks of a task; TopLevel lists only tasks
+	// without a parent.
+	ParentID *uuid.UUID
+	TopLevel bool
+	// SeriesID lists the occurrences of a recurring task.
+	SeriesID *uuid.UUID
+	// LabelsAny matches tasks with at least one of the labels, LabelsAll
+	// tasks with every one of them.
+	LabelsAny []uuid.UUID
+	LabelsAll []uuid.UUID
+	// ProjectID lists the tasks in a project.
+	ProjectID *uuid.UUID
+	// Archived lists archived tasks instead of live ones.
+	Archived bool
 }
 
-func (t *Task) MarkAsCompleted(userID uuid.UUID) {
+// Closed reports whether the task is finished, whatever its workflow calls
+// the status.
+func (t *Task) Closed() bool {
+	return t.StatusCategory == CategoryClosed
+}
+
+// SetStatus moves the task to status, which has category in its workflow.
+func (t *Task) SetStatus(status TaskStatus, category StatusCategory, userID uuid.UUID) {
 	now := time.Now()
-	t.Status = StatusDone
+	t.Status = status
+	t.StatusCategory = category
 	t.UpdateAt = &now
 	t.UpdateBy = &userID
 }
 
+// MarkAsCompleted moves the task to the default workflow's done.
+func (t *Task) MarkAsCompleted(userID uuid.UUID) {
+	t.SetStatus(StatusDone, CategoryClosed, userID)
+}
+
+// MarkAsInProgress moves the task to the default workflow's in_progress.
 func (t *Task) MarkAsInProgress(userID uuid.UUID) {
-	now := time.Now()
-	t.Status = StatusInProgress
-	t.UpdateAt = &now
-	t.UpdateBy = &userID
+	t.SetStatus(StatusInProgress, CategoryActive, userID)
+}
+
+// TaskStore is the persistence contract the task services depend on. Every
+// mutation records its changes in the task's history.
+type TaskStore interface {
+	Create(ctx context.Context, t *Task) (*Task, error)
+	UpdateById(ctx context.Context, id uuid.UUID, t *Task) (*Task, error)
+	FindById(ctx context.Context, id uuid.UUID) (*Task, error)
+	FindAll(ctx context.Context, filter *TaskFilter) ([]*Task, error)
+	FindByUserId(ctx context.Context, userID uuid.UUID, filter *TaskFilter) ([]*Task, error)
+	// FindPage returns the page of tasks matching filter that follows
+	// page.After in page.Sort order.
+	FindPage(ctx context.Context, filter *TaskFilter, page *PageRequest) (*Page, error)
+	DeleteById(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	// MarkAsCompleted moves a task to the default workflow's done.
+	MarkAsCompleted(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	// FindTasksNearDeadline returns the open tasks due within hoursAhead,
+	// leaving out overdue ones.
+	FindTasksNearDeadline(ctx context.Context, hoursAhead int) ([]*Task, error)
+	// ArchiveByProject sets ArchivedAt on every live task in a project, or
+	// clears it when archivedAt is nil.
+	ArchiveByProject(ctx context.Context, projectID uuid.UUID, archivedAt *time.Time) error
+	// FindChildren returns the live, unarchived direct subtasks of a task,
+	// oldest first.
+	FindChildren(ctx context.Context, parentID uuid.UUID) ([]*Task, error)
+	// SubtaskProgress counts the live, unarchived direct subtasks of each
+	// parent. Parents without subtasks are left out of the result.
+	SubtaskProgress(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]Progress, error)
+	// AddDependency records that taskID is blocked by blockerID. It fails
+	// with ErrDependencyCycle when blockerID already depends on taskID,
+	// directly or transitively, and with ErrDependencyExists for a duplicate.
+	AddDependency(ctx context.Context, taskID, blockerID uuid.UUID) error
+	// RemoveDependency deletes an edge, failing with sql.ErrNoRows when it
+	// does not exist.
+	RemoveDependency(ctx context.Context, taskID, blockerID uuid.UUID) error
+	// FindBlockers returns the live tasks taskID directly depends on, and
+	// FindDependents the live tasks that directly depend on it.
+	FindBlockers(ctx context.Context, taskID uuid.UUID) ([]*Task, error)
+	FindDependents(ctx context.Context, taskID uuid.UUID) ([]*Task, error)
+	// OpenBlockers returns, for each of the given tasks that is blocked, the
+	// ids of its live blockers that are not closed.
+	OpenBlockers(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	// FindHistory returns the changes recorded for a task, oldest first.
+	// Changes nobody made directly, such as archiving with a project or
+	// adding a blocker, are attributed to the task's owner.
+	FindHistory(ctx context.Context, taskID uuid.UUID) ([]*Change, error)
 }
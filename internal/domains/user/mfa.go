@@ -0,0 +1,68 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFA is a user's TOTP enrollment. It protects logins once confirmed with
+// a code from the authenticator app.
+type MFA struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Secret      string     `json:"-"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// LastCounter is the time step of the last code accepted, so that no
+	// code is accepted twice.
+	LastCounter int64     `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Enabled reports whether logins need a second factor.
+func (m *MFA) Enabled() bool {
+	return m != nil && m.ConfirmedAt != nil
+}
+
+// MFAChallenge is the server-side record of an MFA token handed out by a
+// login. It is used up by a successful second factor and stops accepting
+// codes after a few attempts.
+type MFAChallenge struct {
+	Id        uuid.UUID
+	UserID    uuid.UUID
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAStore persists TOTP enrollments, the hashes of recovery codes and MFA
+// challenges.
+type MFAStore interface {
+	// Save creates or replaces the user's enrollment.
+	Save(ctx context.Context, m *MFA) error
+	FindByUserId(ctx context.Context, userID uuid.UUID) (*MFA, error)
+	// UseCounter records that a code from time step counter was accepted,
+	// reporting false when one from it or a later step already was.
+	UseCounter(ctx context.Context, userID uuid.UUID, counter int64) (bool, error)
+	// Delete removes the user's enrollment and recovery codes.
+	Delete(ctx context.Context, userID uuid.UUID) error
+
+	// ReplaceRecoveryCodes swaps the user's recovery codes for hashes.
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error
+	// UseRecoveryCode marks the user's unused code with hash used,
+	// reporting whether there was one.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, at time.Time) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+
+	CreateChallenge(ctx context.Context, c *MFAChallenge) error
+	// AttemptChallenge counts an attempt at the challenge, reporting false
+	// when it is used, expired at at or already has maxAttempts.
+	AttemptChallenge(ctx context.Context, id uuid.UUID, maxAttempts int, at time.Time) (bool, error)
+	// ConsumeChallenge marks the challenge used, reporting false when it
+	// already was.
+	ConsumeChallenge(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// CountChallengeAttempts sums the attempts at the user's unused
+	// challenges created since since.
+	CountChallengeAttempts(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/internal/domains/user"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var ActionTokenRepositoryModule = fx.Module(
+	"action-token-repo",
+	fx.Provide(fx.Annotate(NewActionTokenRepository, fx.As(new(user.ActionTokenStore)))),
+)
+
+type ActionTokenRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ user.ActionTokenStore = (*ActionTokenRepository)(nil)
+
+func NewActionTokenRepository(config *config.Config, logger *logger.Logger) *ActionTokenRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &ActionTokenRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *ActionTokenRepository) Create(ctx context.Context, t *user.ActionToken) error {
+	query := `INSERT INTO user_action_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, t.Id, t.UserID, t.Purpose, t.TokenHash, t.ExpiresAt, t.CreatedAt)
+	return err
+}
+
+func (r *ActionTokenRepository) Consume(ctx context.Context, hash string, purpose user.TokenPurpose, at time.Time) (*user.ActionToken, error) {
+	query := `UPDATE user_action_tokens SET used_at = $1
+              WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+              RETURNING id, user_id, purpose, token_hash, expires_at, created_at, used_at`
+
+	var t user.ActionToken
+	var usedAt time.Time
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, at, hash, purpose).Scan(
+		&t.Id, &t.UserID, &t.Purpose, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &usedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	t.UsedAt = &usedAt
+	return &t, nil
+}
+
+func (r *ActionTokenRepository) InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose user.TokenPurpose, at time.Time) error {
+	query := `UPDATE user_action_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, userID, purpose)
+	return err
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"taskhub/internal/domains/user"
+	baserepo "taskhub/pkg/base/repo"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryUserRepositoryModule = fx.Module(
+	"user-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryUserRepository, fx.As(new(user.UserStore)))),
+)
+
+var ErrEmailTaken = errors.New("email already registered")
+
+// MemoryUserRepository is an in-memory user.UserStore for tests and local
+// development. Like the users table it enforces unique emails.
+type MemoryUserRepository struct {
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*user.User]
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		store: baserepo.NewMemoryRepository(cloneUser),
+	}
+}
+
+func cloneUser(u *user.User) *user.User {
+	c := *u
+	return &c
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, u *user.User) (*user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.FindByEmail(ctx, u.Email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	return r.store.Create(ctx, u)
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	users, err := r.store.FindAll(ctx, func(u *user.User) bool {
+		return u.Email == email
+	})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+
+	return users[0], nil
+}
+
+func (r *MemoryUserRepository) FindById(ctx context.Context, id string) (*user.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.store.FindById(ctx, uid)
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, u *user.User) (*user.User, error) {
+	err := r.store.Modify(ctx, u.Id, func(existing *user.User) error {
+		existing.Name = u.Name
+		existing.Email = u.Email
+		existing.Password = u.Password
+		existing.EmailVerifiedAt = u.EmailVerifiedAt
+		existing.UpdateAt = u.UpdateAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
+
+var _ user.UserStore = (*MemoryUserRepository)(nil)
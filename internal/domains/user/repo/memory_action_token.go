@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"taskhub/internal/domains/user"
+	baserepo "taskhub/pkg/base/repo"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryActionTokenRepositoryModule = fx.Module(
+	"action-token-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryActionTokenRepository, fx.As(new(user.ActionTokenStore)))),
+)
+
+// MemoryActionTokenRepository is an in-memory user.ActionTokenStore for tests
+// and local development.
+type MemoryActionTokenRepository struct {
+	// mu makes Consume's lookup atomic with marking the token used.
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*user.ActionToken]
+}
+
+var _ user.ActionTokenStore = (*MemoryActionTokenRepository)(nil)
+
+func NewMemoryActionTokenRepository() *MemoryActionTokenRepository {
+	return &MemoryActionTokenRepository{
+		store: baserepo.NewMemoryRepository(cloneActionToken),
+	}
+}
+
+func cloneActionToken(t *user.ActionToken) *user.ActionToken {
+	c := *t
+	return &c
+}
+
+func (r *MemoryActionTokenRepository) Create(ctx context.Context, t *user.ActionToken) error {
+	_, err := r.store.Create(ctx, t)
+	return err
+}
+
+func (r *MemoryActionTokenRepository) Consume(ctx context.Context, hash string, purpose user.TokenPurpose, at time.Time) (*user.ActionToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.store.FindAll(ctx, func(t *user.ActionToken) bool {
+		return t.TokenHash == hash && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(at)
+	})
+	if err != nil || len(tokens) == 0 {
+		return nil, err
+	}
+
+	t := tokens[0]
+	t.UsedAt = &at
+	if err := r.markUsed(ctx, t.Id, at); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *MemoryActionTokenRepository) InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose user.TokenPurpose, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.store.FindAll(ctx, func(t *user.ActionToken) bool {
+		return t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		if err := r.markUsed(ctx, t.Id, at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryActionTokenRepository) markUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.store.Modify(ctx, id, func(existing *user.ActionToken) error {
+		existing.UsedAt = &at
+		return nil
+	})
+}
@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"taskhub/internal/domains/user"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var MemoryMFARepositoryModule = fx.Module(
+	"mfa-repo-memory",
+	fx.Provide(fx.Annotate(NewMemoryMFARepository, fx.As(new(user.MFAStore)))),
+)
+
+// memoryRecoveryCode is a recovery code hash held by MemoryMFARepository.
+type memoryRecoveryCode struct {
+	hash string
+	used bool
+}
+
+// MemoryMFARepository is an in-memory user.MFAStore for tests and local
+// development.
+type MemoryMFARepository struct {
+	mu            sync.Mutex
+	enrollments   map[uuid.UUID]user.MFA
+	recoveryCodes map[uuid.UUID][]memoryRecoveryCode
+	challenges    map[uuid.UUID]user.MFAChallenge
+}
+
+var _ user.MFAStore = (*MemoryMFARepository)(nil)
+
+func NewMemoryMFARepository() *MemoryMFARepository {
+	return &MemoryMFARepository{
+		enrollments:   make(map[uuid.UUID]user.MFA),
+		recoveryCodes: make(map[uuid.UUID][]memoryRecoveryCode),
+		challenges:    make(map[uuid.UUID]user.MFAChallenge),
+	}
+}
+
+func (r *MemoryMFARepository) Save(ctx context.Context, m *user.MFA) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enrollments[m.UserID] = *m
+	return nil
+}
+
+func (r *MemoryMFARepository) FindByUserId(ctx context.Context, userID uuid.UUID) (*user.MFA, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.enrollments[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (r *MemoryMFARepository) UseCounter(ctx context.Context, userID uuid.UUID, counter int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.enrollments[userID]
+	if !ok || m.LastCounter >= counter {
+		return false, nil
+	}
+
+	m.LastCounter = counter
+	r.enrollments[userID] = m
+	return true, nil
+}
+
+func (r *MemoryMFARepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.enrollments, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+func (r *MemoryMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make([]memoryRecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = memoryRecoveryCode{hash: hash}
+	}
+	r.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r *MemoryMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := r.recoveryCodes[userID]
+	for i := range codes {
+		if codes[i].hash == hash && !codes[i].used {
+			codes[i].used = true
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *MemoryMFARepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, code := range r.recoveryCodes[userID] {
+		if !code.used {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MemoryMFARepository) CreateChallenge(ctx context.Context, c *user.MFAChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.challenges[c.Id] = *c
+	return nil
+}
+
+func (r *MemoryMFARepository) AttemptChallenge(ctx context.Context, id uuid.UUID, maxAttempts int, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.challenges[id]
+	if !ok || c.UsedAt != nil || !c.ExpiresAt.After(at) || c.Attempts >= maxAttempts {
+		return false, nil
+	}
+
+	c.Attempts++
+	r.challenges[id] = c
+	return true, nil
+}
+
+func (r *MemoryMFARepository) ConsumeChallenge(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.challenges[id]
+	if !ok || c.UsedAt != nil {
+		return false, nil
+	}
+
+	c.UsedAt = &at
+	r.challenges[id] = c
+	return true, nil
+}
+
+func (r *MemoryMFARepository) CountChallengeAttempts(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, c := range r.challenges {
+		if c.UserID == userID && c.UsedAt == nil && !c.CreatedAt.Before(since) {
+			count += c.Attempts
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/user"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryActionTokenRepository_Consume(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryActionTokenRepository()
+	userID := uuid.New()
+	now := time.Now()
+
+	tok := &user.ActionToken{Id: uuid.New(), UserID: userID, Purpose: user.PurposePasswordReset, TokenHash: "live", ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	expired := &user.ActionToken{Id: uuid.New(), UserID: userID, Purpose: user.PurposePasswordReset, TokenHash: "expired", ExpiresAt: now.Add(-time.Second), CreatedAt: now}
+	require.NoError(t, r.Create(ctx, tok))
+	require.NoError(t, r.Create(ctx, expired))
+
+	found, err := r.Consume(ctx, "live", user.PurposeEmailVerification, now)
+	require.NoError(t, err)
+	assert.Nil(t, found, "purpose must match")
+
+	found, err = r.Consume(ctx, "live", user.PurposePasswordReset, now)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, userID, found.UserID)
+	assert.NotNil(t, found.UsedAt)
+
+	found, err = r.Consume(ctx, "live", user.PurposePasswordReset, now)
+	require.NoError(t, err)
+	assert.Nil(t, found, "tokens are single-use")
+
+	found, err = r.Consume(ctx, "expired", user.PurposePasswordReset, now)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestMemoryActionTokenRepository_InvalidateByUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryActionTokenRepository()
+	userID := uuid.New()
+	now := time.Now()
+
+	reset := &user.ActionToken{Id: uuid.New(), UserID: userID, Purpose: user.PurposePasswordReset, TokenHash: "reset", ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	verify := &user.ActionToken{Id: uuid.New(), UserID: userID, Purpose: user.PurposeEmailVerification, TokenHash: "verify", ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	require.NoError(t, r.Create(ctx, reset))
+	require.NoError(t, r.Create(ctx, verify))
+
+	require.NoError(t, r.InvalidateByUser(ctx, userID, user.PurposePasswordReset, now))
+
+	found, err := r.Consume(ctx, "reset", user.PurposePasswordReset, now)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	found, err = r.Consume(ctx, "verify", user.PurposeEmailVerification, now)
+	require.NoError(t, err)
+	assert.NotNil(t, found)
+}
+
+func TestMemoryMFARepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryMFARepository()
+	userID := uuid.New()
+
+	found, err := r.FindByUserId(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	assert.False(t, found.Enabled())
+
+	now := time.Now()
+	require.NoError(t, r.Save(ctx, &user.MFA{UserID: userID, Secret: "SECRET", ConfirmedAt: &now, CreatedAt: now}))
+	found, err = r.FindByUserId(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, found.Enabled())
+
+	used, err := r.UseCounter(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = r.UseCounter(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.False(t, used, "a time step is accepted once")
+
+	require.NoError(t, r.ReplaceRecoveryCodes(ctx, userID, []string{"a", "b"}))
+	used, err = r.UseRecoveryCode(ctx, userID, "a", now)
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = r.UseRecoveryCode(ctx, userID, "a", now)
+	require.NoError(t, err)
+	assert.False(t, used)
+	count, err := r.CountRecoveryCodes(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.NoError(t, r.Delete(ctx, userID))
+	found, err = r.FindByUserId(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	count, err = r.CountRecoveryCodes(ctx, userID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestMemoryMFARepository_Challenges(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryMFARepository()
+	userID := uuid.New()
+	now := time.Now()
+
+	c := &user.MFAChallenge{Id: uuid.New(), UserID: userID, ExpiresAt: now.Add(time.Minute), CreatedAt: now}
+	require.NoError(t, r.CreateChallenge(ctx, c))
+
+	for i := 0; i < 2; i++ {
+		ok, err := r.AttemptChallenge(ctx, c.Id, 2, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := r.AttemptChallenge(ctx, c.Id, 2, now)
+	require.NoError(t, err)
+	assert.False(t, ok, "attempts stop at the limit")
+
+	count, err := r.CountChallengeAttempts(ctx, userID, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	ok, err = r.ConsumeChallenge(ctx, c.Id, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = r.ConsumeChallenge(ctx, c.Id, now)
+	require.NoError(t, err)
+	assert.False(t, ok, "a challenge is used once")
+
+	count, err = r.CountChallengeAttempts(ctx, userID, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, count, "used challenges do not count")
+
+	expired := &user.MFAChallenge{Id: uuid.New(), UserID: userID, ExpiresAt: now, CreatedAt: now}
+	require.NoError(t, r.CreateChallenge(ctx, expired))
+	ok, err = r.AttemptChallenge(ctx, expired.Id, 2, now)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/internal/domains/user"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var MFARepositoryModule = fx.Module(
+	"mfa-repo",
+	fx.Provide(fx.Annotate(NewMFARepository, fx.As(new(user.MFAStore)))),
+)
+
+type MFARepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ user.MFAStore = (*MFARepository)(nil)
+
+func NewMFARepository(config *config.Config, logger *logger.Logger) *MFARepository {
+	conn := db.NewDB(config).GetConnection()
+	return &MFARepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *MFARepository) Save(ctx context.Context, m *user.MFA) error {
+	query := `INSERT INTO user_mfa (user_id, secret, confirmed_at, last_counter, created_at)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (user_id) DO UPDATE
+              SET secret = EXCLUDED.secret, confirmed_at = EXCLUDED.confirmed_at,
+                  last_counter = EXCLUDED.last_counter, created_at = EXCLUDED.created_at`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, m.UserID, m.Secret, m.ConfirmedAt, m.LastCounter, m.CreatedAt)
+	return err
+}
+
+func (r *MFARepository) FindByUserId(ctx context.Context, userID uuid.UUID) (*user.MFA, error) {
+	query := `SELECT user_id, secret, confirmed_at, last_counter, created_at FROM user_mfa WHERE user_id = $1`
+
+	var m user.MFA
+	var confirmedAt sql.NullTime
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, userID).Scan(&m.UserID, &m.Secret, &confirmedAt, &m.LastCounter, &m.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		m.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return &m, nil
+}
+
+func (r *MFARepository) UseCounter(ctx context.Context, userID uuid.UUID, counter int64) (bool, error) {
+	query := `UPDATE user_mfa SET last_counter = $1 WHERE user_id = $2 AND last_counter < $1`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, counter, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := `WITH codes AS (DELETE FROM user_recovery_codes WHERE user_id = $1)
+              DELETE FROM user_mfa WHERE user_id = $1`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	query := `WITH old AS (DELETE FROM user_recovery_codes WHERE user_id = $1)
+              INSERT INTO user_recovery_codes (id, user_id, code_hash)
+              SELECT id, $1, code_hash FROM unnest($2::uuid[], $3::text[]) AS codes (id, code_hash)`
+
+	ids := make([]string, len(hashes))
+	for i := range hashes {
+		ids[i] = uuid.New().String()
+	}
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, userID, pq.Array(ids), pq.Array(hashes))
+	return err
+}
+
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, at time.Time) (bool, error) {
+	query := `UPDATE user_recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, userID, hash)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *MFARepository) CreateChallenge(ctx context.Context, c *user.MFAChallenge) error {
+	query := `INSERT INTO mfa_challenges (id, user_id, attempts, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, c.Id, c.UserID, c.Attempts, c.ExpiresAt, c.CreatedAt)
+	return err
+}
+
+func (r *MFARepository) AttemptChallenge(ctx context.Context, id uuid.UUID, maxAttempts int, at time.Time) (bool, error) {
+	query := `UPDATE mfa_challenges SET attempts = attempts + 1
+              WHERE id = $1 AND used_at IS NULL AND expires_at > $2 AND attempts < $3`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, id, at, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) ConsumeChallenge(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	query := `UPDATE mfa_challenges SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, at, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *MFARepository) CountChallengeAttempts(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	query := `SELECT COALESCE(SUM(attempts), 0) FROM mfa_challenges
+              WHERE user_id = $1 AND used_at IS NULL AND created_at >= $2`
+
+	var count int
+	err := db.Conn(ctx, r.conn).QueryRowContext(ctx, query, userID, since).Scan(&count)
+	return count, err
+}
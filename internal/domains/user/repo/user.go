@@ -14,7 +14,7 @@ import (
 
 var UserRepositoryModule = fx.Module(
 	"user-repo",
-	fx.Provide(NewUserRepository),
+	fx.Provide(fx.Annotate(NewUserRepository, fx.As(new(user.UserStore)))),
 )
 
 type UserRepository struct {
@@ -22,6 +22,8 @@ type UserRepository struct {
 	logger *logger.Logger
 }
 
+var _ user.UserStore = (*UserRepository)(nil)
+
 func NewUserRepository(config *config.Config, logger *logger.Logger) *UserRepository {
 	conn := db.NewDB(config).GetConnection()
 	return &UserRepository{
@@ -43,30 +45,26 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) (*user.User,
 }
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
-	query := `SELECT id, name, email, password, created_at FROM users WHERE email = $1`
-
-	var u user.User
-	err := r.conn.QueryRowContext(ctx, query, email).Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.CreatedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
+	query := `SELECT id, name, email, password, created_at, email_verified_at FROM users WHERE email = $1`
 
-	return &u, nil
+	return scanUser(r.conn.QueryRowContext(ctx, query, email))
 }
 
 func (r *UserRepository) FindById(ctx context.Context, id string) (*user.User, error) {
-	query := `SELECT id, name, email, password, created_at FROM users WHERE id = $1`
+	query := `SELECT id, name, email, password, created_at, email_verified_at FROM users WHERE id = $1`
 
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return nil, err
 	}
 
+	return scanUser(r.conn.QueryRowContext(ctx, query, uid))
+}
+
+func scanUser(row *sql.Row) (*user.User, error) {
 	var u user.User
-	err = r.conn.QueryRowContext(ctx, query, uid).Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.CreatedAt)
+	var verifiedAt sql.NullTime
+	err := row.Scan(&u.Id, &u.Name, &u.Email, &u.Password, &u.CreatedAt, &verifiedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -74,13 +72,17 @@ func (r *UserRepository) FindById(ctx context.Context, id string) (*user.User, e
 		return nil, err
 	}
 
+	if verifiedAt.Valid {
+		u.EmailVerifiedAt = &verifiedAt.Time
+	}
+
 	return &u, nil
 }
 
 func (r *UserRepository) Update(ctx context.Context, u *user.User) (*user.User, error) {
-	query := `UPDATE users SET name = $1, email = $2, updated_at = $3 WHERE id = $4`
+	query := `UPDATE users SET name = $1, email = $2, password = $3, email_verified_at = $4, updated_at = $5 WHERE id = $6`
 
-	_, err := r.conn.ExecContext(ctx, query, u.Name, u.Email, u.UpdateAt, u.Id)
+	_, err := r.conn.ExecContext(ctx, query, u.Name, u.Email, u.Password, u.EmailVerifiedAt, u.UpdateAt, u.Id)
 	if err != nil {
 		return nil, err
 	}
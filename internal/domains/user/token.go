@@ -0,0 +1,44 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPurpose is what an ActionToken lets its holder do.
+type TokenPurpose string
+
+const (
+	PurposePasswordReset     TokenPurpose = "password_reset"
+	PurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// ActionToken is a single-use token mailed to a user to prove they own their
+// email address. Only a hash of the token is stored.
+type ActionToken struct {
+	Id        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Purpose   TokenPurpose `json:"purpose"`
+	TokenHash string       `json:"-"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+}
+
+func (t *ActionToken) GetId() uuid.UUID {
+	return t.Id
+}
+
+// ActionTokenStore persists action tokens.
+type ActionTokenStore interface {
+	Create(ctx context.Context, t *ActionToken) error
+	// Consume marks the unused, unexpired token with hash and purpose used
+	// at at and returns it, or nil when there is none. Of two concurrent
+	// calls with the same token, only one gets it.
+	Consume(ctx context.Context, hash string, purpose TokenPurpose, at time.Time) (*ActionToken, error)
+	// InvalidateByUser marks the user's unused tokens for purpose used, so
+	// only a token issued afterwards works.
+	InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose TokenPurpose, at time.Time) error
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 key of the RFC 6238 test vectors.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; these are their last 6 digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfc6238Secret, TOTPCounter(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "at %d", tt.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	counter, ok := ValidateTOTP(rfc6238Secret, "050471", now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPCounter(now), counter)
+
+	_, ok = ValidateTOTP(rfc6238Secret, "050471", now.Add(TOTPPeriod))
+	assert.True(t, ok, "one step of drift is allowed")
+
+	_, ok = ValidateTOTP(rfc6238Secret, "050471", now.Add(3*TOTPPeriod))
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(rfc6238Secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = TOTPCode(secret, 1)
+	assert.NoError(t, err)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("TaskHub", "john@example.com", "ABC"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/TaskHub:john@example.com", uri.Path)
+	assert.Equal(t, "ABC", uri.Query().Get("secret"))
+	assert.Equal(t, "TaskHub", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
@@ -1,7 +1,9 @@
 package user
 
 import (
+	"context"
 	"taskhub/pkg/base/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,8 +13,20 @@ type User struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// EmailVerifiedAt is set once the user proves they own Email.
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
 }
 
 func (u *User) GetId() uuid.UUID {
 	return u.Id
 }
+
+// UserStore is the persistence contract the auth and user services depend on.
+type UserStore interface {
+	Create(ctx context.Context, u *User) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindById(ctx context.Context, id string) (*User, error)
+	// Update saves u's name, email, password hash and verification time.
+	Update(ctx context.Context, u *User) (*User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/view"
+	baserepo "taskhub/pkg/base/repo"
+
+	"github.com/google/uuid"
+)
+
+// MemoryViewRepository is an in-memory view.ViewStore for tests and local
+// development.
+type MemoryViewRepository struct {
+	// mu makes the name uniqueness check atomic with the write.
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*view.View]
+}
+
+var _ view.ViewStore = (*MemoryViewRepository)(nil)
+
+func NewMemoryViewRepository() *MemoryViewRepository {
+	return &MemoryViewRepository{
+		store: baserepo.NewMemoryRepository(cloneView),
+	}
+}
+
+func cloneView(v *view.View) *view.View {
+	c := *v
+	return &c
+}
+
+func (r *MemoryViewRepository) nameTaken(ctx context.Context, v *view.View) (bool, error) {
+	clashes, err := r.store.FindAll(ctx, func(existing *view.View) bool {
+		return existing.UserID == v.UserID && existing.Name == v.Name && existing.Id != v.Id
+	})
+	return len(clashes) > 0, err
+}
+
+func (r *MemoryViewRepository) Create(ctx context.Context, v *view.View) (*view.View, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken, err := r.nameTaken(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, view.ErrNameTaken
+	}
+
+	return r.store.Create(ctx, v)
+}
+
+func (r *MemoryViewRepository) FindById(ctx context.Context, id uuid.UUID) (*view.View, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryViewRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*view.View, error) {
+	views, err := r.store.FindAll(ctx, func(v *view.View) bool {
+		return v.UserID == userID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	return views, nil
+}
+
+func (r *MemoryViewRepository) UpdateById(ctx context.Context, id uuid.UUID, v *view.View) (*view.View, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.store.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	existing.Name = v.Name
+	taken, err := r.nameTaken(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, view.ErrNameTaken
+	}
+
+	err = r.store.Modify(ctx, id, func(existing *view.View) error {
+		existing.Name = v.Name
+		existing.Query = v.Query
+		existing.UpdateAt = v.UpdateAt
+		existing.UpdateBy = v.UpdateBy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v.Id = id
+	return v, nil
+}
+
+func (r *MemoryViewRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
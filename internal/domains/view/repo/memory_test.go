@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/view"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newView(userID uuid.UUID, name string) *view.View {
+	return &view.View{
+		BaseEntity: entity.BaseEntity{Id: uuid.New(), CreatedAt: time.Now(), CreatedBy: userID},
+		UserID:     userID,
+		Name:       name,
+		Query:      "status:todo",
+	}
+}
+
+func TestMemoryViewRepository_NamesAreUniquePerUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryViewRepository()
+	userID := uuid.New()
+
+	_, err := r.Create(ctx, newView(userID, "Today"))
+	assert.NoError(t, err)
+	_, err = r.Create(ctx, newView(userID, "Today"))
+	assert.ErrorIs(t, err, view.ErrNameTaken)
+	_, err = r.Create(ctx, newView(uuid.New(), "Today"))
+	assert.NoError(t, err)
+
+	later, _ := r.Create(ctx, newView(userID, "Later"))
+	_, err = r.UpdateById(ctx, later.Id, &view.View{Name: "Today"})
+	assert.ErrorIs(t, err, view.ErrNameTaken)
+	_, err = r.UpdateById(ctx, later.Id, &view.View{Name: "Later", Query: "priority:high"})
+	assert.NoError(t, err)
+
+	views, err := r.FindByUserId(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.Equal(t, "Later", views[0].Name)
+	assert.Equal(t, "priority:high", views[0].Query)
+
+	assert.NoError(t, r.DeleteById(ctx, later.Id))
+	assert.Equal(t, sql.ErrNoRows, r.DeleteById(ctx, later.Id))
+}
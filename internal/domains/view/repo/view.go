@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskhub/config"
+	"taskhub/internal/domains/view"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var ViewRepositoryModule = fx.Module(
+	"view-repo",
+	fx.Provide(fx.Annotate(NewViewRepository, fx.As(new(view.ViewStore)))),
+)
+
+type ViewRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ view.ViewStore = (*ViewRepository)(nil)
+
+func NewViewRepository(config *config.Config, logger *logger.Logger) *ViewRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &ViewRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// uniqueViolation maps the (user_id, name) constraint to view.ErrNameTaken.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return view.ErrNameTaken
+	}
+	return err
+}
+
+func (r *ViewRepository) Create(ctx context.Context, v *view.View) (*view.View, error) {
+	query := `INSERT INTO task_views (id, user_id, name, query, created_at, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, v.Id, v.UserID, v.Name, v.Query, v.CreatedAt, v.CreatedBy)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return v, nil
+}
+
+func (r *ViewRepository) FindById(ctx context.Context, id uuid.UUID) (*view.View, error) {
+	query := `SELECT id, user_id, name, query, created_at, created_by, updated_at
+              FROM task_views WHERE id = $1`
+
+	v, err := scanView(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *ViewRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*view.View, error) {
+	query := `SELECT id, user_id, name, query, created_at, created_by, updated_at
+              FROM task_views WHERE user_id = $1 ORDER BY name`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*view.View
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+
+	return views, rows.Err()
+}
+
+func scanView(row interface{ Scan(...any) error }) (*view.View, error) {
+	var v view.View
+	var updatedAt sql.NullTime
+
+	if err := row.Scan(&v.Id, &v.UserID, &v.Name, &v.Query, &v.CreatedAt, &v.CreatedBy, &updatedAt); err != nil {
+		return nil, err
+	}
+	if updatedAt.Valid {
+		v.UpdateAt = &updatedAt.Time
+	}
+
+	return &v, nil
+}
+
+func (r *ViewRepository) UpdateById(ctx context.Context, id uuid.UUID, v *view.View) (*view.View, error) {
+	query := `UPDATE task_views SET name = $1, query = $2, updated_at = $3, updated_by = $4 WHERE id = $5`
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, v.Name, v.Query, v.UpdateAt, v.UpdateBy, id)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	v.Id = id
+	return v, nil
+}
+
+func (r *ViewRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM task_views WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
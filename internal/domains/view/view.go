@@ -0,0 +1,31 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+)
+
+var ErrNameTaken = errors.New("view name already taken")
+
+// View is a named task query saved as a smart list. Query uses the syntax
+// parsed by task.ParseQuery.
+type View struct {
+	entity.BaseEntity
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Query  string    `json:"query"`
+}
+
+// ViewStore persists saved views. Names are unique per user; Create and
+// UpdateById report a clash as ErrNameTaken.
+type ViewStore interface {
+	Create(ctx context.Context, v *View) (*View, error)
+	FindById(ctx context.Context, id uuid.UUID) (*View, error)
+	// FindByUserId returns the user's views ordered by name.
+	FindByUserId(ctx context.Context, userID uuid.UUID) ([]*View, error)
+	UpdateById(ctx context.Context, id uuid.UUID, v *View) (*View, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+}
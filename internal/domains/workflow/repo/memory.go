@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"taskhub/internal/domains/workflow"
+	baserepo "taskhub/pkg/base/repo"
+
+	"github.com/google/uuid"
+)
+
+// MemoryWorkflowRepository is an in-memory workflow.WorkflowStore for tests
+// and local development. Unlike WorkflowRepository, it keeps a project's
+// workflow when the project is deleted from a memory project repository.
+type MemoryWorkflowRepository struct {
+	// mu makes the scope uniqueness check atomic with the write.
+	mu    sync.Mutex
+	store *baserepo.MemoryRepository[*workflow.Workflow]
+}
+
+var _ workflow.WorkflowStore = (*MemoryWorkflowRepository)(nil)
+
+func NewMemoryWorkflowRepository() *MemoryWorkflowRepository {
+	return &MemoryWorkflowRepository{
+		store: baserepo.NewMemoryRepository(cloneWorkflow),
+	}
+}
+
+func cloneWorkflow(w *workflow.Workflow) *workflow.Workflow {
+	c := *w
+	c.States = slices.Clone(w.States)
+	c.Transitions = slices.Clone(w.Transitions)
+	return &c
+}
+
+func sameScope(w *workflow.Workflow, userID uuid.UUID, projectID *uuid.UUID) bool {
+	if w.UserID != userID || (w.ProjectID == nil) != (projectID == nil) {
+		return false
+	}
+	return projectID == nil || *w.ProjectID == *projectID
+}
+
+func (r *MemoryWorkflowRepository) Create(ctx context.Context, w *workflow.Workflow) (*workflow.Workflow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.FindByScope(ctx, w.UserID, w.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, workflow.ErrScopeTaken
+	}
+
+	return r.store.Create(ctx, w)
+}
+
+func (r *MemoryWorkflowRepository) FindById(ctx context.Context, id uuid.UUID) (*workflow.Workflow, error) {
+	return r.store.FindById(ctx, id)
+}
+
+func (r *MemoryWorkflowRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*workflow.Workflow, error) {
+	workflows, err := r.store.FindAll(ctx, func(w *workflow.Workflow) bool {
+		return w.UserID == userID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(workflows, func(i, j int) bool {
+		a, b := workflows[i], workflows[j]
+		if (a.ProjectID == nil) != (b.ProjectID == nil) {
+			return a.ProjectID == nil
+		}
+		return a.Name < b.Name
+	})
+
+	return workflows, nil
+}
+
+func (r *MemoryWorkflowRepository) FindByScope(ctx context.Context, userID uuid.UUID, projectID *uuid.UUID) (*workflow.Workflow, error) {
+	workflows, err := r.store.FindAll(ctx, func(w *workflow.Workflow) bool {
+		return sameScope(w, userID, projectID)
+	})
+	if err != nil || len(workflows) == 0 {
+		return nil, err
+	}
+	return workflows[0], nil
+}
+
+func (r *MemoryWorkflowRepository) UpdateById(ctx context.Context, id uuid.UUID, w *workflow.Workflow) (*workflow.Workflow, error) {
+	err := r.store.Modify(ctx, id, func(existing *workflow.Workflow) error {
+		existing.Name = w.Name
+		existing.States = slices.Clone(w.States)
+		existing.Transitions = slices.Clone(w.Transitions)
+		existing.UpdateAt = w.UpdateAt
+		existing.UpdateBy = w.UpdateBy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w.Id = id
+	return w, nil
+}
+
+func (r *MemoryWorkflowRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return r.store.Delete(ctx, id)
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"taskhub/internal/domains/workflow"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWorkflow(userID uuid.UUID, projectID *uuid.UUID, name string) *workflow.Workflow {
+	w := workflow.DefaultWorkflow()
+	w.BaseEntity = entity.BaseEntity{Id: uuid.New(), CreatedAt: time.Now(), CreatedBy: userID}
+	w.UserID = userID
+	w.ProjectID = projectID
+	w.Name = name
+	return w
+}
+
+func TestMemoryWorkflowRepository_OnePerScope(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryWorkflowRepository()
+	userID := uuid.New()
+	projectID := uuid.New()
+
+	_, err := r.Create(ctx, newWorkflow(userID, nil, "Mine"))
+	assert.NoError(t, err)
+	_, err = r.Create(ctx, newWorkflow(userID, nil, "Another"))
+	assert.ErrorIs(t, err, workflow.ErrScopeTaken)
+	_, err = r.Create(ctx, newWorkflow(userID, &projectID, "Launch"))
+	assert.NoError(t, err)
+	_, err = r.Create(ctx, newWorkflow(userID, &projectID, "Launch again"))
+	assert.ErrorIs(t, err, workflow.ErrScopeTaken)
+	_, err = r.Create(ctx, newWorkflow(uuid.New(), nil, "Theirs"))
+	assert.NoError(t, err)
+
+	own, err := r.FindByScope(ctx, userID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", own.Name)
+
+	launch, err := r.FindByScope(ctx, userID, &projectID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Launch", launch.Name)
+
+	otherProject := uuid.New()
+	missing, err := r.FindByScope(ctx, userID, &otherProject)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	workflows, err := r.FindByUserId(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Mine", "Launch"}, []string{workflows[0].Name, workflows[1].Name})
+
+	assert.NoError(t, r.DeleteById(ctx, own.Id))
+	assert.Equal(t, sql.ErrNoRows, r.DeleteById(ctx, own.Id))
+	_, err = r.Create(ctx, newWorkflow(userID, nil, "Mine again"))
+	assert.NoError(t, err)
+}
+
+func TestMemoryWorkflowRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryWorkflowRepository()
+	created, _ := r.Create(ctx, newWorkflow(uuid.New(), nil, "Mine"))
+
+	changed := *created
+	changed.Name = "Renamed"
+	changed.Transitions = created.Transitions[:1]
+	_, err := r.UpdateById(ctx, created.Id, &changed)
+	assert.NoError(t, err)
+
+	found, err := r.FindById(ctx, created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", found.Name)
+	assert.Len(t, found.Transitions, 1)
+
+	_, err = r.UpdateById(ctx, uuid.New(), &changed)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
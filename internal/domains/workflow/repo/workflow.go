@@ -0,0 +1,182 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"taskhub/config"
+	"taskhub/internal/domains/workflow"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/fx"
+)
+
+var WorkflowRepositoryModule = fx.Module(
+	"workflow-repo",
+	fx.Provide(fx.Annotate(NewWorkflowRepository, fx.As(new(workflow.WorkflowStore)))),
+)
+
+type WorkflowRepository struct {
+	conn   *sql.DB
+	logger *logger.Logger
+}
+
+var _ workflow.WorkflowStore = (*WorkflowRepository)(nil)
+
+func NewWorkflowRepository(config *config.Config, logger *logger.Logger) *WorkflowRepository {
+	conn := db.NewDB(config).GetConnection()
+	return &WorkflowRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// uniqueViolation maps the one-workflow-per-scope indexes to
+// workflow.ErrScopeTaken.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return workflow.ErrScopeTaken
+	}
+	return err
+}
+
+const workflowColumns = `id, user_id, project_id, name, states, transitions, created_at, created_by, updated_at`
+
+func (r *WorkflowRepository) Create(ctx context.Context, w *workflow.Workflow) (*workflow.Workflow, error) {
+	query := `INSERT INTO workflows (id, user_id, project_id, name, states, transitions, created_at, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	states, transitions, err := marshalWorkflow(w)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Conn(ctx, r.conn).ExecContext(ctx, query, w.Id, w.UserID, w.ProjectID, w.Name, states, transitions, w.CreatedAt, w.CreatedBy)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return w, nil
+}
+
+func (r *WorkflowRepository) FindById(ctx context.Context, id uuid.UUID) (*workflow.Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflows WHERE id = $1`
+
+	w, err := scanWorkflow(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+func (r *WorkflowRepository) FindByUserId(ctx context.Context, userID uuid.UUID) ([]*workflow.Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflows WHERE user_id = $1
+              ORDER BY project_id IS NOT NULL, name`
+
+	rows, err := db.Conn(ctx, r.conn).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workflows []*workflow.Workflow
+	for rows.Next() {
+		w, err := scanWorkflow(rows)
+		if err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, w)
+	}
+
+	return workflows, rows.Err()
+}
+
+func (r *WorkflowRepository) FindByScope(ctx context.Context, userID uuid.UUID, projectID *uuid.UUID) (*workflow.Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflows WHERE user_id = $1 AND project_id IS NOT DISTINCT FROM $2`
+
+	w, err := scanWorkflow(db.Conn(ctx, r.conn).QueryRowContext(ctx, query, userID, projectID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+func marshalWorkflow(w *workflow.Workflow) (states, transitions []byte, err error) {
+	if states, err = json.Marshal(w.States); err != nil {
+		return nil, nil, err
+	}
+	if w.Transitions == nil {
+		return states, []byte("[]"), nil
+	}
+	transitions, err = json.Marshal(w.Transitions)
+	return states, transitions, err
+}
+
+func scanWorkflow(row interface{ Scan(...any) error }) (*workflow.Workflow, error) {
+	var w workflow.Workflow
+	var projectID sql.NullString
+	var states, transitions []byte
+	var updatedAt sql.NullTime
+
+	err := row.Scan(&w.Id, &w.UserID, &projectID, &w.Name, &states, &transitions, &w.CreatedAt, &w.CreatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if projectID.Valid {
+		pid, _ := uuid.Parse(projectID.String)
+		w.ProjectID = &pid
+	}
+	if updatedAt.Valid {
+		w.UpdateAt = &updatedAt.Time
+	}
+	if err := json.Unmarshal(states, &w.States); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(transitions, &w.Transitions); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func (r *WorkflowRepository) UpdateById(ctx context.Context, id uuid.UUID, w *workflow.Workflow) (*workflow.Workflow, error) {
+	query := `UPDATE workflows SET name = $1, states = $2, transitions = $3, updated_at = $4, updated_by = $5
+              WHERE id = $6`
+
+	states, transitions, err := marshalWorkflow(w)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, query, w.Name, states, transitions, w.UpdateAt, w.UpdateBy, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	w.Id = id
+	return w, nil
+}
+
+func (r *WorkflowRepository) DeleteById(ctx context.Context, id uuid.UUID) error {
+	result, err := db.Conn(ctx, r.conn).ExecContext(ctx, `DELETE FROM workflows WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/base/entity"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrIllegalTransition = errors.New("illegal status transition")
+	ErrUnknownStatus     = errors.New("unknown status")
+	ErrInvalidWorkflow   = errors.New("invalid workflow")
+	ErrScopeTaken        = errors.New("scope already has a workflow")
+)
+
+const maxNameLength = 100
+
+// State is a status of a workflow.
+type State struct {
+	Name     task.TaskStatus     `json:"name"`
+	Category task.StatusCategory `json:"category"`
+}
+
+// Transition allows tasks to move from one state to another.
+type Transition struct {
+	From task.TaskStatus `json:"from"`
+	To   task.TaskStatus `json:"to"`
+}
+
+// Workflow defines the statuses a user's tasks can be in and the moves
+// between them. A project's workflow applies to the tasks in it and a
+// user's workflow, with no ProjectID, to the rest; without either, tasks
+// follow DefaultWorkflow.
+type Workflow struct {
+	entity.BaseEntity
+	UserID    uuid.UUID  `json:"user_id"`
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
+	Name      string     `json:"name"`
+	// States lists the statuses in order; new tasks start in the first.
+	States      []State      `json:"states"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// DefaultWorkflow is todo, in_progress and done, with every move allowed.
+// Tasks follow it unless their project or user defines another.
+func DefaultWorkflow() *Workflow {
+	states := []State{
+		{Name: task.StatusTodo, Category: task.CategoryOpen},
+		{Name: task.StatusInProgress, Category: task.CategoryActive},
+		{Name: task.StatusDone, Category: task.CategoryClosed},
+	}
+
+	var transitions []Transition
+	for _, from := range states {
+		for _, to := range states {
+			if from != to {
+				transitions = append(transitions, Transition{From: from.Name, To: to.Name})
+			}
+		}
+	}
+
+	return &Workflow{Name: "Default", States: states, Transitions: transitions}
+}
+
+// Validate checks that the workflow is usable: a name, uniquely named
+// states with known categories, starting with an open state and including a
+// closed one, and transitions between distinct states of the workflow.
+// Errors wrap ErrInvalidWorkflow.
+func (w *Workflow) Validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" || len(w.Name) > maxNameLength {
+		return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidWorkflow, maxNameLength)
+	}
+	if len(w.States) == 0 {
+		return fmt.Errorf("%w: no states", ErrInvalidWorkflow)
+	}
+
+	seen := map[task.TaskStatus]bool{}
+	closed := false
+	for _, s := range w.States {
+		if !s.Name.Valid() {
+			return fmt.Errorf("%w: state %q must be lowercase letters, digits and underscores", ErrInvalidWorkflow, s.Name)
+		}
+		// Queries take categories in place of statuses.
+		if task.StatusCategory(s.Name).Valid() {
+			return fmt.Errorf("%w: state %q is named after a category", ErrInvalidWorkflow, s.Name)
+		}
+		if !s.Category.Valid() {
+			return fmt.Errorf("%w: state %q has unknown category %q", ErrInvalidWorkflow, s.Name, s.Category)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("%w: state %q given twice", ErrInvalidWorkflow, s.Name)
+		}
+		seen[s.Name] = true
+		closed = closed || s.Category == task.CategoryClosed
+	}
+	if w.States[0].Category != task.CategoryOpen {
+		return fmt.Errorf("%w: the first state must be open", ErrInvalidWorkflow)
+	}
+	if !closed {
+		return fmt.Errorf("%w: no closed state", ErrInvalidWorkflow)
+	}
+
+	for i, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] {
+			return fmt.Errorf("%w: transition %s -> %s uses an unknown state", ErrInvalidWorkflow, t.From, t.To)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("%w: transition %s -> %s goes nowhere", ErrInvalidWorkflow, t.From, t.To)
+		}
+		if slices.Contains(w.Transitions[:i], t) {
+			return fmt.Errorf("%w: transition %s -> %s given twice", ErrInvalidWorkflow, t.From, t.To)
+		}
+	}
+
+	return nil
+}
+
+// State returns the state called name.
+func (w *Workflow) State(name task.TaskStatus) (State, bool) {
+	for _, s := range w.States {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return State{}, false
+}
+
+// Initial returns the state new tasks start in.
+func (w *Workflow) Initial() State {
+	return w.States[0]
+}
+
+// Allowed returns the states a task in from can move to, in workflow order.
+// A status the workflow does not have, as left behind when a workflow
+// changes, can move to any state.
+func (w *Workflow) Allowed(from task.TaskStatus) []task.TaskStatus {
+	_, known := w.State(from)
+
+	var allowed []task.TaskStatus
+	for _, s := range w.States {
+		if s.Name == from {
+			continue
+		}
+		if !known || slices.Contains(w.Transitions, Transition{From: from, To: s.Name}) {
+			allowed = append(allowed, s.Name)
+		}
+	}
+	return allowed
+}
+
+// Transition returns the state a task in from moves to when set to to. It
+// fails with ErrUnknownStatus when the workflow has no such state and with a
+// TransitionError when the move is not allowed. Staying put is always
+// allowed.
+func (w *Workflow) Transition(from, to task.TaskStatus) (State, error) {
+	state, ok := w.State(to)
+	if !ok {
+		return State{}, fmt.Errorf("%w %q", ErrUnknownStatus, to)
+	}
+	if from != to && !slices.Contains(w.Allowed(from), to) {
+		return State{}, &TransitionError{From: from, To: to, Allowed: w.Allowed(from)}
+	}
+	return state, nil
+}
+
+// Target returns the first state of category, in workflow order, that a
+// task in from can move to. A task already in such a state stays there. It
+// fails with a TransitionError when none is allowed.
+func (w *Workflow) Target(from task.TaskStatus, category task.StatusCategory) (State, error) {
+	if s, ok := w.State(from); ok && s.Category == category {
+		return s, nil
+	}
+
+	for _, name := range w.Allowed(from) {
+		if s, _ := w.State(name); s.Category == category {
+			return s, nil
+		}
+	}
+	return State{}, &TransitionError{From: from, Category: category, Allowed: w.Allowed(from)}
+}
+
+// TransitionError is an illegal move, to a state or to any state of a
+// category. It wraps ErrIllegalTransition.
+type TransitionError struct {
+	From     task.TaskStatus
+	To       task.TaskStatus
+	Category task.StatusCategory
+	// Allowed lists the states From can move to.
+	Allowed []task.TaskStatus
+}
+
+func (e *TransitionError) Error() string {
+	to := string(e.To)
+	if to == "" {
+		to = "any " + string(e.Category) + " status"
+	}
+
+	allowed := "none"
+	if len(e.Allowed) > 0 {
+		names := make([]string, len(e.Allowed))
+		for i, s := range e.Allowed {
+			names[i] = string(s)
+		}
+		allowed = strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%v: %s cannot move to %s (allowed: %s)", ErrIllegalTransition, e.From, to, allowed)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// WorkflowStore persists workflows. A user has at most one workflow of
+// their own and one per project; Create reports a second as ErrScopeTaken.
+// Deleting a project deletes its workflow.
+type WorkflowStore interface {
+	Create(ctx context.Context, w *Workflow) (*Workflow, error)
+	FindById(ctx context.Context, id uuid.UUID) (*Workflow, error)
+	// FindByUserId returns the user's workflows, their own first and the
+	// projects' ones after it by name.
+	FindByUserId(ctx context.Context, userID uuid.UUID) ([]*Workflow, error)
+	// FindByScope returns the workflow of a project, or the user's own when
+	// projectID is nil. It returns nil when there is none.
+	FindByScope(ctx context.Context, userID uuid.UUID, projectID *uuid.UUID) (*Workflow, error)
+	UpdateById(ctx context.Context, id uuid.UUID, w *Workflow) (*Workflow, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+}
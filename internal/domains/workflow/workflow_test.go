@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"testing"
+
+	"taskhub/internal/domains/task"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func review() *Workflow {
+	return &Workflow{
+		Name: "Review",
+		States: []State{
+			{Name: "backlog", Category: task.CategoryOpen},
+			{Name: "doing", Category: task.CategoryActive},
+			{Name: "review", Category: task.CategoryActive},
+			{Name: "shipped", Category: task.CategoryClosed},
+		},
+		Transitions: []Transition{
+			{From: "backlog", To: "doing"},
+			{From: "doing", To: "review"},
+			{From: "review", To: "doing"},
+			{From: "review", To: "shipped"},
+		},
+	}
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+	w := DefaultWorkflow()
+	assert.NoError(t, w.Validate())
+	assert.Equal(t, task.StatusTodo, w.Initial().Name)
+
+	for _, s := range w.States {
+		assert.Equal(t, task.DefaultCategory(s.Name), s.Category)
+		assert.Len(t, w.Allowed(s.Name), 2)
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	assert.NoError(t, review().Validate())
+
+	for name, change := range map[string]func(w *Workflow){
+		"no name":         func(w *Workflow) { w.Name = " " },
+		"no states":       func(w *Workflow) { w.States = nil },
+		"bad state name":  func(w *Workflow) { w.States[1].Name = "In Review" },
+		"category name":   func(w *Workflow) { w.States[1].Name = "closed" },
+		"bad category":    func(w *Workflow) { w.States[1].Category = "blocked" },
+		"duplicate state": func(w *Workflow) { w.States[2].Name = "doing" },
+		"starts active":   func(w *Workflow) { w.States[0].Category = task.CategoryActive },
+		"never closes":    func(w *Workflow) { w.States[3].Category = task.CategoryActive },
+		"unknown state":   func(w *Workflow) { w.Transitions[0].To = "done" },
+		"self loop":       func(w *Workflow) { w.Transitions[0].To = "backlog" },
+		"duplicate move":  func(w *Workflow) { w.Transitions[1] = w.Transitions[0] },
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := review()
+			change(w)
+			assert.ErrorIs(t, w.Validate(), ErrInvalidWorkflow)
+		})
+	}
+}
+
+func TestWorkflow_Transition(t *testing.T) {
+	w := review()
+
+	state, err := w.Transition("doing", "review")
+	assert.NoError(t, err)
+	assert.Equal(t, State{Name: "review", Category: task.CategoryActive}, state)
+
+	_, err = w.Transition("doing", "doing")
+	assert.NoError(t, err)
+
+	_, err = w.Transition("backlog", "shipped")
+	assert.ErrorIs(t, err, ErrIllegalTransition)
+	assert.EqualError(t, err, "illegal status transition: backlog cannot move to shipped (allowed: doing)")
+
+	_, err = w.Transition("shipped", "backlog")
+	assert.EqualError(t, err, "illegal status transition: shipped cannot move to backlog (allowed: none)")
+
+	_, err = w.Transition("doing", "done")
+	assert.ErrorIs(t, err, ErrUnknownStatus)
+
+	// A status left over from another workflow can move anywhere.
+	_, err = w.Transition("in_progress", "shipped")
+	assert.NoError(t, err)
+}
+
+func TestWorkflow_Target(t *testing.T) {
+	w := review()
+
+	state, err := w.Target("backlog", task.CategoryActive)
+	assert.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("doing"), state.Name)
+
+	state, err = w.Target("review", task.CategoryActive)
+	assert.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("review"), state.Name)
+
+	state, err = w.Target("review", task.CategoryClosed)
+	assert.NoError(t, err)
+	assert.Equal(t, task.TaskStatus("shipped"), state.Name)
+
+	_, err = w.Target("doing", task.CategoryClosed)
+	assert.ErrorIs(t, err, ErrIllegalTransition)
+	assert.EqualError(t, err, "illegal status transition: doing cannot move to any closed status (allowed: review)")
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"taskhub/pkg/logger"
 	"taskhub/pkg/middleware"
 	"taskhub/pkg/nats"
+	"taskhub/pkg/outbox"
 
 	"go.uber.org/fx"
 )
@@ -22,21 +24,38 @@ var GatewayModule = fx.Module(
 )
 
 type Gateway struct {
-	config         *config.Config
-	natsConn       *nats.Nats
-	httpServer     *http.Server
-	logger         *logger.Logger
-	authHandler    *handler.AuthHandler
-	taskHandler    *handler.TaskHandler
-	webHandler     *handler.WebHandler
-	authMiddleware *middleware.AuthMiddleware
+	config          *config.Config
+	natsConn        *nats.Nats
+	httpServer      *http.Server
+	logger          *logger.Logger
+	authHandler     *handler.AuthHandler
+	taskHandler     *handler.TaskHandler
+	notifHandler    *handler.NotificationHandler
+	viewHandler     *handler.ViewHandler
+	labelHandler    *handler.LabelHandler
+	projectHandler  *handler.ProjectHandler
+	workflowHandler *handler.WorkflowHandler
+	tokenHandler    *handler.PersonalTokenHandler
+	eventHandler    *handler.EventHandler
+	stopEvents      func()
+	outboxStore     outbox.Store
+	webHandler      *handler.WebHandler
+	authMiddleware  *middleware.AuthMiddleware
 }
 
 func NewGateway(
 	config *config.Config,
 	logger *logger.Logger,
+	natsConn *nats.Nats,
 	authService *app.AuthService,
 	taskService *app.TaskService,
+	notificationService *app.NotificationService,
+	viewService *app.ViewService,
+	labelService *app.LabelService,
+	projectService *app.ProjectService,
+	workflowService *app.WorkflowService,
+	tokenService *app.PersonalTokenService,
+	outboxStore outbox.Store,
 ) *Gateway {
 	webHandler, err := handler.NewWebHandler("web/templates")
 	if err != nil {
@@ -44,28 +63,95 @@ func NewGateway(
 	}
 
 	return &Gateway{
-		config:         config,
-		natsConn:       nats.NewNats(config, logger),
-		logger:         logger,
-		authHandler:    handler.NewAuthHandler(authService),
-		taskHandler:    handler.NewTaskHandler(taskService),
-		webHandler:     webHandler,
-		authMiddleware: middleware.NewAuthMiddleware(authService),
+		config:          config,
+		natsConn:        natsConn,
+		logger:          logger,
+		authHandler:     handler.NewAuthHandler(authService),
+		taskHandler:     handler.NewTaskHandler(taskService),
+		notifHandler:    handler.NewNotificationHandler(notificationService),
+		viewHandler:     handler.NewViewHandler(viewService),
+		labelHandler:    handler.NewLabelHandler(labelService),
+		projectHandler:  handler.NewProjectHandler(projectService),
+		workflowHandler: handler.NewWorkflowHandler(workflowService),
+		tokenHandler:    handler.NewPersonalTokenHandler(tokenService),
+		eventHandler:    handler.NewEventHandler(),
+		outboxStore:     outboxStore,
+		webHandler:      webHandler,
+		authMiddleware:  middleware.NewAuthMiddleware(authService, tokenService),
 	}
 }
 
+// streamedSubjects are the NATS subjects forwarded to browsers over
+// /api/events.
+var streamedSubjects = append([]string{app.SubjectTaskReminder}, app.TaskSubjects...)
+
+// subscribeEvents forwards task events from NATS to the SSE streams. Every
+// replica gets its own consumer since each holds its own clients.
+func (g *Gateway) subscribeEvents(ctx context.Context) error {
+	stop, err := g.natsConn.Consume(ctx, nats.ConsumerConfig{
+		Stream:   nats.TaskStream.Name,
+		Subjects: streamedSubjects,
+	}, func(ctx context.Context, msg *nats.Message) error {
+		if err := g.eventHandler.Publish(msg.Subject, msg.Data); err != nil {
+			return nats.Permanent(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.stopEvents = stop
+	return nil
+}
+
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+// outboxHealth reports whether the outbox backlog can be read. It returns
+// 503 when it cannot so probes notice a stuck relay's database. The backlog
+// and watermark are not exposed since the endpoint is public.
+func (g *Gateway) outboxHealth(w http.ResponseWriter, r *http.Request) {
+	status, code := "ok", http.StatusOK
+	if _, err := g.outboxStore.Stats(r.Context()); err != nil {
+		g.logger.Error("failed to read outbox stats", "error", err)
+		status, code = "degraded", http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
+
 func (g *Gateway) Start() error {
 	if g.config == nil {
 		return errors.New("config is nil")
 	}
 
+	if err := g.subscribeEvents(context.Background()); err != nil {
+		return err
+	}
+
+	g.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%s", g.config.Port),
+		Handler:      g.Handler(),
+		ReadTimeout:  g.config.ReadTimeout,
+		WriteTimeout: g.config.WriteTimeout,
+		IdleTimeout:  g.config.IdleTimeout,
+	}
+
+	g.logger.Info("Starting HTTP server on port %s", g.config.Port)
+
+	return g.httpServer.ListenAndServe()
+}
+
+// Handler builds the HTTP routing tree served by Start.
+func (g *Gateway) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/health", healthCheck)
+	mux.HandleFunc("/health/outbox", g.outboxHealth)
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
@@ -76,29 +162,55 @@ func (g *Gateway) Start() error {
 	})
 	mux.HandleFunc("/login", g.webHandler.Login)
 	mux.HandleFunc("/register", g.webHandler.Register)
+	mux.HandleFunc("/forgot-password", g.webHandler.ForgotPassword)
+	mux.HandleFunc("/reset-password", g.webHandler.ResetPassword)
+	mux.HandleFunc("/verify-email", g.webHandler.VerifyEmail)
 	mux.Handle("/dashboard", g.authMiddleware.Authenticate(http.HandlerFunc(g.webHandler.Dashboard)))
+	mux.Handle("/settings/tokens", g.authMiddleware.Authenticate(http.HandlerFunc(g.webHandler.Tokens)))
 
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))))
 
+	mux.HandleFunc("/.well-known/jwks.json", g.authHandler.JWKS)
+
 	mux.HandleFunc("/api/auth/register", g.authHandler.Register)
 	mux.HandleFunc("/api/auth/login", g.authHandler.Login)
-	mux.Handle("/api/auth/refresh", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.RefreshToken)))
-	mux.Handle("/api/auth/logout", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.Logout)))
+	mux.HandleFunc("/api/auth/login/mfa", g.authHandler.LoginMFA)
+	// Refresh and logout are authenticated by the refresh token alone, so
+	// they work once the access token has expired.
+	mux.HandleFunc("/api/auth/refresh", g.authHandler.RefreshToken)
+	mux.HandleFunc("/api/auth/logout", g.authHandler.Logout)
+	mux.Handle("/api/auth/logout-all", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.LogoutAll)))
+	mux.HandleFunc("/api/auth/password/forgot", g.authHandler.ForgotPassword)
+	mux.HandleFunc("/api/auth/password/reset", g.authHandler.ResetPassword)
+	mux.HandleFunc("/api/auth/email/verify", g.authHandler.VerifyEmail)
+	mux.Handle("/api/auth/email/verify/resend", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.ResendVerification)))
+	mux.Handle("/api/auth/mfa", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.MFA)))
+	mux.Handle("/api/auth/mfa/enroll", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.EnrollMFA)))
+	mux.Handle("/api/auth/mfa/confirm", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.ConfirmMFA)))
+	mux.Handle("/api/auth/mfa/recovery-codes", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.RegenerateRecoveryCodes)))
+	mux.Handle("/api/auth/mfa/disable", g.authMiddleware.Authenticate(http.HandlerFunc(g.authHandler.DisableMFA)))
 
-	mux.Handle("/api/tasks", g.authMiddleware.Authenticate(http.HandlerFunc(g.handleTasks)))
-	mux.Handle("/api/tasks/", g.authMiddleware.Authenticate(http.HandlerFunc(g.handleTaskByID)))
+	mux.Handle("/api/tasks", g.authMiddleware.AuthenticateScoped("tasks", http.HandlerFunc(g.handleTasks)))
+	mux.Handle("/api/tasks/", g.authMiddleware.AuthenticateScoped("tasks", http.HandlerFunc(g.handleTaskByID)))
 
-	g.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%s", g.config.Port),
-		Handler:      mux,
-		ReadTimeout:  g.config.ReadTimeout,
-		WriteTimeout: g.config.WriteTimeout,
-		IdleTimeout:  g.config.IdleTimeout,
-	}
+	mux.Handle("/api/views", g.authMiddleware.AuthenticateScoped("views", http.HandlerFunc(g.handleViews)))
+	mux.Handle("/api/views/", g.authMiddleware.AuthenticateScoped("views", http.HandlerFunc(g.handleViewByID)))
+	mux.Handle("/api/labels", g.authMiddleware.AuthenticateScoped("labels", http.HandlerFunc(g.handleLabels)))
+	mux.Handle("/api/labels/", g.authMiddleware.AuthenticateScoped("labels", http.HandlerFunc(g.handleLabelByID)))
+	mux.Handle("/api/projects", g.authMiddleware.AuthenticateScoped("projects", http.HandlerFunc(g.handleProjects)))
+	mux.Handle("/api/projects/", g.authMiddleware.AuthenticateScoped("projects", http.HandlerFunc(g.handleProjectByID)))
+	mux.Handle("/api/workflows", g.authMiddleware.AuthenticateScoped("workflows", http.HandlerFunc(g.handleWorkflows)))
+	mux.Handle("/api/workflows/", g.authMiddleware.AuthenticateScoped("workflows", http.HandlerFunc(g.handleWorkflowByID)))
 
-	g.logger.Info("Starting HTTP server on port %s", g.config.Port)
+	mux.Handle("/api/events", g.authMiddleware.AuthenticateScoped("tasks", http.HandlerFunc(g.eventHandler.Stream)))
 
-	return g.httpServer.ListenAndServe()
+	mux.Handle("/api/notifications", g.authMiddleware.AuthenticateScoped("notifications", http.HandlerFunc(g.notifHandler.List)))
+	mux.Handle("/api/notifications/", g.authMiddleware.AuthenticateScoped("notifications", http.HandlerFunc(g.handleNotificationByID)))
+
+	mux.Handle("/api/tokens", g.authMiddleware.Authenticate(http.HandlerFunc(g.handleTokens)))
+	mux.Handle("/api/tokens/", g.authMiddleware.Authenticate(http.HandlerFunc(g.tokenHandler.Delete)))
+
+	return mux
 }
 
 func (g *Gateway) handleTasks(w http.ResponseWriter, r *http.Request) {
@@ -115,9 +227,34 @@ func (g *Gateway) handleTasks(w http.ResponseWriter, r *http.Request) {
 func (g *Gateway) handleTaskByID(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/tasks/")
 
-	if strings.HasSuffix(path, "/complete") {
+	switch {
+	case strings.HasSuffix(path, "/complete"):
 		g.taskHandler.Complete(w, r)
 		return
+	case strings.HasSuffix(path, "/move"):
+		g.taskHandler.Move(w, r)
+		return
+	case strings.HasSuffix(path, "/checklist"):
+		g.taskHandler.AddChecklistItem(w, r)
+		return
+	case strings.Contains(path, "/checklist/"):
+		g.handleChecklistItem(w, r)
+		return
+	case strings.HasSuffix(path, "/start"):
+		g.taskHandler.Start(w, r)
+		return
+	case strings.HasSuffix(path, "/blockers"):
+		g.handleBlockers(w, r)
+		return
+	case strings.Contains(path, "/blockers/"):
+		g.taskHandler.RemoveBlocker(w, r)
+		return
+	case strings.HasSuffix(path, "/dependents"):
+		g.taskHandler.Dependents(w, r)
+		return
+	case strings.HasSuffix(path, "/history"):
+		g.taskHandler.History(w, r)
+		return
 	}
 
 	switch r.Method {
@@ -132,11 +269,169 @@ func (g *Gateway) handleTaskByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (g *Gateway) handleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		g.taskHandler.UpdateChecklistItem(w, r)
+	case http.MethodDelete:
+		g.taskHandler.DeleteChecklistItem(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleBlockers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.taskHandler.Blockers(w, r)
+	case http.MethodPost:
+		g.taskHandler.AddBlocker(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleViews(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.viewHandler.List(w, r)
+	case http.MethodPost:
+		g.viewHandler.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleViewByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.viewHandler.Get(w, r)
+	case http.MethodPut:
+		g.viewHandler.Update(w, r)
+	case http.MethodDelete:
+		g.viewHandler.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleLabels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.labelHandler.List(w, r)
+	case http.MethodPost:
+		g.labelHandler.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleLabelByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.labelHandler.Get(w, r)
+	case http.MethodPut:
+		g.labelHandler.Update(w, r)
+	case http.MethodDelete:
+		g.labelHandler.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleProjects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.projectHandler.List(w, r)
+	case http.MethodPost:
+		g.projectHandler.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleProjectByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/projects/")
+
+	switch {
+	case strings.HasSuffix(path, "/archive"):
+		g.projectHandler.Archive(w, r)
+		return
+	case strings.HasSuffix(path, "/unarchive"):
+		g.projectHandler.Unarchive(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		g.projectHandler.Get(w, r)
+	case http.MethodPut:
+		g.projectHandler.Update(w, r)
+	case http.MethodDelete:
+		g.projectHandler.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleWorkflows(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.workflowHandler.List(w, r)
+	case http.MethodPost:
+		g.workflowHandler.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleWorkflowByID(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/workflows/effective" {
+		g.workflowHandler.Effective(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		g.workflowHandler.Get(w, r)
+	case http.MethodPut:
+		g.workflowHandler.Update(w, r)
+	case http.MethodDelete:
+		g.workflowHandler.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.tokenHandler.List(w, r)
+	case http.MethodPost:
+		g.tokenHandler.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *Gateway) handleNotificationByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/notifications/")
+
+	switch {
+	case path == "read-all":
+		g.notifHandler.MarkAllRead(w, r)
+	case strings.HasSuffix(path, "/read"):
+		g.notifHandler.MarkRead(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
 func (g *Gateway) Shutdown(ctx context.Context) error {
 	g.logger.Info("Shutting down HTTP server")
 
-	if g.natsConn != nil {
-		g.natsConn.Close()
+	if g.stopEvents != nil {
+		g.stopEvents()
 	}
 
 	if g.httpServer != nil {
@@ -0,0 +1,923 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"taskhub/config"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/apitoken"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	labelrepo "taskhub/internal/domains/label/repo"
+	notificationrepo "taskhub/internal/domains/notification/repo"
+	projectrepo "taskhub/internal/domains/project/repo"
+	sessionrepo "taskhub/internal/domains/session/repo"
+	"taskhub/internal/domains/task"
+	taskrepo "taskhub/internal/domains/task/repo"
+	"taskhub/internal/domains/user"
+	userrepo "taskhub/internal/domains/user/repo"
+	viewrepo "taskhub/internal/domains/view/repo"
+	"taskhub/internal/domains/workflow"
+	workflowrepo "taskhub/internal/domains/workflow/repo"
+	"taskhub/pkg/db"
+	"taskhub/pkg/jwtkeys"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
+	"taskhub/pkg/outbox"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return startTestServer(t).Server
+}
+
+// newTestServerWithNotifications also returns the notification service so
+// tests can fill inboxes without a NATS connection.
+func newTestServerWithNotifications(t *testing.T) (*httptest.Server, *app.NotificationService) {
+	t.Helper()
+
+	ts := startTestServer(t)
+	return ts.Server, ts.notifications
+}
+
+// testServer is a gateway over memory repositories along with the
+// dependencies tests reach into directly.
+type testServer struct {
+	*httptest.Server
+	notifications *app.NotificationService
+	mail          *mailer.MemoryMailer
+}
+
+func startTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWTSecret: "test-secret-key-for-testing-purposes",
+		NatsUrl:   "nats://127.0.0.1:1",
+	}
+	log := logger.NewLogger()
+
+	mail := mailer.NewMemoryMailer()
+	personalTokens := apitokenrepo.NewMemoryTokenRepository()
+	authService := app.NewAuthService(cfg, log, jwtkeys.NewHMACKeyring(cfg.JWTSecret), userrepo.NewMemoryUserRepository(), sessionrepo.NewMemoryRefreshTokenRepository(), userrepo.NewMemoryActionTokenRepository(), userrepo.NewMemoryMFARepository(), personalTokens, mail)
+	tasks := taskrepo.NewMemoryTaskRepository()
+	labels := labelrepo.NewMemoryLabelRepository()
+	projects := projectrepo.NewMemoryProjectRepository()
+	outboxStore := outbox.NewMemoryStore()
+	workflows := workflowrepo.NewMemoryWorkflowRepository()
+	taskService := app.NewTaskService(log, tasks, labels, projects, workflows, app.NewOutboxEventPublisher(outboxStore), db.NoTx{})
+	notificationService := app.NewNotificationService(log, nil, tasks, notificationrepo.NewMemoryReminderLog(), notificationrepo.NewMemoryNotificationRepository())
+
+	viewService := app.NewViewService(log, viewrepo.NewMemoryViewRepository())
+	labelService := app.NewLabelService(log, labels)
+	projectService := app.NewProjectService(log, projects, tasks, db.NoTx{})
+	workflowService := app.NewWorkflowService(log, workflows, projects)
+	tokenService := app.NewPersonalTokenService(log, personalTokens)
+
+	gw := NewGateway(cfg, log, nil, authService, taskService, notificationService, viewService, labelService, projectService, workflowService, tokenService, outboxStore)
+	server := httptest.NewServer(gw.Handler())
+	t.Cleanup(server.Close)
+
+	return &testServer{Server: server, notifications: notificationService, mail: mail}
+}
+
+func doJSON(t *testing.T, client *http.Client, method, url, token string, body any, out any) *http.Response {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp
+}
+
+func registerAndLogin(t *testing.T, server *httptest.Server, email string) string {
+	t.Helper()
+
+	resp := doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/register", "", app.RegisterRequest{
+		Name:     "Test User",
+		Email:    email,
+		Password: "password123",
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var login app.LoginResponse
+	resp = doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/login", "", app.LoginRequest{
+		Email:    email,
+		Password: "password123",
+	}, &login)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	return login.Tokens.AccessToken
+}
+
+func TestGateway_TaskLifecycle(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "owner@example.com")
+
+	var created app.TaskResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{
+		Title:    "Write tests",
+		Priority: "high",
+	}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	taskURL := server.URL + "/api/tasks/" + created.Task.Id.String()
+
+	var list app.ListTasksResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", token, nil, &list)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, list.Tasks, 1)
+
+	var updated app.TaskResponse
+	resp = doJSON(t, client, http.MethodPut, taskURL, token, app.UpdateTaskRequest{
+		Title:    "Write more tests",
+		Status:   "in_progress",
+		Priority: "high",
+	}, &updated)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Write more tests", updated.Task.Title)
+
+	var completed app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, taskURL+"/complete", token, nil, &completed)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "done", string(completed.Task.Status))
+
+	resp = doJSON(t, client, http.MethodDelete, taskURL, token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodGet, taskURL, token, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGateway_ListTasksPagination(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "pager@example.com")
+
+	for _, title := range []string{"one", "two", "three"} {
+		doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: title}, nil)
+	}
+
+	var page app.ListTasksResponse
+	resp := doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?limit=2&sort=title&order=asc", token, nil, &page)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, "one", page.Tasks[0].Title)
+	require.NotEmpty(t, page.NextCursor)
+
+	var last app.ListTasksResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?limit=2&sort=title&order=asc&cursor="+page.NextCursor, token, nil, &last)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, last.Tasks, 1)
+	assert.Equal(t, "two", last.Tasks[0].Title)
+	assert.Empty(t, last.NextCursor)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?sort=status", token, nil, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?cursor=garbage", token, nil, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_SavedViews(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "views@example.com")
+	otherToken := registerAndLogin(t, server, "peeker@example.com")
+
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Urgent", Priority: task.PriorityHigh}, nil)
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Someday", Priority: task.PriorityLow}, nil)
+
+	var created app.ViewResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/views", token, app.SaveViewRequest{Name: "Hot", Query: "priority:high"}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/views", token, app.SaveViewRequest{Name: "Hot", Query: "status:todo"}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/views", token, app.SaveViewRequest{Name: "Bad", Query: "due:whenever"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var list app.ListViewsResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/views", token, nil, &list)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, list.Views, 1)
+
+	var tasks app.ListTasksResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?q="+url.QueryEscape(list.Views[0].Query), token, nil, &tasks)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, tasks.Total)
+	assert.Equal(t, "Urgent", tasks.Tasks[0].Title)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?q="+url.QueryEscape("priority:urgent"), token, nil, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	viewURL := server.URL + "/api/views/" + created.View.Id.String()
+	resp = doJSON(t, client, http.MethodGet, viewURL, otherToken, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var updated app.ViewResponse
+	resp = doJSON(t, client, http.MethodPut, viewURL, token, app.SaveViewRequest{Name: "Hot", Query: "priority:high status:todo"}, &updated)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "priority:high status:todo", updated.View.Query)
+
+	resp = doJSON(t, client, http.MethodDelete, viewURL, token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodGet, viewURL, token, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGateway_SubtasksAndChecklist(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "steps@example.com")
+
+	var parent app.TaskResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Launch", Checklist: []string{"Write notes"}}, &parent)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	parentURL := server.URL + "/api/tasks/" + parent.Task.Id.String()
+
+	var child app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Ship", ParentID: &parent.Task.Id}, &child)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var children app.ListTasksResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?parent="+parent.Task.Id.String(), token, nil, &children)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, children.Tasks, 1)
+	assert.Equal(t, "Ship", children.Tasks[0].Title)
+
+	var topLevel app.ListTasksResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?parent=none", token, nil, &topLevel)
+	require.Len(t, topLevel.Tasks, 1)
+	assert.Equal(t, 1, topLevel.Tasks[0].Progress.Total)
+
+	resp = doJSON(t, client, http.MethodPost, parentURL+"/complete", token, nil, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var item app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, parentURL+"/checklist", token, app.AddChecklistItemRequest{Text: "Tell everyone"}, &item)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Len(t, item.Task.Checklist, 2)
+
+	done := true
+	itemURL := parentURL + "/checklist/" + item.Task.Checklist[0].ID.String()
+	var toggled app.TaskResponse
+	resp = doJSON(t, client, http.MethodPatch, itemURL, token, app.UpdateChecklistItemRequest{Done: &done}, &toggled)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, toggled.Task.Checklist[0].Done)
+
+	resp = doJSON(t, client, http.MethodDelete, itemURL, token, nil, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodDelete, itemURL, token, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var completed app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, parentURL+"/complete?force=true", token, nil, &completed)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 100, completed.Task.Progress.Percent)
+
+	var moved app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks/"+child.Task.Id.String()+"/move", token, app.MoveTaskRequest{}, &moved)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, moved.Task.ParentID)
+
+	resp = doJSON(t, client, http.MethodPost, parentURL+"/move", token, app.MoveTaskRequest{ParentID: &parent.Task.Id}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_Dependencies(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "deps@example.com")
+
+	var design, build app.TaskResponse
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Design"}, &design)
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Build"}, &build)
+	designURL := server.URL + "/api/tasks/" + design.Task.Id.String()
+	buildURL := server.URL + "/api/tasks/" + build.Task.Id.String()
+
+	var blocked app.TaskResponse
+	resp := doJSON(t, client, http.MethodPost, buildURL+"/blockers", token, app.AddDependencyRequest{BlockedByID: design.Task.Id}, &blocked)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.True(t, blocked.Task.Blocked)
+
+	resp = doJSON(t, client, http.MethodPost, designURL+"/blockers", token, app.AddDependencyRequest{BlockedByID: build.Task.Id}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, buildURL+"/start", token, nil, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, buildURL+"/complete", token, nil, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var upstream app.DependencyGraphResponse
+	resp = doJSON(t, client, http.MethodGet, buildURL+"/blockers", token, nil, &upstream)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, upstream.Tasks, 1)
+	assert.Equal(t, "Design", upstream.Tasks[0].Title)
+
+	var downstream app.DependencyGraphResponse
+	resp = doJSON(t, client, http.MethodGet, designURL+"/dependents", token, nil, &downstream)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, downstream.Tasks, 1)
+	assert.Equal(t, "Build", downstream.Tasks[0].Title)
+
+	resp = doJSON(t, client, http.MethodDelete, buildURL+"/blockers/"+design.Task.Id.String(), token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodDelete, buildURL+"/blockers/"+design.Task.Id.String(), token, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var started app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, buildURL+"/start", token, nil, &started)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.False(t, started.Task.Blocked)
+}
+
+func TestGateway_RecurringTasks(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "chores@example.com")
+	deadline := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Rent", Recurrence: "FREQ=MONTHLY"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var created app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Rent", Deadline: &deadline, Recurrence: "FREQ=MONTHLY"}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NotNil(t, created.Task.Recurrence)
+
+	var completed app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks/"+created.Task.Id.String()+"/complete", token, nil, &completed)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, completed.Next)
+	assert.Equal(t, time.Date(2026, 5, 31, 18, 0, 0, 0, time.UTC), completed.Next.Deadline.UTC())
+
+	resp = doJSON(t, client, http.MethodPut, server.URL+"/api/tasks/"+completed.Next.Id.String(), token, app.UpdateTaskRequest{Title: "Rent", Scope: "all"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_Labels(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "labels@example.com")
+	otherToken := registerAndLogin(t, server, "other-labels@example.com")
+
+	var work, urgent app.LabelResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/labels", token, app.SaveLabelRequest{Name: "work", Color: "#2563eb"}, &work)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	doJSON(t, client, http.MethodPost, server.URL+"/api/labels", token, app.SaveLabelRequest{Name: "urgent"}, &urgent)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/labels", token, app.SaveLabelRequest{Name: "work"}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/labels", token, app.SaveLabelRequest{Name: "bad", Color: "blue"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", otherToken, app.CreateTaskRequest{Title: "Theirs", LabelIDs: []uuid.UUID{work.Label.Id}}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var tagged app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Report", LabelIDs: []uuid.UUID{work.Label.Id, urgent.Label.Id}}, &tagged)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Len(t, tagged.Task.Labels, 2)
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Email", LabelIDs: []uuid.UUID{work.Label.Id}}, nil)
+
+	var list app.ListTasksResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?labels_any="+work.Label.Id.String(), token, nil, &list)
+	assert.Len(t, list.Tasks, 2)
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?labels_all="+work.Label.Id.String()+","+urgent.Label.Id.String(), token, nil, &list)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "Report", list.Tasks[0].Title)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?labels_any=nope", token, nil, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodDelete, server.URL+"/api/labels/"+urgent.Label.Id.String(), otherToken, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodDelete, server.URL+"/api/labels/"+urgent.Label.Id.String(), token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	var labels app.ListLabelsResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/labels", token, nil, &labels)
+	require.Len(t, labels.Labels, 1)
+	assert.Equal(t, "work", labels.Labels[0].Name)
+}
+
+func TestGateway_Projects(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "projects@example.com")
+	otherToken := registerAndLogin(t, server, "other-projects@example.com")
+
+	var launch app.ProjectResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/projects", token, app.SaveProjectRequest{Name: "Q3 launch", DefaultPriority: task.PriorityHigh}, &launch)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	projectURL := server.URL + "/api/projects/" + launch.Project.Id.String()
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/projects", token, app.SaveProjectRequest{Name: "Q3 launch"}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/projects", token, app.SaveProjectRequest{Name: "Bad", DefaultPriority: "urgent"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", otherToken, app.CreateTaskRequest{Title: "Theirs", ProjectID: &launch.Project.Id}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var created app.TaskResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Press kit", ProjectID: &launch.Project.Id}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, task.PriorityHigh, created.Task.Priority)
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Laundry"}, nil)
+
+	var list app.ListTasksResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?project="+launch.Project.Id.String(), token, nil, &list)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "Press kit", list.Tasks[0].Title)
+
+	resp = doJSON(t, client, http.MethodPost, projectURL+"/archive", otherToken, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodGet, projectURL+"/archive", token, nil, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	var archived app.ProjectResponse
+	resp = doJSON(t, client, http.MethodPost, projectURL+"/archive", token, nil, &archived)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, archived.Project.ArchivedAt)
+
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", token, nil, &list)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "Laundry", list.Tasks[0].Title)
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks?archived=true", token, nil, &list)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "Press kit", list.Tasks[0].Title)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Late", ProjectID: &launch.Project.Id}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var projects app.ListProjectsResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/projects", token, nil, &projects)
+	assert.Empty(t, projects.Projects)
+	doJSON(t, client, http.MethodGet, server.URL+"/api/projects?archived=true", token, nil, &projects)
+	assert.Len(t, projects.Projects, 1)
+
+	resp = doJSON(t, client, http.MethodPost, projectURL+"/unarchive", token, nil, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", token, nil, &list)
+	assert.Len(t, list.Tasks, 2)
+
+	resp = doJSON(t, client, http.MethodDelete, projectURL, token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodGet, projectURL, token, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGateway_Workflows(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "workflows@example.com")
+
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/workflows", token, app.SaveWorkflowRequest{Name: "Empty"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var created app.WorkflowResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/workflows", token, app.SaveWorkflowRequest{
+		Name: "Review",
+		States: []workflow.State{
+			{Name: "backlog", Category: task.CategoryOpen},
+			{Name: "review", Category: task.CategoryActive},
+			{Name: "shipped", Category: task.CategoryClosed},
+		},
+		Transitions: []workflow.Transition{
+			{From: "backlog", To: "review"},
+			{From: "review", To: "shipped"},
+		},
+	}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/workflows", token, app.SaveWorkflowRequest{Name: "Review", States: created.Workflow.States}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var effective app.WorkflowResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/workflows/effective", token, nil, &effective)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, created.Workflow.Id, effective.Workflow.Id)
+
+	var tsk app.TaskResponse
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Launch"}, &tsk)
+	assert.Equal(t, task.TaskStatus("backlog"), tsk.Task.Status)
+	taskURL := server.URL + "/api/tasks/" + tsk.Task.Id.String()
+
+	var errResp map[string]string
+	resp = doJSON(t, client, http.MethodPut, taskURL, token, app.UpdateTaskRequest{Title: "Launch", Status: "shipped"}, &errResp)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Contains(t, errResp["error"], "allowed: review")
+	resp = doJSON(t, client, http.MethodPut, taskURL, token, app.UpdateTaskRequest{Title: "Launch", Status: "done"}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPut, taskURL, token, app.UpdateTaskRequest{Title: "Launch", Status: "review"}, &tsk)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, task.CategoryActive, tsk.Task.StatusCategory)
+
+	resp = doJSON(t, client, http.MethodPost, taskURL+"/complete", token, nil, &tsk)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, task.TaskStatus("shipped"), tsk.Task.Status)
+
+	workflowURL := server.URL + "/api/workflows/" + created.Workflow.Id.String()
+	resp = doJSON(t, client, http.MethodDelete, workflowURL, token, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	doJSON(t, client, http.MethodGet, server.URL+"/api/workflows/effective", token, nil, &effective)
+	assert.Equal(t, "Default", effective.Workflow.Name)
+}
+
+func TestGateway_TaskHistory(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	token := registerAndLogin(t, server, "history@example.com")
+	otherToken := registerAndLogin(t, server, "other-history@example.com")
+
+	var created app.TaskResponse
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Audit", Priority: task.PriorityLow}, &created)
+	taskURL := server.URL + "/api/tasks/" + created.Task.Id.String()
+	doJSON(t, client, http.MethodPut, taskURL, token, app.UpdateTaskRequest{Title: "Audit", Priority: task.PriorityHigh}, nil)
+	doJSON(t, client, http.MethodPost, taskURL+"/complete", token, nil, nil)
+
+	var history app.TaskHistoryResponse
+	resp := doJSON(t, client, http.MethodGet, taskURL+"/history", token, nil, &history)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, history.History, 3)
+	assert.Equal(t, "priority", history.History[1].Field)
+	assert.Equal(t, "status", history.History[2].Field)
+
+	resp = doJSON(t, client, http.MethodGet, taskURL+"/history", otherToken, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, taskURL+"/history", token, nil, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, taskURL+"/history", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("HX-Request", "true")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body bytes.Buffer
+	body.ReadFrom(resp.Body)
+	assert.Contains(t, body.String(), "You moved the task from todo to done")
+	assert.Contains(t, body.String(), "You changed the priority from low to high")
+}
+
+func TestGateway_TasksAreScopedToOwner(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+	ownerToken := registerAndLogin(t, server, "owner@example.com")
+	otherToken := registerAndLogin(t, server, "other@example.com")
+
+	var created app.TaskResponse
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", ownerToken, app.CreateTaskRequest{Title: "Private"}, &created)
+
+	resp := doJSON(t, client, http.MethodGet, server.URL+"/api/tasks/"+created.Task.Id.String(), otherToken, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var list app.ListTasksResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", otherToken, nil, &list)
+	assert.Empty(t, list.Tasks)
+}
+
+func TestGateway_Unauthenticated(t *testing.T) {
+	server := newTestServer(t)
+
+	resp := doJSON(t, server.Client(), http.MethodGet, server.URL+"/api/tasks", "", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestGateway_CookieSession(t *testing.T) {
+	server := newTestServer(t)
+	registerAndLogin(t, server, "web@example.com")
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := server.Client()
+	client.Jar = jar
+
+	form := url.Values{"email": {"web@example.com"}, "password": {"password123"}}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/auth/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/dashboard", resp.Header.Get("HX-Redirect"))
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/api/tasks", nil)
+	req.Header.Set("HX-Request", "true")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGateway_RefreshTokenSessions(t *testing.T) {
+	server := newTestServer(t)
+	registerAndLogin(t, server, "sessions@example.com")
+
+	login := func() *app.TokenPair {
+		var resp app.LoginResponse
+		r := doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/login", "", app.LoginRequest{
+			Email:    "sessions@example.com",
+			Password: "password123",
+		}, &resp)
+		require.Equal(t, http.StatusOK, r.StatusCode)
+		return resp.Tokens
+	}
+	refresh := func(tokens *app.TokenPair) (*app.TokenPair, int) {
+		var refreshed app.TokenPair
+		r := doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/refresh", "", app.RefreshTokenRequest{
+			RefreshToken: tokens.RefreshToken,
+		}, nil)
+		if r.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&refreshed))
+		}
+		return &refreshed, r.StatusCode
+	}
+
+	first := login()
+	second, status := refresh(first)
+	require.Equal(t, http.StatusOK, status, "refreshing needs no access token")
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	_, status = refresh(&app.TokenPair{RefreshToken: second.AccessToken})
+	assert.Equal(t, http.StatusUnauthorized, status, "access tokens are not refresh tokens")
+
+	resp := doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/logout", "", app.RefreshTokenRequest{
+		RefreshToken: second.RefreshToken,
+	}, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, status = refresh(second)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	laptop, phone := login(), login()
+	resp = doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/auth/logout-all", laptop.AccessToken, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	for _, tokens := range []*app.TokenPair{laptop, phone} {
+		_, status = refresh(tokens)
+		assert.Equal(t, http.StatusUnauthorized, status)
+	}
+}
+
+// mailedToken returns the token in the link of the last email sent to to.
+func mailedToken(t *testing.T, mail *mailer.MemoryMailer, to string) string {
+	t.Helper()
+
+	messages := mail.Messages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].To != to {
+			continue
+		}
+		_, rest, found := strings.Cut(messages[i].Body, "?token=")
+		require.True(t, found, "no link in %q", messages[i].Body)
+		token, _, _ := strings.Cut(rest, "\n")
+		unescaped, err := url.QueryUnescape(token)
+		require.NoError(t, err)
+		return unescaped
+	}
+
+	t.Fatalf("no email sent to %s", to)
+	return ""
+}
+
+func TestGateway_PasswordReset(t *testing.T) {
+	ts := startTestServer(t)
+	accessToken := registerAndLogin(t, ts.Server, "reset@example.com")
+	client := ts.Client()
+
+	resp := doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/password/forgot", "", app.ForgotPasswordRequest{Email: "nobody@example.com"}, nil)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/password/forgot", "", app.ForgotPasswordRequest{Email: "reset@example.com"}, nil)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	// The link is mailed in the background, after the verification email.
+	require.Eventually(t, func() bool { return len(ts.mail.Messages()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	token := mailedToken(t, ts.mail, "reset@example.com")
+
+	reset := app.ResetPasswordRequest{Token: token, Password: "new-password"}
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/password/reset", "", reset, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/password/reset", "", reset, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "reset tokens are single-use")
+
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/login", "", app.LoginRequest{Email: "reset@example.com", Password: "password123"}, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/login", "", app.LoginRequest{Email: "reset@example.com", Password: "new-password"}, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, ts.URL+"/api/auth/email/verify/resend", accessToken, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestGateway_EmailVerification(t *testing.T) {
+	ts := startTestServer(t)
+	registerAndLogin(t, ts.Server, "verify@example.com")
+	token := mailedToken(t, ts.mail, "verify@example.com")
+
+	resp := doJSON(t, ts.Client(), http.MethodPost, ts.URL+"/api/auth/email/verify", "", app.VerifyEmailRequest{Token: "bogus"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var verified app.VerifyEmailResponse
+	resp = doJSON(t, ts.Client(), http.MethodPost, ts.URL+"/api/auth/email/verify", "", app.VerifyEmailRequest{Token: token}, &verified)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "verify@example.com", verified.User.Email)
+	assert.NotNil(t, verified.User.EmailVerifiedAt)
+	assert.Empty(t, verified.User.Password)
+}
+
+func TestGateway_TwoFactorLogin(t *testing.T) {
+	server := newTestServer(t)
+	accessToken := registerAndLogin(t, server, "mfa@example.com")
+	client := server.Client()
+
+	var enrollment app.MFAEnrollmentResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/auth/mfa/enroll", accessToken, nil, &enrollment)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	code, err := user.TOTPCode(enrollment.Secret, user.TOTPCounter(time.Now()))
+	require.NoError(t, err)
+	var codes app.RecoveryCodesResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/auth/mfa/confirm", accessToken, app.MFACodeRequest{Code: code}, &codes)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, codes.RecoveryCodes)
+
+	var login app.LoginResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/auth/login", "", app.LoginRequest{
+		Email:    "mfa@example.com",
+		Password: "password123",
+	}, &login)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, login.MFARequired)
+	assert.Nil(t, login.Tokens)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", login.MFAToken, nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/auth/login/mfa", "", app.LoginMFARequest{MFAToken: login.MFAToken, Code: "000000"}, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var completed app.LoginResponse
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/auth/login/mfa", "", app.LoginMFARequest{
+		MFAToken: login.MFAToken,
+		Code:     codes.RecoveryCodes[0],
+	}, &completed)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var status app.MFAStatusResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/auth/mfa", completed.Tokens.AccessToken, nil, &status)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, len(codes.RecoveryCodes)-1, status.RecoveryCodesLeft)
+}
+
+func TestGateway_PersonalAccessTokens(t *testing.T) {
+	server := newTestServer(t)
+	accessToken := registerAndLogin(t, server, "ci@example.com")
+	client := server.Client()
+
+	var created app.CreatePersonalTokenResponse
+	resp := doJSON(t, client, http.MethodPost, server.URL+"/api/tokens", accessToken, app.CreatePersonalTokenRequest{
+		Name:          "ci",
+		Scopes:        []apitoken.Scope{apitoken.ScopeTasksWrite},
+		ExpiresInDays: 30,
+	}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	pat := created.Secret
+
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", pat, app.CreateTaskRequest{Title: "From CI"}, nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var tasks app.ListTasksResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", pat, nil, &tasks)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, tasks.Tasks, 1)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/labels", pat, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tokens", pat, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/auth/logout-all", pat, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var list app.ListPersonalTokensResponse
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tokens", accessToken, nil, &list)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, list.Tokens, 1)
+	assert.NotNil(t, list.Tokens[0].LastUsedAt)
+
+	other := registerAndLogin(t, server, "other@example.com")
+	resp = doJSON(t, client, http.MethodDelete, server.URL+"/api/tokens/"+created.Token.Id.String(), other, nil, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodDelete, server.URL+"/api/tokens/"+created.Token.Id.String(), accessToken, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/tasks", pat, nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestGateway_NotificationInbox(t *testing.T) {
+	server, notificationService := newTestServerWithNotifications(t)
+	client := server.Client()
+	ownerToken := registerAndLogin(t, server, "owner@example.com")
+	otherToken := registerAndLogin(t, server, "other@example.com")
+
+	var created app.TaskResponse
+	doJSON(t, client, http.MethodPost, server.URL+"/api/tasks", ownerToken, app.CreateTaskRequest{Title: "Inbox"}, &created)
+
+	event := &app.TaskEvent{
+		ID:        uuid.New(),
+		EventType: app.SubjectTaskCreated,
+		TaskID:    created.Task.Id,
+		UserID:    created.Task.UserID,
+		Title:     created.Task.Title,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, notificationService.RecordTaskEvent(context.Background(), event))
+	require.NoError(t, notificationService.RecordTaskEvent(context.Background(), event))
+
+	var inbox app.ListNotificationsResponse
+	resp := doJSON(t, client, http.MethodGet, server.URL+"/api/notifications", ownerToken, nil, &inbox)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, inbox.Notifications, 1)
+	assert.Equal(t, 1, inbox.Unread)
+	readURL := server.URL + "/api/notifications/" + inbox.Notifications[0].Id.String() + "/read"
+
+	var otherInbox app.ListNotificationsResponse
+	doJSON(t, client, http.MethodGet, server.URL+"/api/notifications", otherToken, nil, &otherInbox)
+	assert.Empty(t, otherInbox.Notifications)
+
+	resp = doJSON(t, client, http.MethodPost, readURL, otherToken, nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodPost, readURL, ownerToken, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = doJSON(t, client, http.MethodGet, server.URL+"/api/notifications?unread=true", ownerToken, nil, &inbox)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, inbox.Notifications)
+	assert.Equal(t, 0, inbox.Unread)
+
+	var marked map[string]int64
+	resp = doJSON(t, client, http.MethodPost, server.URL+"/api/notifications/read-all", ownerToken, nil, &marked)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(0), marked["marked"])
+}
+
+func TestGateway_OutboxHealth(t *testing.T) {
+	server := newTestServer(t)
+	token := registerAndLogin(t, server, "owner@example.com")
+
+	doJSON(t, server.Client(), http.MethodPost, server.URL+"/api/tasks", token, app.CreateTaskRequest{Title: "Queued"}, nil)
+
+	var health map[string]any
+	resp := doJSON(t, server.Client(), http.MethodGet, server.URL+"/health/outbox", "", nil, &health)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, map[string]any{"status": "ok"}, health)
+}
+
+func TestGateway_JWKS(t *testing.T) {
+	server := newTestServer(t)
+
+	var set jwtkeys.JWKSet
+	resp := doJSON(t, server.Client(), http.MethodGet, server.URL+"/.well-known/jwks.json", "", nil, &set)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Cache-Control"))
+	// The test server signs with a shared secret, which is never published.
+	assert.NotNil(t, set.Keys)
+	assert.Empty(t, set.Keys)
+}
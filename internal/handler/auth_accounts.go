@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskhub/internal/app"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+// ForgotPassword mails a password reset link. It answers the same whether
+// or not the email belongs to an account.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	isHTMX := isHTMXRequest(r)
+
+	var req app.ForgotPasswordRequest
+	if isHTMX {
+		req.Email = r.FormValue("email")
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	if req.Email == "" {
+		if isHTMX {
+			writeHTMXError(w, "Email is required")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	h.authService.ForgotPassword(r.Context(), &req)
+
+	if isHTMX {
+		writeHTMXSuccess(w, "If an account uses that email, we sent it a link to reset the password.", "")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password with a token from a reset email and
+// ends all of the user's sessions.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	isHTMX := isHTMXRequest(r)
+
+	var req app.ResetPasswordRequest
+	if isHTMX {
+		req.Token = r.FormValue("token")
+		req.Password = r.FormValue("password")
+		if req.Password != r.FormValue("confirm_password") {
+			writeHTMXError(w, "Passwords do not match")
+			return
+		}
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	if req.Token == "" || req.Password == "" {
+		if isHTMX {
+			writeHTMXError(w, "A new password is required")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "token and password are required")
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), &req); err != nil {
+		if err == app.ErrInvalidActionToken {
+			if isHTMX {
+				writeHTMXError(w, "This reset link is invalid or has expired. Please request a new one.")
+				return
+			}
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if isHTMX {
+			writeHTMXError(w, "Failed to reset your password. Please try again.")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to reset password")
+		return
+	}
+
+	middleware.ClearAuthCookies(w)
+
+	if isHTMX {
+		writeHTMXSuccess(w, "Password changed! Redirecting to login...", "/login")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail verifies the user's email address with a token from a
+// verification email.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	isHTMX := isHTMXRequest(r)
+
+	var req app.VerifyEmailRequest
+	if isHTMX {
+		req.Token = r.FormValue("token")
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	resp, err := h.authService.VerifyEmail(r.Context(), &req)
+	if err != nil {
+		if err == app.ErrInvalidActionToken {
+			if isHTMX {
+				writeHTMXError(w, "This verification link is invalid or has expired.")
+				return
+			}
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if isHTMX {
+			writeHTMXError(w, "Failed to verify your email. Please try again.")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to verify email")
+		return
+	}
+
+	if isHTMX {
+		writeHTMXSuccess(w, "Your email address is verified.", "")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ResendVerification mails the current user a new verification link.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	if err := h.authService.ResendEmailVerification(r.Context(), userID); err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to send the verification email")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to send verification email")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		writeHTMXSuccess(w, "Verification email sent", "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"taskhub/internal/app"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -98,7 +102,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if isHTMX {
-		writeHTMXSuccess(w, "Account created successfully! Redirecting to login...", "/login")
+		writeHTMXSuccess(w, "Account created! Check your email to verify your address. Redirecting to login...", "/login")
 		return
 	}
 
@@ -132,6 +136,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "email and password are required")
 		return
 	}
+	req.Device = r.UserAgent()
 
 	resp, err := h.authService.Login(r.Context(), &req)
 	if err != nil {
@@ -151,23 +156,13 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isHTMX && resp.MFARequired {
+		writeHTMXMFAForm(w, resp.MFAToken)
+		return
+	}
+
 	if isHTMX {
-		http.SetCookie(w, &http.Cookie{
-			Name:     "access_token",
-			Value:    resp.Tokens.AccessToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   false,
-			MaxAge:   900,
-		})
-		http.SetCookie(w, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    resp.Tokens.RefreshToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   false,
-			MaxAge:   604800,
-		})
+		middleware.SetAuthCookies(w, resp.Tokens)
 		writeHTMXSuccess(w, "Login successful! Redirecting...", "/dashboard")
 		return
 	}
@@ -202,6 +197,10 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnauthorized, "invalid refresh token")
 			return
 		}
+		if err == app.ErrTokenReused {
+			writeError(w, http.StatusUnauthorized, "refresh token reused; session revoked")
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "failed to refresh token")
 		return
 	}
@@ -209,21 +208,37 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, tokens)
 }
 
+// Logout ends the session of the refresh token in the refresh cookie, or in
+// the JSON body of API clients, and clears the auth cookies. It needs no
+// access token, so an expired session can still be ended. API clients, which
+// send the token in the body or a bearer token, get 204 No Content rather
+// than a redirect to the login page.
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		MaxAge:   -1,
-	})
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		MaxAge:   -1,
-	})
+	refreshToken := ""
+	apiClient := r.Header.Get("Authorization") != ""
+	if cookie, err := r.Cookie(middleware.RefreshTokenCookie); err == nil {
+		refreshToken = cookie.Value
+	} else if r.Body != nil {
+		var req app.RefreshTokenRequest
+		if json.NewDecoder(r.Body).Decode(&req) == nil && req.RefreshToken != "" {
+			refreshToken = req.RefreshToken
+			apiClient = true
+		}
+	}
+
+	if refreshToken != "" {
+		if err := h.authService.Logout(r.Context(), refreshToken); err != nil && !errors.Is(err, app.ErrInvalidToken) {
+			writeError(w, http.StatusInternalServerError, "failed to logout")
+			return
+		}
+	}
+
+	middleware.ClearAuthCookies(w)
+
+	if apiClient {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
 
 	if isHTMXRequest(r) {
 		w.Header().Set("HX-Redirect", "/login")
@@ -233,3 +248,52 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	http.Redirect(w, r, "/login", http.StatusSeeOther)
 }
+
+// LogoutAll ends every session of the user, on all devices, and clears the
+// auth cookies. Access tokens already issued stay valid until they expire.
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	if err := h.authService.LogoutEverywhere(r.Context(), userID); err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to log out everywhere")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to logout")
+		return
+	}
+
+	middleware.ClearAuthCookies(w)
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Redirect", "/login")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// JWKS publishes the public keys that verify TaskHub's tokens, so other
+// services can check them without holding a signing key.
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// Verifiers may cache the keys for five minutes, so a new key should be
+	// published as a verification key before it becomes the active one.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	writeJSON(w, http.StatusOK, h.authService.JWKS())
+}
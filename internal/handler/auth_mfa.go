@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"taskhub/internal/app"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+// writeHTMXMFAForm asks for the second factor of a login, replacing the
+// login page's message area with a form that completes it.
+func writeHTMXMFAForm(w http.ResponseWriter, mfaToken string) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, `<form hx-post="/api/auth/login/mfa" hx-target="#message" hx-swap="innerHTML" class="mfa-form">
+    <input type="hidden" name="mfa_token" value="%s">
+    <div class="form-group">
+        <label for="code">Authentication code</label>
+        <input type="text" id="code" name="code" autocomplete="one-time-code" placeholder="6-digit code or recovery code" autofocus required>
+    </div>
+    <button type="submit" class="btn btn-primary">Verify</button>
+</form>`, html.EscapeString(mfaToken))
+}
+
+// writeMFAError maps two-factor authentication errors to HTTP responses.
+func writeMFAError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrInvalidMFACode):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, app.ErrMFAAlreadyEnabled):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, app.ErrMFANotEnrolled):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+// LoginMFA completes a login that needs a second factor.
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	isHTMX := isHTMXRequest(r)
+
+	var req app.LoginMFARequest
+	if isHTMX {
+		req.MFAToken = r.FormValue("mfa_token")
+		req.Code = r.FormValue("code")
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	req.Device = r.UserAgent()
+
+	resp, err := h.authService.LoginMFA(r.Context(), &req)
+	if err != nil {
+		if err == app.ErrInvalidMFACode {
+			if isHTMX {
+				// Keep the form so the user can try another code.
+				writeHTMXMFAForm(w, req.MFAToken)
+				fmt.Fprint(w, `<div class="alert alert-error shake">Invalid authentication code</div>`)
+				return
+			}
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err == app.ErrInvalidToken || err == app.ErrTokenExpired {
+			if isHTMX {
+				writeHTMXError(w, "Your login expired or had too many wrong codes. Please sign in again.")
+				return
+			}
+			writeError(w, http.StatusUnauthorized, "invalid or expired mfa token")
+			return
+		}
+		if err == app.ErrTooManyMFAAttempts {
+			if isHTMX {
+				writeHTMXError(w, "Too many wrong codes. Please try again later.")
+				return
+			}
+			writeError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		if isHTMX {
+			writeHTMXError(w, "Login failed. Please try again.")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to login")
+		return
+	}
+
+	if isHTMX {
+		middleware.SetAuthCookies(w, resp.Tokens)
+		writeHTMXSuccess(w, "Login successful! Redirecting...", "/dashboard")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// MFA reports whether the current user has two-factor authentication on.
+func (h *AuthHandler) MFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.authService.MFAStatus(r.Context(), userID)
+	if err != nil {
+		writeMFAError(w, err, "get two-factor status")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// EnrollMFA starts setting up two-factor authentication.
+func (h *AuthHandler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.authService.EnrollMFA(r.Context(), userID)
+	if err != nil {
+		writeMFAError(w, err, "set up two-factor authentication")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ConfirmMFA turns two-factor authentication on and returns recovery codes.
+func (h *AuthHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Code == "" {
+		writeError(w, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	resp, err := h.authService.ConfirmMFA(r.Context(), userID, &req)
+	if err != nil {
+		writeMFAError(w, err, "confirm two-factor authentication")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes.
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Code == "" {
+		writeError(w, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	resp, err := h.authService.RegenerateRecoveryCodes(r.Context(), userID, &req)
+	if err != nil {
+		writeMFAError(w, err, "regenerate recovery codes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DisableMFA turns two-factor authentication off.
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Code == "" {
+		writeError(w, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	if err := h.authService.DisableMFA(r.Context(), userID, &req); err != nil {
+		writeMFAError(w, err, "disable two-factor authentication")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"taskhub/pkg/middleware"
+	"taskhub/pkg/sse"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// eventHistorySize and eventHistoryAge bound the events kept per user for
+// Last-Event-ID resume.
+const (
+	eventHistorySize = 100
+	eventHistoryAge  = 5 * time.Minute
+)
+
+// EventHandler streams task events to the owning user over Server-Sent
+// Events.
+type EventHandler struct {
+	broker    *sse.Broker
+	keepAlive time.Duration
+}
+
+func NewEventHandler() *EventHandler {
+	return &EventHandler{
+		broker:    sse.NewBroker(eventHistorySize, eventHistoryAge),
+		keepAlive: 15 * time.Second,
+	}
+}
+
+// Publish routes a NATS message to the stream of the user it belongs to. The
+// SSE event name is the subject with dots replaced by dashes, e.g.
+// task.created becomes task-created, so it can be used in hx-trigger.
+func (h *EventHandler) Publish(subject string, data []byte) error {
+	var envelope struct {
+		ID     uuid.UUID `json:"id"`
+		UserID uuid.UUID `json:"user_id"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	if envelope.UserID == uuid.Nil {
+		return nil
+	}
+
+	id := envelope.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	h.broker.Publish(envelope.UserID.String(), sse.Event{
+		ID:   id.String(),
+		Name: strings.ReplaceAll(subject, ".", "-"),
+		Data: data,
+	})
+
+	return nil
+}
+
+func (h *EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	if _, err := uuid.Parse(userIDStr); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server WriteTimeout would otherwise cut long-lived streams.
+	rc.SetWriteDeadline(time.Time{})
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub := h.broker.Subscribe(userIDStr, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Missed {
+		sse.Event{Name: "resync", Data: []byte("{}")}.Write(w)
+	}
+	for _, e := range sub.Replay {
+		if err := e.Write(w); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if err := e.Write(w); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHandler_Stream_InvalidUser(t *testing.T) {
+	handler := NewEventHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Stream(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestEventHandler_Stream_FiltersByUserAndResumes(t *testing.T) {
+	handler := NewEventHandler()
+	userID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDKey, userID.String())
+		handler.Stream(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	first, _ := json.Marshal(map[string]any{"id": uuid.New(), "user_id": userID})
+	require.NoError(t, handler.Publish("task.created", first))
+
+	other, _ := json.Marshal(map[string]any{"id": uuid.New(), "user_id": uuid.New()})
+	require.NoError(t, handler.Publish("task.created", other))
+
+	secondID := uuid.New()
+	second, _ := json.Marshal(map[string]any{"id": secondID, "user_id": userID})
+	require.NoError(t, handler.Publish("task.updated", second))
+
+	var firstEvent struct {
+		ID uuid.UUID `json:"id"`
+	}
+	json.Unmarshal(first, &firstEvent)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	req.Header.Set("Last-Event-ID", firstEvent.ID.String())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			break
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	assert.Equal(t, []string{
+		"id: " + secondID.String(),
+		"event: task-updated",
+		"data: " + string(second),
+	}, lines)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/label"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type LabelHandler struct {
+	labelService *app.LabelService
+}
+
+func NewLabelHandler(labelService *app.LabelService) *LabelHandler {
+	return &LabelHandler{
+		labelService: labelService,
+	}
+}
+
+// writeLabelError maps label service errors to HTTP responses.
+func writeLabelError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrLabelNotFound):
+		writeError(w, http.StatusNotFound, "label not found")
+	case errors.Is(err, app.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, "unauthorized")
+	case errors.Is(err, app.ErrInvalidLabel):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, label.ErrNameTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+func (h *LabelHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.labelService.ListLabels(r.Context(), userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load labels")
+			return
+		}
+		writeLabelError(w, err, "list labels")
+		return
+	}
+
+	// The dashboard loads labels as the options of the task form's label
+	// picker.
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		for _, l := range resp.Labels {
+			fmt.Fprintf(w, `<option value="%s">%s</option>`, l.Id.String(), html.EscapeString(l.Name))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *LabelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.SaveLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.labelService.CreateLabel(r.Context(), &req, userID)
+	if err != nil {
+		writeLabelError(w, err, "create label")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "labelsChanged")
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *LabelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	labelID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/labels/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid label id")
+		return
+	}
+
+	resp, err := h.labelService.GetLabel(r.Context(), labelID, userID)
+	if err != nil {
+		writeLabelError(w, err, "get label")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *LabelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	labelID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/labels/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid label id")
+		return
+	}
+
+	var req app.SaveLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.labelService.UpdateLabel(r.Context(), labelID, &req, userID)
+	if err != nil {
+		writeLabelError(w, err, "update label")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "labelsChanged")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *LabelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	labelID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/labels/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid label id")
+		return
+	}
+
+	if err := h.labelService.DeleteLabel(r.Context(), labelID, userID); err != nil {
+		writeLabelError(w, err, "delete label")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "labelsChanged")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseLabelIDs parses label ids given as repeated values, comma-separated
+// lists or both. Empty values are skipped.
+func parseLabelIDs(values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
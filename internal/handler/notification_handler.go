@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/notification"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	notificationService *app.NotificationService
+}
+
+func NewNotificationHandler(notificationService *app.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	query := r.URL.Query()
+	req := &app.ListNotificationsRequest{
+		UnreadOnly: query.Get("unread") == "true",
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = limit
+	}
+
+	resp, err := h.notificationService.ListNotifications(r.Context(), req, userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load notifications")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to list notifications")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		if len(resp.Notifications) == 0 {
+			fmt.Fprint(w, `<div class="empty-state"><p>No notifications</p></div>`)
+			return
+		}
+		for _, n := range resp.Notifications {
+			h.renderNotification(w, n)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/notifications/")
+	path = strings.TrimSuffix(path, "/read")
+	notificationID, err := uuid.Parse(path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err := h.notificationService.MarkNotificationRead(r.Context(), notificationID, userID); err != nil {
+		if err == app.ErrNotificationNotFound {
+			writeError(w, http.StatusNotFound, "notification not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to mark notification as read")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "notificationsRead")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	marked, err := h.notificationService.MarkAllNotificationsRead(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to mark notifications as read")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "notificationsRead")
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int64{"marked": marked})
+}
+
+func (h *NotificationHandler) renderNotification(w http.ResponseWriter, n *notification.Notification) {
+	readClass := ""
+	action := fmt.Sprintf(`<button class="btn btn-sm btn-outline" hx-post="/api/notifications/%s/read" hx-swap="none">Mark read</button>`, n.Id.String())
+	if n.IsRead() {
+		readClass = " notification-read"
+		action = ""
+	}
+
+	fmt.Fprintf(w, `
+	<div class="notification%s" id="notification-%s">
+		<div class="notification-info">
+			<h4>%s</h4>
+			<p>%s</p>
+			<span class="notification-time">%s</span>
+		</div>
+		%s
+	</div>`,
+		readClass,
+		n.Id.String(),
+		html.EscapeString(n.Title),
+		html.EscapeString(n.Message),
+		n.CreatedAt.Format("Jan 2, 2006 3:04 PM"),
+		action,
+	)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/apitoken"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type PersonalTokenHandler struct {
+	tokenService *app.PersonalTokenService
+}
+
+func NewPersonalTokenHandler(tokenService *app.PersonalTokenService) *PersonalTokenHandler {
+	return &PersonalTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// writePersonalTokenError maps personal token service errors to HTTP
+// responses.
+func writePersonalTokenError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrPersonalTokenNotFound):
+		writeError(w, http.StatusNotFound, "token not found")
+	case errors.Is(err, app.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, "unauthorized")
+	case errors.Is(err, apitoken.ErrInvalidToken):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+func (h *PersonalTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.tokenService.ListTokens(r.Context(), userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load tokens")
+			return
+		}
+		writePersonalTokenError(w, err, "list tokens")
+		return
+	}
+
+	// The settings page loads the list as rows of its token table.
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		if len(resp.Tokens) == 0 {
+			fmt.Fprint(w, `<div class="empty-state"><p>No personal access tokens</p></div>`)
+			return
+		}
+		for _, t := range resp.Tokens {
+			renderPersonalToken(w, t)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func renderPersonalToken(w http.ResponseWriter, t *apitoken.Token) {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = string(s)
+	}
+
+	expires := "Never expires"
+	if t.ExpiresAt != nil {
+		expires = "Expires " + t.ExpiresAt.Format("Jan 2, 2006")
+	}
+	lastUsed := "Never used"
+	if t.LastUsedAt != nil {
+		lastUsed = "Last used " + t.LastUsedAt.Format("Jan 2, 2006 3:04 PM")
+	}
+
+	fmt.Fprintf(w, `
+	<div class="token" id="token-%s">
+		<div class="token-info">
+			<h4>%s <code>%s…</code></h4>
+			<p>%s</p>
+			<span class="token-time">%s · %s</span>
+		</div>
+		<button class="btn btn-sm btn-danger" hx-delete="/api/tokens/%s" hx-swap="none" hx-confirm="Delete this token? Scripts using it will stop working.">Delete</button>
+	</div>`,
+		t.Id.String(),
+		html.EscapeString(t.Name),
+		html.EscapeString(t.Prefix),
+		html.EscapeString(strings.Join(scopes, ", ")),
+		expires,
+		lastUsed,
+		t.Id.String(),
+	)
+}
+
+func (h *PersonalTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	isHTMX := isHTMXRequest(r)
+
+	var req app.CreatePersonalTokenRequest
+	if isHTMX {
+		if err := r.ParseForm(); err != nil {
+			writeHTMXError(w, "Invalid form")
+			return
+		}
+		req.Name = r.FormValue("name")
+		for _, s := range r.Form["scopes"] {
+			req.Scopes = append(req.Scopes, apitoken.Scope(s))
+		}
+		if days := r.FormValue("expires_in_days"); days != "" {
+			req.ExpiresInDays, err = strconv.Atoi(days)
+			if err != nil {
+				writeHTMXError(w, "Invalid expiry")
+				return
+			}
+		}
+	} else {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	resp, err := h.tokenService.CreateToken(r.Context(), &req, userID)
+	if err != nil {
+		if isHTMX {
+			if errors.Is(err, apitoken.ErrInvalidToken) {
+				writeHTMXError(w, html.EscapeString(err.Error()))
+				return
+			}
+			writeHTMXError(w, "Failed to create the token. Please try again.")
+			return
+		}
+		writePersonalTokenError(w, err, "create token")
+		return
+	}
+
+	if isHTMX {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("HX-Trigger", "tokensChanged")
+		fmt.Fprintf(w, `<div class="alert alert-success">Token created. Copy it now, it will not be shown again: <code class="token-secret">%s</code></div>`, html.EscapeString(resp.Secret))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *PersonalTokenHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	tokenID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/tokens/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	if err := h.tokenService.DeleteToken(r.Context(), tokenID, userID); err != nil {
+		writePersonalTokenError(w, err, "delete token")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "tokensChanged")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
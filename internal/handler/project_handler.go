@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/project"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type ProjectHandler struct {
+	projectService *app.ProjectService
+}
+
+func NewProjectHandler(projectService *app.ProjectService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+	}
+}
+
+// writeProjectError maps project service errors to HTTP responses.
+func writeProjectError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrProjectNotFound):
+		writeError(w, http.StatusNotFound, "project not found")
+	case errors.Is(err, app.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, "unauthorized")
+	case errors.Is(err, app.ErrInvalidProject):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, project.ErrNameTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+// projectPath splits /api/projects/{id}/rest... into the project id and the
+// remaining path segments.
+func projectPath(r *http.Request) (uuid.UUID, []string, error) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/")
+	id, err := uuid.Parse(parts[0])
+	return id, parts[1:], err
+}
+
+func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	archived := r.URL.Query().Get("archived") == "true"
+	resp, err := h.projectService.ListProjects(r.Context(), archived, userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load projects")
+			return
+		}
+		writeProjectError(w, err, "list projects")
+		return
+	}
+
+	// The dashboard loads projects as the options of the task form's
+	// project picker and of the project filter.
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		for _, p := range resp.Projects {
+			fmt.Fprintf(w, `<option value="%s">%s</option>`, p.Id.String(), html.EscapeString(p.Name))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.SaveProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.projectService.CreateProject(r.Context(), &req, userID)
+	if err != nil {
+		writeProjectError(w, err, "create project")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "projectsChanged")
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	projectID, _, err := projectPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	resp, err := h.projectService.GetProject(r.Context(), projectID, userID)
+	if err != nil {
+		writeProjectError(w, err, "get project")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	projectID, _, err := projectPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	var req app.SaveProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.projectService.UpdateProject(r.Context(), projectID, &req, userID)
+	if err != nil {
+		writeProjectError(w, err, "update project")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "projectsChanged")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Archive archives a project and its tasks.
+func (h *ProjectHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+// Unarchive restores an archived project and its tasks.
+func (h *ProjectHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *ProjectHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	projectID, _, err := projectPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	var resp *app.ProjectResponse
+	if archived {
+		resp, err = h.projectService.ArchiveProject(r.Context(), projectID, userID)
+	} else {
+		resp, err = h.projectService.UnarchiveProject(r.Context(), projectID, userID)
+	}
+	if err != nil {
+		writeProjectError(w, err, "archive project")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "projectsChanged, tasksChanged")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	projectID, _, err := projectPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	if err := h.projectService.DeleteProject(r.Context(), projectID, userID); err != nil {
+		writeProjectError(w, err, "delete project")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "projectsChanged, tasksChanged")
+	w.WriteHeader(http.StatusNoContent)
+}
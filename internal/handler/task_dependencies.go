@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"taskhub/internal/app"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+func (h *TaskHandler) Start(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	// Starting a task with open blockers needs ?force=true.
+	req := &app.StartTaskRequest{Force: r.URL.Query().Get("force") == "true"}
+
+	resp, err := h.taskService.StartTask(r.Context(), taskID, req, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "start task")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "taskUpdated")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Blockers returns the tasks a task depends on, directly or transitively.
+func (h *TaskHandler) Blockers(w http.ResponseWriter, r *http.Request) {
+	h.dependencyGraph(w, r, app.Upstream)
+}
+
+// Dependents returns the tasks that depend on a task, directly or
+// transitively.
+func (h *TaskHandler) Dependents(w http.ResponseWriter, r *http.Request) {
+	h.dependencyGraph(w, r, app.Downstream)
+}
+
+func (h *TaskHandler) dependencyGraph(w http.ResponseWriter, r *http.Request, direction app.GraphDirection) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	resp, err := h.taskService.GetDependencyGraph(r.Context(), taskID, direction, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "load dependencies")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		for _, t := range resp.Tasks {
+			h.renderTaskCard(w, t)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TaskHandler) AddBlocker(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	var req app.AddDependencyRequest
+	if isHTMXRequest(r) {
+		blockerID, err := uuid.Parse(r.FormValue("blocked_by_id"))
+		if err != nil {
+			writeHTMXError(w, "Invalid blocking task")
+			return
+		}
+		req.BlockedByID = blockerID
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.taskService.AddDependency(r.Context(), taskID, &req, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "add dependency")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "taskUpdated")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *TaskHandler) RemoveBlocker(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, rest, err := taskSubpath(r)
+	if err != nil || len(rest) != 2 || rest[0] != "blockers" {
+		writeError(w, http.StatusBadRequest, "invalid dependency")
+		return
+	}
+	blockerID, err := uuid.Parse(rest[1])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid dependency")
+		return
+	}
+
+	if err := h.taskService.RemoveDependency(r.Context(), taskID, blockerID, userID); err != nil {
+		writeTaskError(w, r, err, "remove dependency")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "tasksChanged")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"net/http"
+	"strconv"
 	"strings"
 	"taskhub/internal/app"
 	"taskhub/internal/domains/task"
+	"taskhub/internal/domains/workflow"
 	"taskhub/pkg/middleware"
 	"time"
 
@@ -23,6 +27,46 @@ func NewTaskHandler(taskService *app.TaskService) *TaskHandler {
 	}
 }
 
+// taskErrorStatus maps the task service's rule violations to an HTTP status,
+// or returns 0 for other errors.
+func taskErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, task.ErrOpenSubtasks), errors.Is(err, task.ErrBlocked),
+		errors.Is(err, task.ErrDependencyCycle), errors.Is(err, task.ErrDependencyExists), errors.Is(err, app.ErrProjectArchived):
+		return http.StatusConflict
+	case errors.Is(err, task.ErrInvalidParent), errors.Is(err, task.ErrMaxDepth), errors.Is(err, task.ErrInvalidChecklistItem),
+		errors.Is(err, task.ErrInvalidDependency), errors.Is(err, task.ErrInvalidRRule), errors.Is(err, task.ErrRecurrenceDeadline),
+		errors.Is(err, app.ErrInvalidScope), errors.Is(err, app.ErrUnknownLabel), errors.Is(err, app.ErrUnknownProject):
+		return http.StatusBadRequest
+	case errors.Is(err, task.ErrChecklistItemNotFound), errors.Is(err, app.ErrDependencyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, workflow.ErrIllegalTransition), errors.Is(err, workflow.ErrUnknownStatus):
+		return http.StatusUnprocessableEntity
+	default:
+		return 0
+	}
+}
+
+// writeTaskError maps task service errors to HTTP responses, or to an inline
+// alert for HTMX requests.
+func writeTaskError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	status, message := http.StatusInternalServerError, "failed to "+action
+	switch {
+	case errors.Is(err, app.ErrTaskNotFound):
+		status, message = http.StatusNotFound, "task not found"
+	case errors.Is(err, app.ErrUnauthorized):
+		status, message = http.StatusForbidden, "unauthorized"
+	case taskErrorStatus(err) != 0:
+		status, message = taskErrorStatus(err), err.Error()
+	}
+
+	if isHTMXRequest(r) {
+		writeHTMXError(w, message)
+		return
+	}
+	writeError(w, status, message)
+}
+
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -49,6 +93,27 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 				req.Deadline = &deadline
 			}
 		}
+		if parentStr := r.FormValue("parent_id"); parentStr != "" {
+			parentID, err := uuid.Parse(parentStr)
+			if err != nil {
+				writeHTMXError(w, "Invalid parent task")
+				return
+			}
+			req.ParentID = &parentID
+		}
+		req.Recurrence = r.FormValue("recurrence")
+		if req.LabelIDs, err = parseLabelIDs(r.Form["label_ids"]); err != nil {
+			writeHTMXError(w, "Invalid label")
+			return
+		}
+		if projectStr := r.FormValue("project_id"); projectStr != "" {
+			projectID, err := uuid.Parse(projectStr)
+			if err != nil {
+				writeHTMXError(w, "Invalid project")
+				return
+			}
+			req.ProjectID = &projectID
+		}
 	} else {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid request body")
@@ -67,6 +132,10 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.taskService.CreateTask(r.Context(), &req, userID)
 	if err != nil {
+		if taskErrorStatus(err) != 0 {
+			writeTaskError(w, r, err, "create task")
+			return
+		}
 		if isHTMX {
 			writeHTMXError(w, "Failed to create task")
 			return
@@ -155,6 +224,29 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 				req.Deadline = &deadline
 			}
 		}
+		req.Force = r.FormValue("force") == "true"
+		if r.Form.Has("recurrence") {
+			rule := r.FormValue("recurrence")
+			req.Recurrence = &rule
+		}
+		req.Scope = app.EditScope(r.FormValue("scope"))
+		// The task form always carries the label picker, so an empty
+		// selection detaches every label.
+		labelIDs, err := parseLabelIDs(r.Form["label_ids"])
+		if err != nil {
+			writeHTMXError(w, "Invalid label")
+			return
+		}
+		req.LabelIDs = &labelIDs
+		// Likewise an empty project takes the task out of its project.
+		projectID := uuid.Nil
+		if projectStr := r.FormValue("project_id"); projectStr != "" {
+			if projectID, err = uuid.Parse(projectStr); err != nil {
+				writeHTMXError(w, "Invalid project")
+				return
+			}
+		}
+		req.ProjectID = &projectID
 	} else {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid request body")
@@ -180,6 +272,10 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusForbidden, "unauthorized")
 			return
 		}
+		if taskErrorStatus(err) != 0 {
+			writeTaskError(w, r, err, "update task")
+			return
+		}
 		if isHTMX {
 			writeHTMXError(w, "Failed to update task")
 			return
@@ -191,6 +287,9 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if isHTMX {
 		w.Header().Set("HX-Trigger", "taskUpdated")
 		h.renderTaskCard(w, resp.Task)
+		if resp.Next != nil {
+			h.renderTaskCard(w, resp.Next)
+		}
 		return
 	}
 
@@ -290,8 +389,63 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		req.Search = searchStr
 	}
 
+	// parent lists the subtasks of a task, or top-level tasks for "none".
+	switch parentStr := query.Get("parent"); parentStr {
+	case "":
+	case "none":
+		req.TopLevel = true
+	default:
+		parentID, err := uuid.Parse(parentStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid parent")
+			return
+		}
+		req.ParentID = &parentID
+	}
+
+	if projectStr := query.Get("project"); projectStr != "" {
+		projectID, err := uuid.Parse(projectStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid project")
+			return
+		}
+		req.ProjectID = &projectID
+	}
+	req.Archived = query.Get("archived") == "true"
+
+	if req.LabelsAny, err = parseLabelIDs(query["labels_any"]); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid labels_any")
+		return
+	}
+	if req.LabelsAll, err = parseLabelIDs(query["labels_all"]); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid labels_all")
+		return
+	}
+
+	req.Query = query.Get("q")
+	req.Sort = query.Get("sort")
+	req.Order = query.Get("order")
+	req.Cursor = query.Get("cursor")
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = limit
+	}
+
 	resp, err := h.taskService.ListTasks(r.Context(), req, userID)
 	if err != nil {
+		if errors.Is(err, task.ErrInvalidSort) || errors.Is(err, task.ErrInvalidCursor) || errors.Is(err, task.ErrInvalidQuery) {
+			if isHTMXRequest(r) {
+				writeHTMXError(w, err.Error())
+				return
+			}
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if isHTMXRequest(r) {
 			writeHTMXError(w, "Failed to load tasks")
 			return
@@ -301,8 +455,8 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if isHTMXRequest(r) {
-		if len(resp.Tasks) == 0 {
-			w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Type", "text/html")
+		if len(resp.Tasks) == 0 && req.Cursor == "" && req.ParentID == nil {
 			fmt.Fprint(w, `<div class="empty-state">
 				<h3>No tasks found</h3>
 				<p>Create your first task to get started!</p>
@@ -310,10 +464,20 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		w.Header().Set("Content-Type", "text/html")
 		for _, task := range resp.Tasks {
 			h.renderTaskCard(w, task)
 		}
+
+		// The sentinel loads the next page when it scrolls into view and
+		// replaces itself with it, giving the list infinite scroll.
+		if resp.NextCursor != "" {
+			next := r.URL.Query()
+			next.Set("cursor", resp.NextCursor)
+			fmt.Fprintf(w, `
+	<div class="load-more" hx-get="/api/tasks?%s" hx-trigger="revealed" hx-swap="outerHTML">
+		<div class="loading">Loading more tasks...</div>
+	</div>`, html.EscapeString(next.Encode()))
+		}
 		return
 	}
 
@@ -342,8 +506,12 @@ func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Completing a task with open subtasks needs ?force=true, which
+	// completes the subtasks as well.
+	req := &app.CompleteTaskRequest{Force: r.URL.Query().Get("force") == "true"}
+
 	isHTMX := isHTMXRequest(r)
-	resp, err := h.taskService.CompleteTask(r.Context(), taskID, userID)
+	resp, err := h.taskService.CompleteTask(r.Context(), taskID, req, userID)
 	if err != nil {
 		if err == app.ErrTaskNotFound {
 			if isHTMX {
@@ -361,6 +529,10 @@ func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusForbidden, "unauthorized")
 			return
 		}
+		if taskErrorStatus(err) != 0 {
+			writeTaskError(w, r, err, "complete task")
+			return
+		}
 		if isHTMX {
 			writeHTMXError(w, "Failed to complete task")
 			return
@@ -372,6 +544,10 @@ func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request) {
 	if isHTMX {
 		w.Header().Set("HX-Trigger", "taskCompleted")
 		h.renderTaskCard(w, resp.Task)
+		// The next occurrence of a recurring task follows the completed one.
+		if resp.Next != nil {
+			h.renderTaskCard(w, resp.Next)
+		}
 		return
 	}
 
@@ -381,7 +557,8 @@ func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request) {
 func (h *TaskHandler) renderTaskCard(w http.ResponseWriter, t *task.Task) {
 	w.Header().Set("Content-Type", "text/html")
 
-	statusClass := "badge-" + string(t.Status)
+	// Statuses vary between workflows, so the badge is colored by category.
+	statusClass := "badge-" + string(t.StatusCategory)
 	priorityClass := "badge-" + string(t.Priority)
 
 	deadlineText := ""
@@ -389,6 +566,12 @@ func (h *TaskHandler) renderTaskCard(w http.ResponseWriter, t *task.Task) {
 		deadlineText = t.Deadline.Format("Jan 2, 2006 3:04 PM")
 	}
 
+	// Search results show highlighted headlines, which are already escaped.
+	title, description := html.EscapeString(t.Title), html.EscapeString(t.Description)
+	if t.Match != nil {
+		title, description = t.Match.Title, t.Match.Snippet
+	}
+
 	fmt.Fprintf(w, `
 	<div class="task-card" id="task-%s">
 		<div class="task-info">
@@ -398,18 +581,25 @@ func (h *TaskHandler) renderTaskCard(w http.ResponseWriter, t *task.Task) {
 				<span class="badge %s">%s</span>
 				<span class="badge %s">%s</span>
 				%s
+				%s
+				%s
 			</div>
+			%s
+			%s
+			<div class="subtasks" id="subtasks-%s"></div>
 		</div>
 		<div class="task-actions">
 			%s
+			%s
+			<button class="btn btn-sm btn-outline" onclick="addSubtask('%s')">+ Subtask</button>
 			<button class="btn btn-sm btn-outline" onclick="editTask('%s')">Edit</button>
 			<button class="btn btn-sm btn-danger" hx-delete="/api/tasks/%s" hx-target="#task-%s" hx-swap="outerHTML">Delete</button>
 		</div>
 	</div>`,
 		t.Id.String(),
-		t.Title,
-		t.Description,
-		statusClass, string(t.Status),
+		title,
+		description,
+		statusClass, html.EscapeString(string(t.Status)),
 		priorityClass, string(t.Priority),
 		func() string {
 			if deadlineText != "" {
@@ -418,13 +608,86 @@ func (h *TaskHandler) renderTaskCard(w http.ResponseWriter, t *task.Task) {
 			return ""
 		}(),
 		func() string {
-			if t.Status != task.StatusDone {
-				return fmt.Sprintf(`<button class="btn btn-sm btn-success" hx-post="/api/tasks/%s/complete" hx-target="#task-%s" hx-swap="outerHTML">✓</button>`, t.Id.String(), t.Id.String())
+			badges := ""
+			if t.Blocked {
+				badges += fmt.Sprintf(`<span class="badge badge-blocked">Blocked by %d</span>`, len(t.BlockedBy))
 			}
-			return ""
+			if t.Recurrence != nil {
+				badges += fmt.Sprintf(`<span class="badge" title="%s">🔁 #%d</span>`, html.EscapeString(t.Recurrence.Rule), t.Recurrence.Occurrence)
+			}
+			return badges
+		}(),
+		renderLabels(t),
+		renderProgress(t),
+		renderChecklist(t),
+		t.Id.String(),
+		func() string {
+			if t.Closed() {
+				return ""
+			}
+			// Completing a parent with open subtasks completes them too,
+			// and a blocked task needs forcing, so ask first.
+			switch {
+			case t.Progress.Open():
+				return fmt.Sprintf(`<button class="btn btn-sm btn-success" hx-post="/api/tasks/%s/complete?force=true" hx-confirm="Complete this task and its open subtasks?" hx-target="#task-%s" hx-swap="outerHTML">✓</button>`, t.Id.String(), t.Id.String())
+			case t.Blocked:
+				return fmt.Sprintf(`<button class="btn btn-sm btn-success" hx-post="/api/tasks/%s/complete?force=true" hx-confirm="This task is blocked by open tasks. Complete it anyway?" hx-target="#task-%s" hx-swap="outerHTML">✓</button>`, t.Id.String(), t.Id.String())
+			}
+			return fmt.Sprintf(`<button class="btn btn-sm btn-success" hx-post="/api/tasks/%s/complete" hx-target="#task-%s" hx-swap="outerHTML">✓</button>`, t.Id.String(), t.Id.String())
+		}(),
+		func() string {
+			if t.Progress == nil {
+				return ""
+			}
+			return fmt.Sprintf(`<button class="btn btn-sm btn-outline" hx-get="/api/tasks?parent=%s" hx-include="this" hx-target="#subtasks-%s" hx-swap="innerHTML">Subtasks</button>`, t.Id.String(), t.Id.String())
 		}(),
 		t.Id.String(),
 		t.Id.String(),
 		t.Id.String(),
+		t.Id.String(),
 	)
 }
+
+// renderLabels renders the task's labels as colored chips. Label colors are
+// validated #rrggbb values, so they are safe in the style attribute.
+func renderLabels(t *task.Task) string {
+	var b strings.Builder
+	for _, l := range t.Labels {
+		fmt.Fprintf(&b, `<span class="label-chip" style="background: %s">%s</span>`, l.Color, html.EscapeString(l.Name))
+	}
+	return b.String()
+}
+
+// renderProgress renders a progress bar over the task's subtasks.
+func renderProgress(t *task.Task) string {
+	if t.Progress == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(`<div class="task-progress">
+				<div class="progress-bar"><span style="width: %d%%"></span></div>
+				<small>%d/%d subtasks done</small>
+			</div>`, t.Progress.Percent, t.Progress.Done, t.Progress.Total)
+}
+
+// renderChecklist renders the task's checklist and a field to add items.
+// Toggling or adding an item swaps in the updated card.
+func renderChecklist(t *task.Task) string {
+	var b strings.Builder
+	b.WriteString(`<ul class="checklist">`)
+	for _, item := range t.Checklist {
+		checked := ""
+		if item.Done {
+			checked = " checked"
+		}
+		fmt.Fprintf(&b, `
+				<li><label><input type="checkbox" hx-patch="/api/tasks/%s/checklist/%s" hx-vals='{"done": "%t"}' hx-target="#task-%s" hx-swap="outerHTML"%s> %s</label></li>`,
+			t.Id.String(), item.ID.String(), !item.Done, t.Id.String(), checked, html.EscapeString(item.Text))
+	}
+	fmt.Fprintf(&b, `
+				<li><form class="checklist-add" hx-post="/api/tasks/%s/checklist" hx-target="#task-%s" hx-swap="outerHTML"><input type="text" name="text" placeholder="Add a step" required></form></li>`,
+		t.Id.String(), t.Id.String())
+	b.WriteString(`</ul>`)
+
+	return b.String()
+}
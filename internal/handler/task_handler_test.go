@@ -202,3 +202,51 @@ func TestTaskHandler_Complete_InvalidTaskID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestTaskHandler_Move_MethodNotAllowed(t *testing.T) {
+	handler := NewTaskHandler(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/123/move", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Move(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestTaskHandler_UpdateChecklistItem_InvalidPath(t *testing.T) {
+	handler := NewTaskHandler(nil)
+
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, uuid.New().String())
+	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/"+uuid.New().String()+"/checklist/not-an-id", nil)
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	handler.UpdateChecklistItem(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTaskHandler_Start_MethodNotAllowed(t *testing.T) {
+	handler := NewTaskHandler(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/123/start", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Start(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestTaskHandler_RemoveBlocker_InvalidPath(t *testing.T) {
+	handler := NewTaskHandler(nil)
+
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, uuid.New().String())
+	req := httptest.NewRequest(http.MethodDelete, "/api/tasks/"+uuid.New().String()+"/blockers/not-an-id", nil)
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	handler.RemoveBlocker(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"taskhub/internal/domains/task"
+	"taskhub/pkg/middleware"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// History returns the changes made to a task, oldest first.
+func (h *TaskHandler) History(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	resp, err := h.taskService.GetTaskHistory(r.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "load history")
+		return
+	}
+
+	// The task modal shows the history as a timeline, newest first.
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		if len(resp.History) == 0 {
+			fmt.Fprint(w, `<li class="timeline-entry">No activity yet</li>`)
+			return
+		}
+		for i := len(resp.History) - 1; i >= 0; i-- {
+			c := resp.History[i]
+			fmt.Fprintf(w, `<li class="timeline-entry"><time datetime="%s">%s</time> %s</li>`,
+				c.CreatedAt.Format(time.RFC3339), c.CreatedAt.Format("Jan 2, 2006 3:04 PM"), describeChange(c, userID))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// describeChange renders a history entry as an escaped sentence for the
+// timeline.
+func describeChange(c *task.Change, userID uuid.UUID) string {
+	actor := "Someone else"
+	if c.ActorID == userID {
+		actor = "You"
+	}
+
+	var what string
+	switch {
+	case c.Action == task.ActionCreated:
+		what = "created the task"
+	case c.Action == task.ActionDeleted:
+		what = "deleted the task"
+	case c.Field == task.FieldBlockedBy && c.Old == nil:
+		what = "added a blocker"
+	case c.Field == task.FieldBlockedBy:
+		what = "removed a blocker"
+	case c.Field == "archived_at" && c.New == nil:
+		what = "unarchived the task"
+	case c.Field == "archived_at":
+		what = "archived the task"
+	case c.Field == "status":
+		from, to := historyValue(c.Old), historyValue(c.New)
+		what = fmt.Sprintf("moved the task from %s to %s", strings.ReplaceAll(from, "_", " "), strings.ReplaceAll(to, "_", " "))
+	case c.Field == "title" || c.Field == "priority" || c.Field == "deadline" || c.Field == "recurrence":
+		what = fmt.Sprintf("changed the %s from %s to %s", c.Field, historyValue(c.Old), historyValue(c.New))
+	default:
+		what = "changed the " + strings.TrimSuffix(c.Field, "_id")
+	}
+
+	return html.EscapeString(actor + " " + what)
+}
+
+// historyValue formats a field value recorded in a task's history.
+func historyValue(raw json.RawMessage) string {
+	var s string
+	if raw == nil || json.Unmarshal(raw, &s) != nil || s == "" {
+		return "none"
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Format("Jan 2, 2006 3:04 PM")
+	}
+	return s
+}
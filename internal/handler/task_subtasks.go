@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+// taskSubpath splits /api/tasks/{id}/rest... into the task id and the
+// remaining path segments.
+func taskSubpath(r *http.Request) (uuid.UUID, []string, error) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/tasks/"), "/")
+	id, err := uuid.Parse(parts[0])
+	return id, parts[1:], err
+}
+
+func (h *TaskHandler) Move(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	var req app.MoveTaskRequest
+	if isHTMXRequest(r) {
+		if parentStr := r.FormValue("parent_id"); parentStr != "" {
+			parentID, err := uuid.Parse(parentStr)
+			if err != nil {
+				writeHTMXError(w, "Invalid parent task")
+				return
+			}
+			req.ParentID = &parentID
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.taskService.MoveTask(r.Context(), taskID, &req, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "move task")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "tasksChanged")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TaskHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, _, err := taskSubpath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	var req app.AddChecklistItemRequest
+	if isHTMXRequest(r) {
+		req.Text = r.FormValue("text")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.taskService.AddChecklistItem(r.Context(), taskID, &req, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "add checklist item")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "taskUpdated")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *TaskHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, itemID, ok := checklistItemPath(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid checklist item")
+		return
+	}
+
+	var req app.UpdateChecklistItemRequest
+	if isHTMXRequest(r) {
+		r.ParseForm()
+		if r.Form.Has("text") {
+			text := r.FormValue("text")
+			req.Text = &text
+		}
+		if r.Form.Has("done") {
+			done := r.FormValue("done") == "true"
+			req.Done = &done
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.taskService.UpdateChecklistItem(r.Context(), taskID, itemID, &req, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "update checklist item")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "taskUpdated")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *TaskHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	taskID, itemID, ok := checklistItemPath(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid checklist item")
+		return
+	}
+
+	resp, err := h.taskService.DeleteChecklistItem(r.Context(), taskID, itemID, userID)
+	if err != nil {
+		writeTaskError(w, r, err, "delete checklist item")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Trigger", "taskUpdated")
+		h.renderTaskCard(w, resp.Task)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// checklistItemPath parses /api/tasks/{id}/checklist/{itemID}.
+func checklistItemPath(r *http.Request) (taskID, itemID uuid.UUID, ok bool) {
+	taskID, rest, err := taskSubpath(r)
+	if err != nil || len(rest) != 2 || rest[0] != "checklist" {
+		return taskID, itemID, false
+	}
+
+	itemID, err = uuid.Parse(rest[1])
+	return taskID, itemID, err == nil
+}
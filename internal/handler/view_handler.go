@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/task"
+	"taskhub/internal/domains/view"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type ViewHandler struct {
+	viewService *app.ViewService
+}
+
+func NewViewHandler(viewService *app.ViewService) *ViewHandler {
+	return &ViewHandler{
+		viewService: viewService,
+	}
+}
+
+// writeViewError maps view service errors to HTTP responses.
+func writeViewError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrViewNotFound):
+		writeError(w, http.StatusNotFound, "view not found")
+	case errors.Is(err, app.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, "unauthorized")
+	case errors.Is(err, app.ErrInvalidView), errors.Is(err, task.ErrInvalidQuery):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, view.ErrNameTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+func (h *ViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.viewService.ListViews(r.Context(), userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load views")
+			return
+		}
+		writeViewError(w, err, "list views")
+		return
+	}
+
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		for _, v := range resp.Views {
+			h.renderView(w, v)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.SaveViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.viewService.CreateView(r.Context(), &req, userID)
+	if err != nil {
+		writeViewError(w, err, "create view")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "viewsChanged")
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *ViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	viewID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/views/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid view id")
+		return
+	}
+
+	resp, err := h.viewService.GetView(r.Context(), viewID, userID)
+	if err != nil {
+		writeViewError(w, err, "get view")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	viewID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/views/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid view id")
+		return
+	}
+
+	var req app.SaveViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.viewService.UpdateView(r.Context(), viewID, &req, userID)
+	if err != nil {
+		writeViewError(w, err, "update view")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "viewsChanged")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *ViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	viewID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/views/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid view id")
+		return
+	}
+
+	if err := h.viewService.DeleteView(r.Context(), viewID, userID); err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to delete view")
+			return
+		}
+		writeViewError(w, err, "delete view")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "viewsChanged")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderView renders a smart list chip that loads the view's tasks into the
+// task list.
+func (h *ViewHandler) renderView(w http.ResponseWriter, v *view.View) {
+	fmt.Fprintf(w, `
+	<span class="view-chip" id="view-%s">
+		<button class="btn btn-sm btn-outline" type="button" data-query="%s" onclick="applyView(this)">%s</button>
+		<button class="btn btn-sm btn-link" type="button" title="Delete view" hx-delete="/api/views/%s" hx-confirm="Delete this view?" hx-swap="none">&times;</button>
+	</span>`,
+		v.Id.String(),
+		html.EscapeString(v.Query),
+		html.EscapeString(v.Name),
+		v.Id.String(),
+	)
+}
@@ -4,20 +4,36 @@ import (
 	"html/template"
 	"net/http"
 	"path/filepath"
+	"taskhub/internal/domains/apitoken"
 )
 
 type WebHandler struct {
-	templates *template.Template
+	// pages holds each page parsed with base.html on its own, since every
+	// page defines the same blocks.
+	pages map[string]*template.Template
 }
 
 func NewWebHandler(templatesDir string) (*WebHandler, error) {
-	tmpl, err := template.ParseGlob(filepath.Join(templatesDir, "*.html"))
+	base := filepath.Join(templatesDir, "base.html")
+	files, err := filepath.Glob(filepath.Join(templatesDir, "*.html"))
 	if err != nil {
 		return nil, err
 	}
 
+	pages := make(map[string]*template.Template)
+	for _, file := range files {
+		if file == base {
+			continue
+		}
+		tmpl, err := template.ParseFiles(base, file)
+		if err != nil {
+			return nil, err
+		}
+		pages[filepath.Base(file)] = tmpl
+	}
+
 	return &WebHandler{
-		templates: tmpl,
+		pages: pages,
 	}, nil
 }
 
@@ -39,6 +55,41 @@ func (h *WebHandler) Register(w http.ResponseWriter, r *http.Request) {
 	h.render(w, "register.html", nil)
 }
 
+func (h *WebHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.render(w, "forgot_password.html", nil)
+}
+
+// tokenPage is the data of pages opened from a link with a token in its
+// query string.
+type tokenPage struct {
+	Token string
+}
+
+func (h *WebHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.render(w, "reset_password.html", tokenPage{Token: r.URL.Query().Get("token")})
+}
+
+// VerifyEmail renders a page that posts the link's token once loaded, so
+// mail scanners that fetch the link do not use it up.
+func (h *WebHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.render(w, "verify_email.html", tokenPage{Token: r.URL.Query().Get("token")})
+}
+
 func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -48,9 +99,29 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	h.render(w, "dashboard.html", nil)
 }
 
+// tokensPage is the data of the personal access token settings page.
+type tokensPage struct {
+	Scopes []apitoken.Scope
+}
+
+func (h *WebHandler) Tokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.render(w, "tokens.html", tokensPage{Scopes: apitoken.Scopes})
+}
+
 func (h *WebHandler) render(w http.ResponseWriter, name string, data interface{}) {
+	tmpl, ok := h.pages[name]
+	if !ok {
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := h.templates.ExecuteTemplate(w, name, data); err != nil {
+	if err := tmpl.ExecuteTemplate(w, name, data); err != nil {
 		http.Error(w, "Error rendering template", http.StatusInternalServerError)
 	}
 }
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebHandler_RendersEachPage(t *testing.T) {
+	h, err := NewWebHandler("../../web/templates")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+		want    string
+	}{
+		{"login", h.Login, "/login", "Sign In"},
+		{"register", h.Register, "/register", "Create Account"},
+		{"forgot password", h.ForgotPassword, "/forgot-password", "Send Reset Link"},
+		{"reset password", h.ResetPassword, "/reset-password?token=abc", `name="token" value="abc"`},
+		{"verify email", h.VerifyEmail, "/verify-email?token=abc", `name="token" value="abc"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
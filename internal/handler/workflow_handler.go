@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/workflow"
+	"taskhub/pkg/middleware"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowHandler struct {
+	workflowService *app.WorkflowService
+}
+
+func NewWorkflowHandler(workflowService *app.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// writeWorkflowError maps workflow service errors to HTTP responses.
+func writeWorkflowError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, app.ErrWorkflowNotFound):
+		writeError(w, http.StatusNotFound, "workflow not found")
+	case errors.Is(err, app.ErrUnauthorized):
+		writeError(w, http.StatusForbidden, "unauthorized")
+	case errors.Is(err, workflow.ErrInvalidWorkflow):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, app.ErrUnknownProject):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, workflow.ErrScopeTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
+
+func (h *WorkflowHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	resp, err := h.workflowService.ListWorkflows(r.Context(), userID)
+	if err != nil {
+		writeWorkflowError(w, err, "list workflows")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Effective returns the workflow tasks in the project_id query parameter's
+// project follow, or those outside projects without it.
+func (h *WorkflowHandler) Effective(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var projectID *uuid.UUID
+	if projectStr := r.URL.Query().Get("project_id"); projectStr != "" {
+		id, err := uuid.Parse(projectStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid project id")
+			return
+		}
+		projectID = &id
+	}
+
+	resp, err := h.workflowService.EffectiveWorkflow(r.Context(), projectID, userID)
+	if err != nil {
+		if isHTMXRequest(r) {
+			writeHTMXError(w, "Failed to load statuses")
+			return
+		}
+		writeWorkflowError(w, err, "load workflow")
+		return
+	}
+
+	// The dashboard loads the workflow as the options of the task form's
+	// status picker.
+	if isHTMXRequest(r) {
+		w.Header().Set("Content-Type", "text/html")
+		for _, s := range resp.Workflow.States {
+			name := html.EscapeString(string(s.Name))
+			fmt.Fprintf(w, `<option value="%s">%s</option>`, name, strings.ReplaceAll(name, "_", " "))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *WorkflowHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	var req app.SaveWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.workflowService.CreateWorkflow(r.Context(), &req, userID)
+	if err != nil {
+		writeWorkflowError(w, err, "create workflow")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	workflowID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/workflows/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid workflow id")
+		return
+	}
+
+	resp, err := h.workflowService.GetWorkflow(r.Context(), workflowID, userID)
+	if err != nil {
+		writeWorkflowError(w, err, "get workflow")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	workflowID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/workflows/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid workflow id")
+		return
+	}
+
+	var req app.SaveWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := h.workflowService.UpdateWorkflow(r.Context(), workflowID, &req, userID)
+	if err != nil {
+		writeWorkflowError(w, err, "update workflow")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *WorkflowHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	userIDStr := middleware.GetUserIDFromContext(r.Context())
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid user")
+		return
+	}
+
+	workflowID, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/workflows/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid workflow id")
+		return
+	}
+
+	if err := h.workflowService.DeleteWorkflow(r.Context(), workflowID, userID); err != nil {
+		writeWorkflowError(w, err, "delete workflow")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
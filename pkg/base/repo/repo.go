@@ -1,18 +1,129 @@
 package repo
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"sync"
 
-type BaseRepository[T any] interface {
-	FindById(ctx context.Context, id string) (*T, error)
+	"github.com/google/uuid"
+)
+
+type Entity interface {
+	GetId() uuid.UUID
+}
+
+type BaseRepository[T Entity] interface {
+	Create(ctx context.Context, entity T) (T, error)
+	FindById(ctx context.Context, id uuid.UUID) (T, error)
+	FindAll(ctx context.Context, match func(T) bool) ([]T, error)
+	Update(ctx context.Context, entity T) (T, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// MemoryRepository is a thread-safe BaseRepository backed by a map. Entities
+// are cloned on the way in and out so callers never share state with the
+// store, mirroring the copy semantics of a database-backed repository.
+type MemoryRepository[T Entity] struct {
+	mu       sync.RWMutex
+	entities map[uuid.UUID]T
+	order    []uuid.UUID
+	clone    func(T) T
+}
+
+func NewMemoryRepository[T Entity](clone func(T) T) *MemoryRepository[T] {
+	return &MemoryRepository[T]{
+		entities: make(map[uuid.UUID]T),
+		clone:    clone,
+	}
+}
+
+func (r *MemoryRepository[T]) Create(ctx context.Context, entity T) (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := entity.GetId()
+	if _, ok := r.entities[id]; !ok {
+		r.order = append(r.order, id)
+	}
+	r.entities[id] = r.clone(entity)
+
+	return entity, nil
+}
+
+func (r *MemoryRepository[T]) FindById(ctx context.Context, id uuid.UUID) (T, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.entities[id]
+	if !ok {
+		var zero T
+		return zero, nil
+	}
+
+	return r.clone(entity), nil
 }
 
-type baseRepository[T any] struct {
+// FindAll returns the entities accepted by match in insertion order. A nil
+// match returns every entity.
+func (r *MemoryRepository[T]) FindAll(ctx context.Context, match func(T) bool) ([]T, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []T
+	for _, id := range r.order {
+		entity := r.entities[id]
+		if match != nil && !match(entity) {
+			continue
+		}
+		result = append(result, r.clone(entity))
+	}
+
+	return result, nil
 }
 
-func NewBaseRepository[T any]() BaseRepository[T] {
-	return &baseRepository[T]{}
+func (r *MemoryRepository[T]) Update(ctx context.Context, entity T) (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := entity.GetId()
+	if _, ok := r.entities[id]; !ok {
+		var zero T
+		return zero, sql.ErrNoRows
+	}
+	r.entities[id] = r.clone(entity)
+
+	return entity, nil
 }
 
-func (b *baseRepository[T]) FindById(ctx context.Context, id string) (*T, error) {
-	return nil, nil
+// Modify applies fn to the stored entity under the write lock, which lets
+// callers perform read-modify-write updates atomically.
+func (r *MemoryRepository[T]) Modify(ctx context.Context, id uuid.UUID, fn func(T) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entity, ok := r.entities[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	return fn(entity)
+}
+
+func (r *MemoryRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entities[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.entities, id)
+
+	for i, existing := range r.order {
+		if existing == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
 }
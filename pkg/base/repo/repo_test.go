@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEntity struct {
+	Id   uuid.UUID
+	Name string
+}
+
+func (e *testEntity) GetId() uuid.UUID {
+	return e.Id
+}
+
+func cloneTestEntity(e *testEntity) *testEntity {
+	c := *e
+	return &c
+}
+
+func TestMemoryRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRepository(cloneTestEntity)
+
+	entity := &testEntity{Id: uuid.New(), Name: "first"}
+	_, err := r.Create(ctx, entity)
+	assert.NoError(t, err)
+
+	found, err := r.FindById(ctx, entity.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", found.Name)
+
+	entity.Name = "second"
+	_, err = r.Update(ctx, entity)
+	assert.NoError(t, err)
+
+	found, _ = r.FindById(ctx, entity.Id)
+	assert.Equal(t, "second", found.Name)
+
+	assert.NoError(t, r.Delete(ctx, entity.Id))
+
+	found, err = r.FindById(ctx, entity.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestMemoryRepository_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRepository(cloneTestEntity)
+
+	entity := &testEntity{Id: uuid.New(), Name: "original"}
+	r.Create(ctx, entity)
+
+	entity.Name = "mutated"
+	found, _ := r.FindById(ctx, entity.Id)
+	assert.Equal(t, "original", found.Name)
+
+	found.Name = "mutated again"
+	found, _ = r.FindById(ctx, entity.Id)
+	assert.Equal(t, "original", found.Name)
+}
+
+func TestMemoryRepository_NotFound(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRepository(cloneTestEntity)
+
+	_, err := r.Update(ctx, &testEntity{Id: uuid.New()})
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	err = r.Delete(ctx, uuid.New())
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	err = r.Modify(ctx, uuid.New(), func(e *testEntity) error { return nil })
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestMemoryRepository_FindAll(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRepository(cloneTestEntity)
+
+	r.Create(ctx, &testEntity{Id: uuid.New(), Name: "a"})
+	r.Create(ctx, &testEntity{Id: uuid.New(), Name: "b"})
+	r.Create(ctx, &testEntity{Id: uuid.New(), Name: "a"})
+
+	all, err := r.FindAll(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+	assert.Equal(t, "a", all[0].Name)
+	assert.Equal(t, "b", all[1].Name)
+
+	matched, err := r.FindAll(ctx, func(e *testEntity) bool { return e.Name == "a" })
+	assert.NoError(t, err)
+	assert.Len(t, matched, 2)
+}
+
+func TestMemoryRepository_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	r := NewMemoryRepository(cloneTestEntity)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := &testEntity{Id: uuid.New()}
+			r.Create(ctx, e)
+			r.FindById(ctx, e.Id)
+			r.FindAll(ctx, nil)
+		}()
+	}
+	wg.Wait()
+
+	all, _ := r.FindAll(ctx, nil)
+	assert.Len(t, all, 50)
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrNoConnection = errors.New("no database connection")
+
+// AdvisoryLocker elects a single leader across replicas using a Postgres
+// session-level advisory lock held on a dedicated connection.
+type AdvisoryLocker struct {
+	conn *sql.DB
+	key  int64
+}
+
+func NewAdvisoryLocker(conn *sql.DB, key int64) *AdvisoryLocker {
+	return &AdvisoryLocker{conn: conn, key: key}
+}
+
+func (l *AdvisoryLocker) TryLock(ctx context.Context) (func(), bool, error) {
+	if l.conn == nil {
+		return nil, false, ErrNoConnection
+	}
+
+	conn, err := l.conn.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, l.key)
+		conn.Close()
+	}
+
+	return release, true, nil
+}
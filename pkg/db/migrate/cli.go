@@ -0,0 +1,67 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = "usage: taskhub migrate up|down|status|to N"
+
+var ErrUsage = errors.New(usage)
+
+// Run executes a `taskhub migrate` subcommand.
+func Run(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "up":
+		if err := m.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if err := m.Down(ctx); err != nil {
+			return err
+		}
+	case "to":
+		if len(args) != 2 {
+			return ErrUsage
+		}
+		target, err := strconv.Atoi(args[1])
+		if err != nil || target < 0 {
+			return fmt.Errorf("invalid target version %q", args[1])
+		}
+		if err := m.To(ctx, target); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return ErrUsage
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	return PrintStatus(out, statuses)
+}
+
+func PrintStatus(out io.Writer, statuses []*MigrationStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,396 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"taskhub/config"
+	"taskhub/pkg/db"
+	"taskhub/pkg/logger"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+var MigrateModule = fx.Module(
+	"migrate",
+	fx.Provide(NewMigrator),
+	fx.Invoke(registerStartupHook),
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+const (
+	// ModeOff skips any schema handling on startup.
+	ModeOff = "off"
+	// ModeCheck refuses to start while migrations are pending.
+	ModeCheck = "check"
+	// ModeAuto applies pending migrations on startup.
+	ModeAuto = "auto"
+)
+
+// lockKey identifies the Postgres advisory lock held while migrating so that
+// concurrent gateway replicas apply migrations one at a time.
+const lockKey int64 = 0x7461736b687562 // "taskhub"
+
+var (
+	ErrSchemaBehind   = errors.New("database schema is behind, run `taskhub migrate up`")
+	ErrNoDownScript   = errors.New("migration has no down script")
+	ErrUnknownVersion = errors.New("database has a migration this binary does not know")
+	ErrNoConnection   = errors.New("no database connection")
+)
+
+var filenamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	conn       *sql.DB
+	logger     *logger.Logger
+	mode       string
+	migrations []*Migration
+}
+
+func NewMigrator(config *config.Config, logger *logger.Logger) (*Migrator, error) {
+	migrations, err := Load(embedded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		conn:       db.NewDB(config).GetConnection(),
+		logger:     logger,
+		mode:       config.DB.Migrate,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads NNNN_name.up.sql / NNNN_name.down.sql pairs from fsys, searching
+// the migrations directory, and returns them ordered by version.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	files, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		match := filenamePattern.FindStringSubmatch(path.Base(file))
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration filename %q", file)
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the highest known migration version.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Plan computes which migrations to apply and which to revert to reach
+// target, given the set of applied versions.
+func Plan(migrations []*Migration, applied map[int]bool, target int) (up []*Migration, down []*Migration, err error) {
+	known := map[int]bool{}
+	for _, m := range migrations {
+		known[m.Version] = true
+		if m.Version <= target && !applied[m.Version] {
+			up = append(up, m)
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version > target && applied[m.Version] {
+			if m.Down == "" {
+				return nil, nil, fmt.Errorf("%w: %d_%s", ErrNoDownScript, m.Version, m.Name)
+			}
+			down = append(down, m)
+		}
+	}
+
+	for version := range applied {
+		if !known[version] && version > target {
+			return nil, nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+	}
+
+	return up, down, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	if m.conn == nil {
+		return nil, ErrNoConnection
+	}
+
+	if err := ensureTable(ctx, m.conn); err != nil {
+		return nil, err
+	}
+
+	appliedAt, err := appliedVersions(ctx, m.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*MigrationStatus
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			at := at
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the number of known migrations not yet applied.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// Check returns ErrSchemaBehind when migrations are pending.
+func (m *Migrator) Check(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w (%d pending)", ErrSchemaBehind, pending)
+	}
+	return nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		appliedAt, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		current := 0
+		for version := range appliedAt {
+			if version > current {
+				current = version
+			}
+		}
+		if current == 0 {
+			return nil
+		}
+
+		previous := 0
+		for version := range appliedAt {
+			if version < current && version > previous {
+				previous = version
+			}
+		}
+
+		return m.migrate(ctx, conn, appliedAt, previous)
+	})
+}
+
+// To migrates up or down until target is the latest applied version.
+func (m *Migrator) To(ctx context.Context, target int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		appliedAt, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		return m.migrate(ctx, conn, appliedAt, target)
+	})
+}
+
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, appliedAt map[int]time.Time, target int) error {
+	applied := make(map[int]bool, len(appliedAt))
+	for version := range appliedAt {
+		applied[version] = true
+	}
+
+	up, down, err := Plan(m.migrations, applied, target)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range down {
+		m.logger.Info("reverting migration", "version", migration.Version, "name", migration.Name)
+		if err := run(ctx, conn, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+			return fmt.Errorf("revert %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	for _, migration := range up {
+		m.logger.Info("applying migration", "version", migration.Version, "name", migration.Name)
+		if err := run(ctx, conn, migration.Up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name); err != nil {
+			return fmt.Errorf("apply %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	if m.conn == nil {
+		return ErrNoConnection
+	}
+
+	conn, err := m.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func ensureTable(ctx context.Context, conn execer) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version BIGINT PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+    )`)
+	return err
+}
+
+func appliedVersions(ctx context.Context, conn execer) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func run(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func registerStartupHook(lc fx.Lifecycle, m *Migrator) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			switch m.mode {
+			case ModeAuto:
+				return m.Up(ctx)
+			case ModeCheck:
+				return m.Check(ctx)
+			default:
+				return nil
+			}
+		},
+	})
+}
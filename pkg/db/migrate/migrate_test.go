@@ -0,0 +1,148 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(embedded)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Up, "migration %d has no up script", m.Version)
+		assert.NotEmpty(t, m.Down, "migration %d has no down script", m.Version)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0010_second.up.sql":  {Data: []byte("SELECT 2;")},
+		"migrations/0002_first.up.sql":   {Data: []byte("SELECT 1;")},
+		"migrations/0002_first.down.sql": {Data: []byte("SELECT -1;")},
+	}
+
+	migrations, err := Load(fsys)
+
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, 2, migrations[0].Version)
+	assert.Equal(t, "SELECT -1;", migrations[0].Down)
+	assert.Equal(t, 10, migrations[1].Version)
+	assert.Empty(t, migrations[1].Down)
+}
+
+func TestLoad_InvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "bad filename",
+			fsys: fstest.MapFS{"migrations/init.sql": {Data: []byte("SELECT 1;")}},
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{"migrations/0001_init.down.sql": {Data: []byte("SELECT 1;")}},
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"migrations/0001_init.up.sql":  {Data: []byte("SELECT 1;")},
+				"migrations/0001_other.up.sql": {Data: []byte("SELECT 1;")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func testMigrations() []*Migration {
+	return []*Migration{
+		{Version: 1, Name: "one", Up: "up1", Down: "down1"},
+		{Version: 2, Name: "two", Up: "up2", Down: "down2"},
+		{Version: 3, Name: "three", Up: "up3"},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	up, down, err := Plan(testMigrations(), map[int]bool{1: true}, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, down)
+	assert.Len(t, up, 2)
+	assert.Equal(t, 2, up[0].Version)
+	assert.Equal(t, 3, up[1].Version)
+
+	up, down, err = Plan(testMigrations(), map[int]bool{1: true, 2: true}, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, up)
+	assert.Len(t, down, 2)
+	assert.Equal(t, 2, down[0].Version)
+	assert.Equal(t, 1, down[1].Version)
+}
+
+func TestPlan_Errors(t *testing.T) {
+	_, _, err := Plan(testMigrations(), map[int]bool{1: true, 2: true, 3: true}, 2)
+	assert.True(t, errors.Is(err, ErrNoDownScript))
+
+	_, _, err = Plan(testMigrations(), map[int]bool{1: true, 9: true}, 3)
+	assert.True(t, errors.Is(err, ErrUnknownVersion))
+}
+
+func TestMigrator_NoConnection(t *testing.T) {
+	m := &Migrator{migrations: testMigrations()}
+	ctx := context.Background()
+
+	assert.Equal(t, 3, m.Latest())
+	assert.Equal(t, ErrNoConnection, m.Up(ctx))
+	assert.Equal(t, ErrNoConnection, m.Down(ctx))
+	assert.Equal(t, ErrNoConnection, m.Check(ctx))
+}
+
+func TestRun_Usage(t *testing.T) {
+	m := &Migrator{migrations: testMigrations()}
+	ctx := context.Background()
+
+	assert.Equal(t, ErrUsage, Run(ctx, m, nil, &bytes.Buffer{}))
+	assert.Equal(t, ErrUsage, Run(ctx, m, []string{"sideways"}, &bytes.Buffer{}))
+	assert.Equal(t, ErrUsage, Run(ctx, m, []string{"to"}, &bytes.Buffer{}))
+	assert.Error(t, Run(ctx, m, []string{"to", "abc"}, &bytes.Buffer{}))
+}
+
+func TestPrintStatus(t *testing.T) {
+	appliedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var out bytes.Buffer
+
+	err := PrintStatus(&out, []*MigrationStatus{
+		{Version: 1, Name: "init", AppliedAt: &appliedAt},
+		{Version: 2, Name: "next"},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "0001")
+	assert.Contains(t, out.String(), "init")
+	assert.Contains(t, out.String(), "2026-01-02T03:04:05Z")
+	assert.Contains(t, out.String(), "pending")
+}
+
+func TestMigrateModule(t *testing.T) {
+	assert.NotNil(t, MigrateModule)
+}
@@ -36,4 +36,4 @@ CREATE INDEX IF NOT EXISTS idx_tasks_deadline ON tasks(deadline);
 CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at);
-CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,8 @@
+-- One row per reminder sent, so each threshold fires once per deadline
+CREATE TABLE IF NOT EXISTS task_reminders (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    deadline TIMESTAMP NOT NULL,
+    threshold_minutes INTEGER NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, deadline, threshold_minutes)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_outbox_watermark;
+DROP TABLE IF EXISTS event_outbox;
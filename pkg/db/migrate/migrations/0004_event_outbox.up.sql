@@ -0,0 +1,25 @@
+-- Transactional outbox drained to NATS by the relay
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    subject VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_published_at ON event_outbox(published_at) WHERE published_at IS NOT NULL;
+
+-- Single-row publish watermark, kept outside event_outbox so pruning
+-- published messages does not lose it
+CREATE TABLE IF NOT EXISTS event_outbox_watermark (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    last_published_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP
+);
+
+INSERT INTO event_outbox_watermark (id) VALUES (1) ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_user_title_id;
+DROP INDEX IF EXISTS idx_tasks_user_priority_id;
+DROP INDEX IF EXISTS idx_tasks_user_deadline_id;
+DROP INDEX IF EXISTS idx_tasks_user_created_at_id;
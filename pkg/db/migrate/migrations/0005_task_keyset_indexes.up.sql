@@ -0,0 +1,6 @@
+-- Keyset pagination indexes for GET /api/tasks. The expressions must match
+-- sortKeys in internal/domains/task/repo/task.go for the planner to use them.
+CREATE INDEX IF NOT EXISTS idx_tasks_user_created_at_id ON tasks(user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_deadline_id ON tasks(user_id, COALESCE(deadline, 'infinity'::timestamp), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_priority_id ON tasks(user_id, (CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 ELSE 0 END), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_title_id ON tasks(user_id, title, id) WHERE deleted_at IS NULL;
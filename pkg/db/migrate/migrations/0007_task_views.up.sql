@@ -0,0 +1,12 @@
+-- Saved task queries shown as smart lists in the dashboard
+CREATE TABLE IF NOT EXISTS task_views (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_at TIMESTAMP,
+    updated_by UUID,
+    UNIQUE (user_id, name)
+);
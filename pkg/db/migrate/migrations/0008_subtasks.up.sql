@@ -0,0 +1,6 @@
+-- Subtasks point at their parent task; checklist items are stored inline as
+-- a JSON array of {id, text, done}.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checklist JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- task_id is blocked by blocked_by_id. Cycles are rejected by the
+-- application before inserting.
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(id),
+    blocked_by_id UUID NOT NULL REFERENCES tasks(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_id ON task_dependencies (blocked_by_id);
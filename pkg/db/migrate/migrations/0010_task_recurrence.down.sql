@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_series_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,5 @@
+-- Occurrences of a recurring task carry their series as JSON:
+-- {rule, series_id, occurrence, template}. Other tasks leave it NULL.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks ((recurrence->>'series_id')) WHERE recurrence IS NOT NULL;
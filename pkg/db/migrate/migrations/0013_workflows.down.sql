@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_category;
+DROP TABLE IF EXISTS workflows;
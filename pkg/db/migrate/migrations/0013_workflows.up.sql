@@ -0,0 +1,29 @@
+-- Workflows define the statuses of a user's tasks, per user or per project,
+-- as JSON arrays of {name, category} states and {from, to} transitions.
+CREATE TABLE IF NOT EXISTS workflows (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    states JSONB NOT NULL,
+    transitions JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_at TIMESTAMP,
+    updated_by UUID
+);
+
+-- One workflow per user and one per project.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_user ON workflows (user_id) WHERE project_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflows_project ON workflows (project_id) WHERE project_id IS NOT NULL;
+
+-- Existing tasks move onto the default workflow: todo, in_progress and done.
+-- Statuses it does not have, which older versions accepted, become todo.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_category VARCHAR(10) NOT NULL DEFAULT 'open';
+
+UPDATE tasks SET status = 'todo' WHERE status NOT IN ('todo', 'in_progress', 'done');
+UPDATE tasks SET status_category = CASE status
+    WHEN 'in_progress' THEN 'active'
+    WHEN 'done' THEN 'closed'
+    ELSE 'open'
+END;
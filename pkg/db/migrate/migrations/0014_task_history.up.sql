@@ -0,0 +1,14 @@
+-- The field-level change log of every task: who changed what, and the
+-- values before and after.
+CREATE TABLE IF NOT EXISTS task_history (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    field VARCHAR(30) NOT NULL DEFAULT '',
+    old_value JSONB,
+    new_value JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history (task_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_action_tokens_user_id;
+DROP TABLE IF EXISTS user_action_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
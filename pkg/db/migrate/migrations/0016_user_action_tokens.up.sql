@@ -0,0 +1,15 @@
+-- Single-use tokens mailed to users for password resets and email
+-- verification. Only a SHA-256 hash of each token is stored.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS user_action_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_action_tokens_user_id ON user_action_tokens (user_id, purpose) WHERE used_at IS NULL;
@@ -0,0 +1,2 @@
+UPDATE notifications SET task_id = NULL WHERE task_id NOT IN (SELECT id FROM tasks);
+ALTER TABLE notifications ADD CONSTRAINT notifications_task_id_fkey FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE SET NULL;
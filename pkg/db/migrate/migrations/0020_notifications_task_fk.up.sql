@@ -0,0 +1,4 @@
+-- Task events reach the inbox after the mutation committed, possibly after
+-- the task row is gone. Notifications keep the id of the task they were
+-- about instead of referencing it, so recording them cannot fail.
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_task_id_fkey;
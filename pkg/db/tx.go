@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+
+	"go.uber.org/fx"
+)
+
+var TxModule = fx.Module(
+	"db-tx",
+	fx.Provide(fx.Annotate(NewTxManager, fx.As(new(Transactor)))),
+)
+
+// Transactor runs fn in a single database transaction. Repositories pick the
+// transaction up from the context through Executor, so several writes made
+// by different repositories commit or roll back together.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Executor is the query surface shared by *sql.DB and *sql.Tx.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction stored in ctx by WithinTx, or conn when the
+// call is not part of a transaction.
+func Conn(ctx context.Context, conn *sql.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return conn
+}
+
+// InTx reports whether ctx carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
+
+type TxManager struct {
+	conn *sql.DB
+}
+
+func NewTxManager(config *config.Config) *TxManager {
+	return &TxManager{conn: NewDB(config).GetConnection()}
+}
+
+// WithinTx commits when fn returns nil and rolls back otherwise. Nested calls
+// join the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTx(ctx) {
+		return fn(ctx)
+	}
+	if m.conn == nil {
+		return ErrNoConnection
+	}
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// NoTx runs fn directly. It backs the in-memory repositories, which have no
+// transactions to join.
+type NoTx struct{}
+
+func (NoTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
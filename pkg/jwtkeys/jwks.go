@@ -0,0 +1,70 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// JWK is the public half of a key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are an RSA key's modulus and exponent.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv and X are an Ed25519 key's curve and public key.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify the keyring's tokens, the
+// active key first. Retired keys and the shared HS256 secret are never
+// published.
+func (k *Keyring) JWKS() *JWKSet {
+	now := time.Now()
+	set := &JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if retired(key.NotAfter, now) {
+			continue
+		}
+		set.Keys = append(set.Keys, key.jwk())
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		a, b := set.Keys[i], set.Keys[j]
+		if k.active != nil && (a.Kid == k.active.ID) != (b.Kid == k.active.ID) {
+			return a.Kid == k.active.ID
+		}
+		return a.Kid < b.Kid
+	})
+
+	return set
+}
+
+func (key *Key) jwk() JWK {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+	switch pub := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
@@ -0,0 +1,279 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"taskhub/config"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/fx"
+)
+
+var KeyringModule = fx.Module(
+	"jwt-keyring",
+	fx.Provide(NewKeyring),
+)
+
+var (
+	ErrUnknownKey = errors.New("jwtkeys: token signed with an unknown key")
+	ErrRetiredKey = errors.New("jwtkeys: token signed with a retired key")
+)
+
+// minRSABits is the smallest RSA key accepted for signing tokens.
+const minRSABits = 2048
+
+// Key is a kid-tagged key of a Keyring.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// signKey is nil for keys that only verify tokens.
+	signKey   crypto.PrivateKey
+	verifyKey crypto.PublicKey
+	// NotAfter, when set, is when the key stops verifying tokens.
+	NotAfter time.Time
+}
+
+// retired reports whether a key, or the secret, that stops verifying at
+// notAfter has at now.
+func retired(notAfter time.Time, now time.Time) bool {
+	return !notAfter.IsZero() && !now.Before(notAfter)
+}
+
+// Keyring signs tokens with its active key and verifies them with any of
+// its keys, picked by the token's kid header. Tokens without a kid are
+// HS256 tokens checked against the shared secret, which also signs when
+// there is no active key.
+type Keyring struct {
+	active *Key
+	keys   map[string]*Key
+	secret []byte
+	// secretNotAfter, when set, is when the secret stops verifying tokens.
+	secretNotAfter time.Time
+}
+
+// LoadOptions configures LoadKeyring.
+type LoadOptions struct {
+	// ActiveKeyID is the kid of the private key that signs tokens.
+	ActiveKeyID string
+	// LegacySecret verifies HS256 tokens without a kid, issued before the
+	// switch to asymmetric keys, until LegacyUntil. Without both, such
+	// tokens are rejected.
+	LegacySecret string
+	LegacyUntil  time.Time
+	// NotAfter retires keys by kid. Once its time has passed a key no
+	// longer verifies tokens and is no longer published.
+	NotAfter map[string]time.Time
+}
+
+// NewKeyring loads the keys in config.JWT.KeysDir, or returns an HS256
+// keyring over config.JWTSecret when there is no KeysDir.
+func NewKeyring(cfg *config.Config) (*Keyring, error) {
+	if cfg.JWT == nil || cfg.JWT.KeysDir == "" {
+		return NewHMACKeyring(cfg.JWTSecret), nil
+	}
+
+	opts := LoadOptions{ActiveKeyID: cfg.JWT.ActiveKeyID}
+	if cfg.JWT.LegacyHS256Until != "" {
+		until, err := parseTime(cfg.JWT.LegacyHS256Until)
+		if err != nil {
+			return nil, fmt.Errorf("jwtkeys: JWT_LEGACY_HS256_UNTIL: %w", err)
+		}
+		opts.LegacySecret, opts.LegacyUntil = cfg.JWTSecret, until
+	}
+
+	notAfter, err := parseNotAfter(cfg.JWT.KeyNotAfter)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: JWT_KEY_NOT_AFTER: %w", err)
+	}
+	opts.NotAfter = notAfter
+
+	return LoadKeyring(cfg.JWT.KeysDir, opts)
+}
+
+// parseTime parses a date (2006-01-02, midnight UTC) or an RFC 3339 time.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseNotAfter parses comma-separated kid=time pairs.
+func parseNotAfter(value string) (map[string]time.Time, error) {
+	notAfter := map[string]time.Time{}
+	if strings.TrimSpace(value) == "" {
+		return notAfter, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		kid, at, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("%q is not kid=time", pair)
+		}
+		t, err := parseTime(at)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", kid, err)
+		}
+		notAfter[kid] = t
+	}
+
+	return notAfter, nil
+}
+
+// NewHMACKeyring returns a keyring that signs and verifies HS256 tokens
+// with secret.
+func NewHMACKeyring(secret string) *Keyring {
+	return &Keyring{
+		keys:   map[string]*Key{},
+		secret: []byte(secret),
+	}
+}
+
+// LoadKeyring reads every <kid>.pem file in dir and signs with the private
+// key opts.ActiveKeyID.
+func LoadKeyring(dir string, opts LoadOptions) (*Keyring, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	activeID := opts.ActiveKeyID
+	k := &Keyring{keys: map[string]*Key{}}
+	if opts.LegacySecret != "" && !opts.LegacyUntil.IsZero() {
+		k.secret = []byte(opts.LegacySecret)
+		k.secretNotAfter = opts.LegacyUntil
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[key.ID] = key
+	}
+
+	for kid, at := range opts.NotAfter {
+		key, ok := k.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("jwtkeys: retired key %q not found in %s", kid, dir)
+		}
+		if kid == activeID {
+			return nil, fmt.Errorf("jwtkeys: active key %q cannot be retired", kid)
+		}
+		key.NotAfter = at
+	}
+
+	active, ok := k.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: active key %q not found in %s", activeID, dir)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("jwtkeys: active key %q is a public key", activeID)
+	}
+	k.active = active
+
+	return k, nil
+}
+
+// ParseKey parses a PEM-encoded RSA or Ed25519 key, private or public.
+// Private keys may be PKCS #8 or PKCS #1, public keys PKIX.
+func ParseKey(id string, data []byte) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("jwtkeys: key without an id")
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: key %q is not PEM-encoded", id)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwtkeys: key %q has unsupported PEM type %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: key %q: %w", id, err)
+	}
+
+	key := &Key{ID: id}
+	switch parsed := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodRS256, parsed, &parsed.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodRS256, parsed
+	case ed25519.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodEdDSA, parsed, parsed.Public()
+	case ed25519.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodEdDSA, parsed
+	default:
+		return nil, fmt.Errorf("jwtkeys: key %q is not an RSA or Ed25519 key", id)
+	}
+
+	if pub, ok := key.verifyKey.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("jwtkeys: RSA key %q is shorter than %d bits", id, minRSABits)
+	}
+
+	return key, nil
+}
+
+// Sign returns a token with claims, signed with the active key and tagged
+// with its kid.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if k.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	token.Header["kid"] = k.active.ID
+	return token.SignedString(k.active.signKey)
+}
+
+// Keyfunc is a jwt.Keyfunc returning the key that verifies token. It fails
+// with ErrUnknownKey when the kid is unknown or the token's algorithm is
+// not its key's, so a public key is never used as an HMAC secret, and with
+// ErrRetiredKey once the key, or the legacy secret, is past its not-after.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	now := time.Now()
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || k.secret == nil {
+			return nil, ErrUnknownKey
+		}
+		if retired(k.secretNotAfter, now) {
+			return nil, ErrRetiredKey
+		}
+		return k.secret, nil
+	}
+
+	key, ok := k.keys[kid]
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnknownKey
+	}
+	if retired(key.NotAfter, now) {
+		return nil, ErrRetiredKey
+	}
+
+	return key.verifyKey, nil
+}
@@ -0,0 +1,208 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"taskhub/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir string, kid string, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600))
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeEd25519Key(t *testing.T, dir string, kid string) ed25519.PublicKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	writePEM(t, dir, kid, "PRIVATE KEY", der)
+
+	return pub
+}
+
+func writeRSAPublicKey(t *testing.T, dir string, kid string, bits int) *rsa.PrivateKey {
+	t.Helper()
+
+	priv, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	writePEM(t, dir, kid, "PUBLIC KEY", der)
+
+	return priv
+}
+
+func verify(k *Keyring, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, k.Keyfunc)
+	return err
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{Subject: "user", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func TestNewKeyring_HMAC(t *testing.T) {
+	k, err := NewKeyring(&config.Config{JWTSecret: "secret"})
+	require.NoError(t, err)
+
+	token, err := k.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(k, token))
+	assert.Error(t, verify(NewHMACKeyring("other"), token))
+	assert.Empty(t, k.JWKS().Keys)
+}
+
+func TestLoadKeyring_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "2024-01")
+	writeEd25519Key(t, dir, "2024-02")
+	retired := writeRSAPublicKey(t, dir, "2023-12", 2048)
+
+	k, err := NewKeyring(&config.Config{JWTSecret: "legacy", JWT: &config.JWT{
+		KeysDir:          dir,
+		ActiveKeyID:      "2024-01",
+		LegacyHS256Until: time.Now().Add(time.Hour).Format(time.RFC3339),
+	}})
+	require.NoError(t, err)
+
+	token, err := k.Sign(testClaims())
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	assert.NoError(t, verify(k, token))
+
+	// After rotating to 2024-02, tokens signed with 2024-01 still verify.
+	rotated, err := LoadKeyring(dir, LoadOptions{ActiveKeyID: "2024-02"})
+	require.NoError(t, err)
+	assert.NoError(t, verify(rotated, token))
+
+	// Tokens signed with a retired key that is kept as a public key verify.
+	old := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims())
+	old.Header["kid"] = "2023-12"
+	oldToken, err := old.SignedString(retired)
+	require.NoError(t, err)
+	assert.NoError(t, verify(rotated, oldToken))
+
+	// Once past its not-after, a retired key no longer verifies.
+	expired, err := NewKeyring(&config.Config{JWT: &config.JWT{
+		KeysDir:     dir,
+		ActiveKeyID: "2024-02",
+		KeyNotAfter: "2023-12=2024-01-01, 2024-01=" + time.Now().Add(time.Hour).Format(time.RFC3339),
+	}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(expired, oldToken), ErrRetiredKey)
+	assert.NoError(t, verify(expired, token), "keys verify until their not-after")
+
+	// HS256 tokens from before the switch verify only until the cutoff.
+	legacy, err := NewHMACKeyring("legacy").Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(k, legacy))
+	assert.ErrorIs(t, verify(rotated, legacy), ErrUnknownKey)
+	noCutoff, err := NewKeyring(&config.Config{JWTSecret: "legacy", JWT: &config.JWT{KeysDir: dir, ActiveKeyID: "2024-01"}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(noCutoff, legacy), ErrUnknownKey, "the secret needs an explicit cutoff")
+	pastCutoff, err := LoadKeyring(dir, LoadOptions{ActiveKeyID: "2024-01", LegacySecret: "legacy", LegacyUntil: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(pastCutoff, legacy), ErrRetiredKey)
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims())
+	unknown.Header["kid"] = "missing"
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	unknownToken, err := unknown.SignedString(priv)
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(k, unknownToken), ErrUnknownKey)
+}
+
+func TestKeyring_RejectsAlgorithmConfusion(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "active")
+	writeRSAPublicKey(t, dir, "rsa", 2048)
+	pubPEM, err := os.ReadFile(filepath.Join(dir, "rsa.pem"))
+	require.NoError(t, err)
+
+	k, err := LoadKeyring(dir, LoadOptions{ActiveKeyID: "active"})
+	require.NoError(t, err)
+
+	// An HS256 token "signed" with the published RSA key must not verify.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = "rsa"
+	forgedToken, err := forged.SignedString(pubPEM)
+	require.NoError(t, err)
+	assert.ErrorIs(t, verify(k, forgedToken), ErrUnknownKey)
+}
+
+func TestLoadKeyring_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "private")
+	writeRSAPublicKey(t, dir, "public", 2048)
+
+	_, err := LoadKeyring(dir, LoadOptions{ActiveKeyID: "missing"})
+	assert.Error(t, err)
+	_, err = LoadKeyring(dir, LoadOptions{ActiveKeyID: "public"})
+	assert.Error(t, err)
+
+	weak := t.TempDir()
+	writeRSAPublicKey(t, weak, "weak", 1024)
+	_, err = LoadKeyring(weak, LoadOptions{ActiveKeyID: "weak"})
+	assert.Error(t, err)
+
+	_, err = LoadKeyring(dir, LoadOptions{ActiveKeyID: "private", NotAfter: map[string]time.Time{"private": time.Now()}})
+	assert.Error(t, err, "the active key cannot be retired")
+	_, err = LoadKeyring(dir, LoadOptions{ActiveKeyID: "private", NotAfter: map[string]time.Time{"missing": time.Now()}})
+	assert.Error(t, err)
+	_, err = NewKeyring(&config.Config{JWT: &config.JWT{KeysDir: dir, ActiveKeyID: "private", KeyNotAfter: "public"}})
+	assert.Error(t, err)
+	_, err = NewKeyring(&config.Config{JWT: &config.JWT{KeysDir: dir, ActiveKeyID: "private", LegacyHS256Until: "soon"}})
+	assert.Error(t, err)
+
+	_, err = ParseKey("garbage", []byte("not a key"))
+	assert.Error(t, err)
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	dir := t.TempDir()
+	edPub := writeEd25519Key(t, dir, "b-active")
+	rsaKey := writeRSAPublicKey(t, dir, "a-retired", 2048)
+	writeEd25519Key(t, dir, "c-retired")
+
+	k, err := LoadKeyring(dir, LoadOptions{ActiveKeyID: "b-active", NotAfter: map[string]time.Time{"c-retired": time.Now()}})
+	require.NoError(t, err)
+
+	set := k.JWKS()
+	require.Len(t, set.Keys, 2)
+
+	ed := set.Keys[0]
+	assert.Equal(t, JWK{Kty: "OKP", Kid: "b-active", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: b64(edPub)}, ed)
+
+	rsaJWK := set.Keys[1]
+	assert.Equal(t, "RSA", rsaJWK.Kty)
+	assert.Equal(t, "a-retired", rsaJWK.Kid)
+	assert.Equal(t, "RS256", rsaJWK.Alg)
+	assert.Equal(t, b64(rsaKey.N.Bytes()), rsaJWK.N)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+}
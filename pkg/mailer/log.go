@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"taskhub/pkg/logger"
+	"time"
+)
+
+// LogMailer is a Mailer for development. It logs each message and, when dir
+// is set, writes it to an .eml file there instead of sending it.
+type LogMailer struct {
+	logger *logger.Logger
+	from   string
+	dir    string
+}
+
+var _ Mailer = (*LogMailer)(nil)
+
+func NewLogMailer(logger *logger.Logger, from string, dir string) *LogMailer {
+	return &LogMailer{
+		logger: logger,
+		from:   from,
+		dir:    dir,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := format(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	if m.dir == "" {
+		m.logger.Info("Mail logged", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(m.dir, fmt.Sprintf("%s.eml", now.UTC().Format("20060102T150405.000000000")))
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return err
+	}
+
+	m.logger.Info("Mail written", "to", msg.To, "subject", msg.Subject, "file", name)
+	return nil
+}
@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"taskhub/config"
+	"taskhub/pkg/logger"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+var MailerModule = fx.Module(
+	"mailer",
+	fx.Provide(NewMailer),
+)
+
+var ErrInvalidHeader = errors.New("mailer: header contains a line break")
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewMailer returns the Mailer selected by config.Mail.Driver.
+func NewMailer(cfg *config.Config, logger *logger.Logger) (Mailer, error) {
+	mail := cfg.Mail
+	if mail == nil {
+		mail = &config.Mail{}
+	}
+
+	switch mail.Driver {
+	case "", "log":
+		return NewLogMailer(logger, mail.From, mail.Dir), nil
+	case "smtp":
+		return NewSMTPMailer(mail), nil
+	default:
+		return nil, fmt.Errorf("mailer: unknown driver %q", mail.Driver)
+	}
+}
+
+// format renders msg as an RFC 5322 message from from.
+func format(from string, msg *Message, date time.Time) ([]byte, error) {
+	for _, header := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"taskhub/config"
+	"taskhub/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	data, err := format("TaskHub <no-reply@example.com>", &Message{
+		To:      "user@example.com",
+		Subject: "Hello",
+		Body:    "line one\nline two",
+	}, date)
+	require.NoError(t, err)
+
+	text := string(data)
+	assert.Contains(t, text, "From: TaskHub <no-reply@example.com>\r\n")
+	assert.Contains(t, text, "To: user@example.com\r\n")
+	assert.Contains(t, text, "Subject: Hello\r\n")
+	assert.Contains(t, text, "Date: Mon, 15 Jan 2024 10:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(text, "\r\n\r\nline one\r\nline two"))
+}
+
+func TestFormat_RejectsHeaderInjection(t *testing.T) {
+	_, err := format("from@example.com", &Message{
+		To:      "user@example.com",
+		Subject: "Hello\r\nBcc: victim@example.com",
+	}, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestNewMailer(t *testing.T) {
+	log := logger.NewLogger()
+
+	m, err := NewMailer(&config.Config{}, log)
+	require.NoError(t, err)
+	assert.IsType(t, &LogMailer{}, m)
+
+	m, err = NewMailer(&config.Config{Mail: &config.Mail{Driver: "smtp", Host: "localhost", Port: "25"}}, log)
+	require.NoError(t, err)
+	assert.IsType(t, &SMTPMailer{}, m)
+
+	_, err = NewMailer(&config.Config{Mail: &config.Mail{Driver: "pigeon"}}, log)
+	assert.Error(t, err)
+}
+
+func TestLogMailer_WritesFile(t *testing.T) {
+	dir := t.TempDir()
+	m := NewLogMailer(logger.NewLogger(), "from@example.com", dir)
+
+	require.NoError(t, m.Send(context.Background(), &Message{To: "user@example.com", Subject: "Hi", Body: "body"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: Hi\r\n")
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	msg := &Message{To: "user@example.com", Subject: "Hi"}
+
+	require.NoError(t, m.Send(context.Background(), msg))
+	msg.Subject = "changed"
+
+	messages := m.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Hi", messages[0].Subject)
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryMailer records messages instead of sending them, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+var _ Mailer = (*MemoryMailer)(nil)
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := *msg
+	m.messages = append(m.messages, &c)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (m *MemoryMailer) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.messages)
+}
@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"taskhub/config"
+	"time"
+)
+
+// SMTPMailer sends email through an SMTP server, using STARTTLS when the
+// server offers it.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+func NewSMTPMailer(cfg *config.Mail) *SMTPMailer {
+	m := &SMTPMailer{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	data, err := format(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, from.Address, []string{to.Address}, data)
+}
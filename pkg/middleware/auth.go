@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"strings"
 	"taskhub/internal/app"
+	"taskhub/internal/domains/apitoken"
+	"time"
 )
 
 type contextKey string
@@ -14,45 +16,234 @@ const (
 	EmailKey  contextKey = "email"
 )
 
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+
+	loginPath = "/login"
+)
+
 type AuthMiddleware struct {
-	authService *app.AuthService
+	authService  *app.AuthService
+	tokenService *app.PersonalTokenService
 }
 
-func NewAuthMiddleware(authService *app.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService *app.AuthService, tokenService *app.PersonalTokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:  authService,
+		tokenService: tokenService,
 	}
 }
 
+// Authenticate lets through requests with a session: an access token in the
+// Authorization header or the web login's cookies. Personal access tokens
+// are refused with a 403, since these routes manage the account itself.
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return m.AuthenticateScoped("", next)
+}
+
+// AuthenticateScoped is Authenticate for the routes of resource, which also
+// accept a personal access token with the resource's read scope for GET and
+// HEAD requests and its write scope for the rest.
+func (m *AuthMiddleware) AuthenticateScoped(resource string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+		if authHeader != "" {
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+				http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+				return
+			}
+
+			if strings.HasPrefix(parts[1], apitoken.TokenPrefix) {
+				m.authenticatePersonalToken(w, r, parts[1], resource, next)
+				return
+			}
+
+			claims, err := m.authService.ValidateAccessToken(parts[1])
+			if err != nil {
+				if err == app.ErrTokenExpired {
+					http.Error(w, "Token expired", http.StatusUnauthorized)
+					return
+				}
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+		claims, ok := m.authenticateCookies(w, r)
+		if !ok {
+			m.unauthorized(w, r)
 			return
 		}
 
-		tokenString := parts[1]
-		claims, err := m.authService.ValidateAccessToken(tokenString)
-		if err != nil {
-			if err == app.ErrTokenExpired {
-				http.Error(w, "Token expired", http.StatusUnauthorized)
-				return
-			}
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
+
+// authenticatePersonalToken serves r with the personal access token secret
+// when the token allows the request on resource.
+func (m *AuthMiddleware) authenticatePersonalToken(w http.ResponseWriter, r *http.Request, secret string, resource string, next http.Handler) {
+	if m.tokenService == nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	t, err := m.tokenService.Authenticate(r.Context(), secret)
+	if err != nil {
+		switch err {
+		case app.ErrTokenExpired:
+			http.Error(w, "Token expired", http.StatusUnauthorized)
+		case app.ErrInvalidToken:
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
+		default:
+			http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if resource == "" {
+		http.Error(w, "Personal access tokens cannot be used here", http.StatusForbidden)
+		return
+	}
+
+	scope := apitoken.WriteScope(resource)
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		scope = apitoken.ReadScope(resource)
+	}
+	if !t.Allows(scope) {
+		http.Error(w, "Token lacks the "+string(scope)+" scope", http.StatusForbidden)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, t.UserID.String())
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authenticateCookies validates the access token cookie set by the web login
+// and, when it is missing or expired, transparently rotates both cookies using
+// the refresh token cookie.
+func (m *AuthMiddleware) authenticateCookies(w http.ResponseWriter, r *http.Request) (*app.Claims, bool) {
+	if m.authService == nil {
+		return nil, false
+	}
+
+	if cookie, err := r.Cookie(AccessTokenCookie); err == nil && cookie.Value != "" {
+		claims, err := m.authService.ValidateAccessToken(cookie.Value)
+		if err == nil {
+			return claims, true
+		}
+		if err != app.ErrTokenExpired {
+			return nil, false
 		}
+	}
+
+	cookie, err := r.Cookie(RefreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		return nil, false
+	}
+
+	tokens, err := m.authService.RefreshToken(r.Context(), &app.RefreshTokenRequest{RefreshToken: cookie.Value})
+	if err != nil {
+		return nil, false
+	}
+
+	claims, err := m.authService.ValidateAccessToken(tokens.AccessToken)
+	if err != nil {
+		return nil, false
+	}
+
+	SetAuthCookies(w, tokens)
+
+	return claims, true
+}
+
+// unauthorized sends browsers back to the login page and API clients a 401.
+func (m *AuthMiddleware) unauthorized(w http.ResponseWriter, r *http.Request) {
+	hasSession := hasCookie(r, AccessTokenCookie) || hasCookie(r, RefreshTokenCookie)
+	if hasSession {
+		ClearAuthCookies(w)
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", loginPath)
+		http.Error(w, "Session expired", http.StatusUnauthorized)
+		return
+	}
+
+	if isPageRequest(r) {
+		http.Redirect(w, r, loginPath, http.StatusSeeOther)
+		return
+	}
 
-		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-		ctx = context.WithValue(ctx, EmailKey, claims.Email)
+	if hasSession {
+		http.Error(w, "Session expired", http.StatusUnauthorized)
+		return
+	}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
+	http.Error(w, "Authorization header required", http.StatusUnauthorized)
+}
+
+func withClaims(ctx context.Context, claims *app.Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, EmailKey, claims.Email)
+	return ctx
+}
+
+func hasCookie(r *http.Request, name string) bool {
+	cookie, err := r.Cookie(name)
+	return err == nil && cookie.Value != ""
+}
+
+func isPageRequest(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// SetAuthCookies stores a token pair in HttpOnly cookies for the web dashboard.
+func SetAuthCookies(w http.ResponseWriter, tokens *app.TokenPair) {
+	accessMaxAge := int(time.Until(time.Unix(tokens.ExpiresAt, 0)).Seconds())
+	if accessMaxAge <= 0 {
+		accessMaxAge = 900
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     AccessTokenCookie,
+		Value:    tokens.AccessToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   accessMaxAge,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   604800,
+	})
+}
+
+// ClearAuthCookies expires both session cookies.
+func ClearAuthCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     AccessTokenCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   -1,
 	})
 }
 
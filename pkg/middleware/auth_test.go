@@ -6,9 +6,31 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"taskhub/config"
+	"taskhub/internal/app"
+	"taskhub/internal/domains/apitoken"
+	apitokenrepo "taskhub/internal/domains/apitoken/repo"
+	sessionrepo "taskhub/internal/domains/session/repo"
+	"taskhub/internal/domains/user"
+	userrepo "taskhub/internal/domains/user/repo"
+	"taskhub/pkg/base/entity"
+	"taskhub/pkg/jwtkeys"
+	"taskhub/pkg/logger"
+	"taskhub/pkg/mailer"
+
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestAuthService() *app.AuthService {
+	return newTestAuthServiceWithUsers(nil)
+}
+
+func newTestAuthServiceWithUsers(users user.UserStore) *app.AuthService {
+	cfg := &config.Config{JWTSecret: "test-secret-key-for-testing-purposes"}
+	return app.NewAuthService(cfg, logger.NewLogger(), jwtkeys.NewHMACKeyring(cfg.JWTSecret), users, sessionrepo.NewMemoryRefreshTokenRepository(), userrepo.NewMemoryActionTokenRepository(), userrepo.NewMemoryMFARepository(), apitokenrepo.NewMemoryTokenRepository(), mailer.NewMemoryMailer())
+}
+
 func TestContextKey(t *testing.T) {
 	assert.Equal(t, contextKey("user_id"), UserIDKey)
 	assert.Equal(t, contextKey("email"), EmailKey)
@@ -143,6 +165,196 @@ func TestContextValues(t *testing.T) {
 }
 
 func TestNewAuthMiddleware(t *testing.T) {
-	middleware := NewAuthMiddleware(nil)
+	middleware := NewAuthMiddleware(nil, nil)
 	assert.NotNil(t, middleware)
 }
+
+func TestAuthMiddleware_AccessTokenCookie(t *testing.T) {
+	authService := newTestAuthService()
+	middleware := NewAuthMiddleware(authService, nil)
+
+	testUser := &user.User{
+		BaseEntity: entity.BaseEntity{Id: uuid.New()},
+		Email:      "test@example.com",
+	}
+	tokens, err := authService.GenerateTokenPair(context.Background(), testUser, "")
+	assert.NoError(t, err)
+
+	var gotUserID string
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID = GetUserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: tokens.AccessToken})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, testUser.Id.String(), gotUserID)
+}
+
+func TestAuthMiddleware_InvalidCookie(t *testing.T) {
+	middleware := NewAuthMiddleware(newTestAuthService(), nil)
+
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: "invalid-token"})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Result().Cookies())
+}
+
+func TestAuthMiddleware_PageRequestRedirectsToLogin(t *testing.T) {
+	middleware := NewAuthMiddleware(newTestAuthService(), nil)
+
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/login", rec.Header().Get("Location"))
+}
+
+func TestAuthMiddleware_HTMXRequestRedirectsToLogin(t *testing.T) {
+	middleware := NewAuthMiddleware(newTestAuthService(), nil)
+
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "/login", rec.Header().Get("HX-Redirect"))
+}
+
+func TestSetAuthCookies(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	SetAuthCookies(rec, &app.TokenPair{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+	})
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 2)
+	assert.Equal(t, AccessTokenCookie, cookies[0].Name)
+	assert.Equal(t, "access", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, RefreshTokenCookie, cookies[1].Name)
+	assert.Equal(t, "refresh", cookies[1].Value)
+}
+
+func TestClearAuthCookies(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	ClearAuthCookies(rec)
+
+	for _, cookie := range rec.Result().Cookies() {
+		assert.Equal(t, -1, cookie.MaxAge)
+		assert.Empty(t, cookie.Value)
+	}
+}
+
+func TestAuthMiddleware_RefreshesFromRefreshCookie(t *testing.T) {
+	users := userrepo.NewMemoryUserRepository()
+	authService := newTestAuthServiceWithUsers(users)
+	middleware := NewAuthMiddleware(authService, nil)
+
+	testUser := &user.User{
+		BaseEntity: entity.BaseEntity{Id: uuid.New()},
+		Email:      "test@example.com",
+	}
+	users.Create(context.Background(), testUser)
+	tokens, err := authService.GenerateTokenPair(context.Background(), testUser, "")
+	assert.NoError(t, err)
+
+	var gotUserID string
+	handler := middleware.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID = GetUserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	req.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: tokens.RefreshToken})
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, testUser.Id.String(), gotUserID)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 2)
+	assert.Equal(t, AccessTokenCookie, cookies[0].Name)
+	assert.NotEmpty(t, cookies[0].Value)
+}
+
+func TestAuthMiddleware_PersonalAccessToken(t *testing.T) {
+	tokenService := app.NewPersonalTokenService(logger.NewLogger(), apitokenrepo.NewMemoryTokenRepository())
+	middleware := NewAuthMiddleware(newTestAuthService(), tokenService)
+
+	userID := uuid.New()
+	created, err := tokenService.CreateToken(context.Background(), &app.CreatePersonalTokenRequest{
+		Name:   "ci",
+		Scopes: []apitoken.Scope{apitoken.ScopeTasksRead},
+	}, userID)
+	assert.NoError(t, err)
+
+	var gotUserID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID = GetUserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		handler  http.Handler
+		method   string
+		token    string
+		expected int
+	}{
+		{"read scope", middleware.AuthenticateScoped("tasks", next), http.MethodGet, created.Secret, http.StatusOK},
+		{"missing write scope", middleware.AuthenticateScoped("tasks", next), http.MethodPost, created.Secret, http.StatusForbidden},
+		{"other resource", middleware.AuthenticateScoped("labels", next), http.MethodGet, created.Secret, http.StatusForbidden},
+		{"session only route", middleware.Authenticate(next), http.MethodGet, created.Secret, http.StatusForbidden},
+		{"unknown token", middleware.AuthenticateScoped("tasks", next), http.MethodGet, apitoken.TokenPrefix + "unknown", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUserID = ""
+			req := httptest.NewRequest(tt.method, "/api/tasks", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code)
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, userID.String(), gotUserID)
+			} else {
+				assert.Empty(t, gotUserID)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package nats_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"taskhub/pkg/logger"
+	"taskhub/pkg/nats"
+	"taskhub/pkg/nats/natstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consumerConfig(durable string) nats.ConsumerConfig {
+	return nats.ConsumerConfig{
+		Durable:    durable,
+		Stream:     nats.TaskStream.Name,
+		Subjects:   []string{"task.created"},
+		MaxDeliver: 3,
+		AckWait:    5 * time.Second,
+		Backoff:    []time.Duration{10 * time.Millisecond},
+	}
+}
+
+func TestConsume_DeliversPublishedMessage(t *testing.T) {
+	n := natstest.New(t)
+	ctx := context.Background()
+
+	received := make(chan *nats.Message, 1)
+	stop, err := n.Consume(ctx, consumerConfig("deliver"), func(ctx context.Context, msg *nats.Message) error {
+		received <- msg
+		return nil
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "evt-1", []byte(`{"id":"1"}`)))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "task.created", msg.Subject)
+		assert.JSONEq(t, `{"id":"1"}`, string(msg.Data))
+		assert.Equal(t, 1, msg.Delivered)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not delivered")
+	}
+}
+
+func TestConsume_RetriesFailedMessage(t *testing.T) {
+	n := natstest.New(t)
+	ctx := context.Background()
+
+	done := make(chan int, 1)
+	stop, err := n.Consume(ctx, consumerConfig("retry"), func(ctx context.Context, msg *nats.Message) error {
+		if msg.Delivered < 2 {
+			return errors.New("transient")
+		}
+		done <- msg.Delivered
+		return nil
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "evt-1", []byte(`{}`)))
+
+	select {
+	case delivered := <-done:
+		assert.Equal(t, 2, delivered)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not redelivered")
+	}
+}
+
+func TestConsume_DeadLettersPermanentFailure(t *testing.T) {
+	n := natstest.New(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	stop, err := n.Consume(ctx, consumerConfig("poison"), func(ctx context.Context, msg *nats.Message) error {
+		calls.Add(1)
+		return nats.Permanent(errors.New("bad payload"))
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "evt-1", []byte(`not json`)))
+
+	dlq := make(chan *nats.Message, 1)
+	stopDLQ, err := n.Consume(ctx, nats.ConsumerConfig{
+		Durable:  "dlq-reader",
+		Stream:   nats.DeadLetterStream.Name,
+		Subjects: []string{nats.DeadLetterPrefix + ">"},
+	}, func(ctx context.Context, msg *nats.Message) error {
+		dlq <- msg
+		return nil
+	})
+	require.NoError(t, err)
+	defer stopDLQ()
+
+	select {
+	case msg := <-dlq:
+		assert.Equal(t, nats.DeadLetterPrefix+"task.created", msg.Subject)
+		assert.Equal(t, "not json", string(msg.Data))
+		assert.Equal(t, int32(1), calls.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not dead-lettered")
+	}
+}
+
+func TestConsume_WithoutDurableFansOutNewMessages(t *testing.T) {
+	n := natstest.New(t)
+	ctx := context.Background()
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "old", []byte(`{"id":"old"}`)))
+
+	first := make(chan *nats.Message, 2)
+	second := make(chan *nats.Message, 2)
+	for _, received := range []chan *nats.Message{first, second} {
+		received := received
+		stop, err := n.Consume(ctx, consumerConfig(""), func(ctx context.Context, msg *nats.Message) error {
+			received <- msg
+			return nil
+		})
+		require.NoError(t, err)
+		defer stop()
+	}
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "new", []byte(`{"id":"new"}`)))
+
+	for _, received := range []chan *nats.Message{first, second} {
+		select {
+		case msg := <-received:
+			assert.JSONEq(t, `{"id":"new"}`, string(msg.Data))
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not delivered to every consumer")
+		}
+	}
+}
+
+func TestConsume_WithoutJetStream(t *testing.T) {
+	srv, err := nats.StartServer(nats.ServerOptions{JetStream: false})
+	require.NoError(t, err)
+	t.Cleanup(srv.Shutdown)
+
+	n, err := nats.Connect(srv.ClientURL(), logger.NewLogger())
+	require.NoError(t, err)
+	t.Cleanup(n.Close)
+	assert.False(t, n.JetStream())
+
+	ctx := context.Background()
+	require.NoError(t, n.EnsureStreams(ctx, nats.Streams...), "streams are skipped without jetstream")
+
+	received := make(chan string, 1)
+	stop, err := n.Consume(ctx, consumerConfig("core"), func(ctx context.Context, msg *nats.Message) error {
+		received <- string(msg.Data)
+		return nil
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	require.NoError(t, n.PublishStream(ctx, "task.created", "id-1", []byte("hello")))
+	select {
+	case data := <-received:
+		assert.Equal(t, "hello", data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not delivered over core nats")
+	}
+}
@@ -0,0 +1,310 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DeadLetterPrefix is prepended to the subject of messages that exhausted
+// their deliveries or failed permanently, e.g. dlq.task.created.
+const DeadLetterPrefix = "dlq."
+
+// Headers set on dead-lettered messages.
+const (
+	HeaderDeadLetterError     = "Taskhub-Dlq-Error"
+	HeaderDeadLetterDelivered = "Taskhub-Dlq-Delivered"
+	HeaderDeadLetterConsumer  = "Taskhub-Dlq-Consumer"
+)
+
+type StreamSpec struct {
+	Name     string
+	Subjects []string
+	MaxAge   time.Duration
+}
+
+var (
+	// TaskStream stores every task.* event so durable consumers that are down
+	// when an event is published receive it once they are back.
+	TaskStream = StreamSpec{Name: "TASKS", Subjects: []string{"task.*"}, MaxAge: 7 * 24 * time.Hour}
+	// DeadLetterStream keeps poison messages for inspection and replay.
+	DeadLetterStream = StreamSpec{Name: "DLQ", Subjects: []string{DeadLetterPrefix + ">"}, MaxAge: 30 * 24 * time.Hour}
+)
+
+// Streams are provisioned on startup by NatsModule.
+var Streams = []StreamSpec{TaskStream, DeadLetterStream}
+
+// EnsureStreams creates the streams or updates them to match specs. It is
+// safe to call from every replica on every start.
+func (n *Nats) EnsureStreams(ctx context.Context, specs ...StreamSpec) error {
+	if n == nil || n.js == nil {
+		return nil
+	}
+
+	for _, spec := range specs {
+		_, err := n.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:       spec.Name,
+			Subjects:   spec.Subjects,
+			MaxAge:     spec.MaxAge,
+			Storage:    jetstream.FileStorage,
+			Duplicates: 2 * time.Minute,
+		})
+		if err != nil {
+			return fmt.Errorf("provision stream %s: %w", spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// PublishStream publishes to a JetStream subject and waits for the stream to
+// store it. msgID deduplicates retries of the same message within the
+// stream's duplicate window. Without JetStream it publishes on the core
+// subject and waits for the server to receive it.
+func (n *Nats) PublishStream(ctx context.Context, subject string, msgID string, data []byte) error {
+	if !n.IsConnected() {
+		return ErrNotConnected
+	}
+	if n.js == nil {
+		if err := n.conn.Publish(subject, data); err != nil {
+			return err
+		}
+		return n.conn.FlushTimeout(confirmTimeout)
+	}
+
+	var opts []jetstream.PublishOpt
+	if msgID != "" {
+		opts = append(opts, jetstream.WithMsgID(msgID))
+	}
+
+	_, err := n.js.Publish(ctx, subject, data, opts...)
+	return err
+}
+
+// Message is a JetStream message handed to a Handler.
+type Message struct {
+	Subject string
+	Data    []byte
+	// Delivered counts deliveries of this message, starting at 1.
+	Delivered int
+}
+
+// Handler processes a durable message. Returning nil acks it, returning an
+// error redelivers it after a backoff, and returning an error wrapped with
+// Permanent sends it straight to the dead-letter subject.
+type Handler func(ctx context.Context, msg *Message) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. a payload that cannot be
+// decoded.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+type ConsumerConfig struct {
+	// Durable names the consumer. Replicas using the same name share the
+	// work, like a queue group. Without a name every process gets its own
+	// consumer that receives only messages published after it started and
+	// is removed once the process is gone.
+	Durable  string
+	Stream   string
+	Subjects []string
+	// MaxDeliver is the number of attempts before a message is
+	// dead-lettered. Defaults to 5.
+	MaxDeliver int
+	// AckWait is how long a handler may run before the message is
+	// redelivered. Defaults to 30s.
+	AckWait time.Duration
+	// Backoff is the redelivery delay after each failed attempt; the last
+	// value repeats. Defaults to 1s, 5s, 30s.
+	Backoff []time.Duration
+
+	// name identifies the consumer in logs and dead letters; it is the
+	// server-assigned name for consumers without Durable.
+	name string
+}
+
+// ephemeralInactiveThreshold is how long the server keeps a consumer
+// without Durable after its process stopped pulling.
+const ephemeralInactiveThreshold = time.Minute
+
+func (c ConsumerConfig) withDefaults() ConsumerConfig {
+	if c.MaxDeliver <= 0 {
+		c.MaxDeliver = 5
+	}
+	if c.AckWait <= 0 {
+		c.AckWait = 30 * time.Second
+	}
+	if len(c.Backoff) == 0 {
+		c.Backoff = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+	}
+	return c
+}
+
+type disposition int
+
+const (
+	dispositionAck disposition = iota
+	dispositionRetry
+	dispositionDeadLetter
+)
+
+// dispose decides what happens to a message after its handler returned err
+// on the given delivery.
+func (c ConsumerConfig) dispose(err error, delivered int) disposition {
+	switch {
+	case err == nil:
+		return dispositionAck
+	case IsPermanent(err) || delivered >= c.MaxDeliver:
+		return dispositionDeadLetter
+	default:
+		return dispositionRetry
+	}
+}
+
+func (c ConsumerConfig) retryDelay(delivered int) time.Duration {
+	i := min(max(delivered-1, 0), len(c.Backoff)-1)
+	return c.Backoff[i]
+}
+
+// Consume runs handler for every message on a pull consumer until stop is
+// called. Without a connection it does nothing. Without JetStream it
+// subscribes to the subjects in a queue group named after the consumer, so
+// only messages published while it runs are handled and failed ones are not
+// retried.
+func (n *Nats) Consume(ctx context.Context, cfg ConsumerConfig, handler Handler) (stop func(), err error) {
+	if n == nil || n.conn == nil {
+		return func() {}, nil
+	}
+
+	cfg = cfg.withDefaults()
+	cfg.name = cfg.Durable
+	if n.js == nil {
+		return n.consumeCore(cfg, handler)
+	}
+
+	consumerConfig := jetstream.ConsumerConfig{
+		Durable:        cfg.Durable,
+		FilterSubjects: cfg.Subjects,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        cfg.AckWait,
+		// One extra delivery lets a message whose last attempt timed out
+		// still reach the dead-letter subject instead of being dropped.
+		MaxDeliver: cfg.MaxDeliver + 1,
+	}
+	if cfg.Durable == "" {
+		consumerConfig.DeliverPolicy = jetstream.DeliverNewPolicy
+		consumerConfig.InactiveThreshold = ephemeralInactiveThreshold
+	}
+
+	consumer, err := n.js.CreateOrUpdateConsumer(ctx, cfg.Stream, consumerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create consumer %s: %w", cfg.Durable, err)
+	}
+	cfg.name = consumer.CachedInfo().Name
+
+	cc, err := consumer.Consume(func(msg jetstream.Msg) {
+		n.handle(cfg, msg, handler)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cc.Stop, nil
+}
+
+func (n *Nats) consumeCore(cfg ConsumerConfig, handler Handler) (stop func(), err error) {
+	var subs []*nats.Subscription
+	stop = func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}
+
+	for _, subject := range cfg.Subjects {
+		// An empty queue name is a plain subscription, so consumers
+		// without Durable each get every message.
+		sub, err := n.conn.QueueSubscribe(subject, cfg.Durable, func(msg *nats.Msg) {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.AckWait)
+			defer cancel()
+
+			if err := handler(ctx, &Message{Subject: msg.Subject, Data: msg.Data, Delivered: 1}); err != nil {
+				n.logger.Error("message handler failed, dropping it without jetstream", "consumer", cfg.name, "subject", msg.Subject, "error", err)
+			}
+		})
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return stop, nil
+}
+
+func (n *Nats) handle(cfg ConsumerConfig, msg jetstream.Msg, handler Handler) {
+	delivered := 1
+	if meta, err := msg.Metadata(); err == nil {
+		delivered = int(meta.NumDelivered)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.AckWait)
+	defer cancel()
+
+	err := handler(ctx, &Message{Subject: msg.Subject(), Data: msg.Data(), Delivered: delivered})
+
+	switch cfg.dispose(err, delivered) {
+	case dispositionAck:
+		msg.Ack()
+	case dispositionRetry:
+		n.logger.Error("message handler failed, retrying", "consumer", cfg.name, "subject", msg.Subject(), "delivered", delivered, "error", err)
+		msg.NakWithDelay(cfg.retryDelay(delivered))
+	case dispositionDeadLetter:
+		n.logger.Error("message dead-lettered", "consumer", cfg.name, "subject", msg.Subject(), "delivered", delivered, "error", err)
+		dlqCtx, dlqCancel := context.WithTimeout(context.Background(), confirmTimeout)
+		defer dlqCancel()
+		if dlqErr := n.deadLetter(dlqCtx, cfg, msg, delivered, err); dlqErr != nil {
+			n.logger.Error("failed to dead-letter message", "subject", msg.Subject(), "error", dlqErr)
+			msg.NakWithDelay(cfg.retryDelay(delivered))
+			return
+		}
+		msg.TermWithReason(err.Error())
+	}
+}
+
+func (n *Nats) deadLetter(ctx context.Context, cfg ConsumerConfig, msg jetstream.Msg, delivered int, reason error) error {
+	dlq := nats.NewMsg(DeadLetterPrefix + msg.Subject())
+	dlq.Data = msg.Data()
+	for key, values := range msg.Headers() {
+		for _, value := range values {
+			dlq.Header.Add(key, value)
+		}
+	}
+	// Scope the dedup id to the consumer so two consumers dead-lettering the
+	// same event both land in the DLQ.
+	if id := dlq.Header.Get(nats.MsgIdHdr); id != "" {
+		dlq.Header.Set(nats.MsgIdHdr, cfg.name+":"+id)
+	}
+	dlq.Header.Set(HeaderDeadLetterError, reason.Error())
+	dlq.Header.Set(HeaderDeadLetterDelivered, strconv.Itoa(delivered))
+	dlq.Header.Set(HeaderDeadLetterConsumer, cfg.name)
+
+	_, err := n.js.PublishMsg(ctx, dlq)
+	return err
+}
@@ -0,0 +1,51 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("bad payload")
+	err := fmt.Errorf("decode: %w", Permanent(cause))
+
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, cause)
+	assert.False(t, IsPermanent(cause))
+}
+
+func TestConsumerConfig_Dispose(t *testing.T) {
+	cfg := ConsumerConfig{MaxDeliver: 3}.withDefaults()
+	failure := errors.New("db down")
+
+	assert.Equal(t, dispositionAck, cfg.dispose(nil, 1))
+	assert.Equal(t, dispositionRetry, cfg.dispose(failure, 1))
+	assert.Equal(t, dispositionRetry, cfg.dispose(failure, 2))
+	assert.Equal(t, dispositionDeadLetter, cfg.dispose(failure, 3))
+	assert.Equal(t, dispositionDeadLetter, cfg.dispose(Permanent(failure), 1))
+}
+
+func TestConsumerConfig_RetryDelay(t *testing.T) {
+	cfg := ConsumerConfig{Backoff: []time.Duration{time.Second, time.Minute}}.withDefaults()
+
+	assert.Equal(t, time.Second, cfg.retryDelay(1))
+	assert.Equal(t, time.Minute, cfg.retryDelay(2))
+	assert.Equal(t, time.Minute, cfg.retryDelay(5))
+}
+
+func TestNats_JetStream_Nil(t *testing.T) {
+	var n *Nats
+	ctx := context.Background()
+
+	assert.NoError(t, n.EnsureStreams(ctx, Streams...))
+	assert.ErrorIs(t, n.PublishStream(ctx, "task.created", "id", nil), ErrNotConnected)
+
+	stop, err := n.Consume(ctx, ConsumerConfig{Durable: "test"}, func(ctx context.Context, msg *Message) error { return nil })
+	assert.NoError(t, err)
+	stop()
+}
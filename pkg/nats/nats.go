@@ -1,39 +1,104 @@
 package nats
 
 import (
+	"context"
+	"errors"
 	"taskhub/config"
 	"taskhub/pkg/logger"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/fx"
 )
 
 var NatsModule = fx.Module(
 	"nats",
-	fx.Provide(NewNats),
+	fx.Provide(NewEmbeddedServer, NewNats),
+	fx.Invoke(registerLifecycle),
 )
 
+// confirmTimeout bounds how long a dead-letter publish waits for the server.
+const confirmTimeout = 5 * time.Second
+
+var ErrNotConnected = errors.New("nats: not connected")
+
 type Nats struct {
-	conn   *nats.Conn
+	conn *nats.Conn
+	// js is nil when the server has no JetStream. Streams are then not
+	// provisioned, and PublishStream and Consume fall back to core subjects.
+	js     jetstream.JetStream
 	logger *logger.Logger
 }
 
-func NewNats(config *config.Config, logger *logger.Logger) *Nats {
+// NewNats connects to the embedded server when one is running and to
+// NATS_URL otherwise. It returns nil when the connection fails; every method
+// treats a nil *Nats as disconnected.
+func NewNats(config *config.Config, logger *logger.Logger, embedded *Server) *Nats {
 	url := config.NatsUrl
+	if embedded != nil {
+		url = embedded.ClientURL()
+	}
 	if url == "" {
 		url = nats.DefaultURL
 	}
 
-	conn, err := nats.Connect(url)
+	n, err := Connect(url, logger)
 	if err != nil {
-		logger.Error("failed to connect to nats: %v", err)
+		logger.Error("failed to connect to nats", "url", url, "error", err)
 		return nil
 	}
 
+	return n
+}
+
+// Connect dials url and checks whether the server has JetStream, falling
+// back to core subjects when it does not.
+func Connect(url string, logger *logger.Logger) (*Nats, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), confirmTimeout)
+	defer cancel()
+	if _, err := js.AccountInfo(ctx); err != nil {
+		if !errors.Is(err, jetstream.ErrJetStreamNotEnabled) && !errors.Is(err, jetstream.ErrJetStreamNotEnabledForAccount) {
+			conn.Close()
+			return nil, err
+		}
+		logger.Info("nats server has no jetstream, using core subjects without persistence or retries", "url", url)
+		js = nil
+	}
+
 	return &Nats{
 		conn:   conn,
+		js:     js,
 		logger: logger,
-	}
+	}, nil
+}
+
+// JetStream reports whether messages are persisted in streams.
+func (n *Nats) JetStream() bool {
+	return n != nil && n.js != nil
+}
+
+func registerLifecycle(lc fx.Lifecycle, n *Nats) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return n.EnsureStreams(ctx, Streams...)
+		},
+		OnStop: func(ctx context.Context) error {
+			n.Close()
+			return nil
+		},
+	})
 }
 
 func (n *Nats) Close() {
@@ -52,30 +117,6 @@ func (n *Nats) Publish(subject string, data []byte) error {
 	return n.conn.Publish(subject, data)
 }
 
-func (n *Nats) Subscribe(subject string, handler func([]byte)) error {
-	if n == nil || n.conn == nil {
-		return nil
-	}
-
-	_, err := n.conn.Subscribe(subject, func(msg *nats.Msg) {
-		handler(msg.Data)
-	})
-
-	return err
-}
-
-func (n *Nats) QueueSubscribe(subject, queue string, handler func([]byte)) error {
-	if n == nil || n.conn == nil {
-		return nil
-	}
-
-	_, err := n.conn.QueueSubscribe(subject, queue, func(msg *nats.Msg) {
-		handler(msg.Data)
-	})
-
-	return err
-}
-
 func (n *Nats) IsConnected() bool {
 	return n != nil && n.conn != nil && n.conn.IsConnected()
 }
@@ -28,30 +28,6 @@ func TestNats_Publish_NilConn(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestNats_Subscribe_Nil(t *testing.T) {
-	var n *Nats
-	err := n.Subscribe("test", func(data []byte) {})
-	assert.NoError(t, err)
-}
-
-func TestNats_Subscribe_NilConn(t *testing.T) {
-	n := &Nats{conn: nil}
-	err := n.Subscribe("test", func(data []byte) {})
-	assert.NoError(t, err)
-}
-
-func TestNats_QueueSubscribe_Nil(t *testing.T) {
-	var n *Nats
-	err := n.QueueSubscribe("test", "queue", func(data []byte) {})
-	assert.NoError(t, err)
-}
-
-func TestNats_QueueSubscribe_NilConn(t *testing.T) {
-	n := &Nats{conn: nil}
-	err := n.QueueSubscribe("test", "queue", func(data []byte) {})
-	assert.NoError(t, err)
-}
-
 func TestNats_IsConnected_Nil(t *testing.T) {
 	var n *Nats
 	assert.False(t, n.IsConnected())
@@ -0,0 +1,38 @@
+// Package natstest runs an embedded JetStream server for tests that need a
+// real broker.
+package natstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskhub/pkg/logger"
+	"taskhub/pkg/nats"
+)
+
+// New starts a JetStream server in t.TempDir, connects to it and provisions
+// nats.Streams. The connection and server are closed when the test ends.
+func New(t testing.TB) *nats.Nats {
+	t.Helper()
+
+	srv, err := nats.StartServer(nats.ServerOptions{JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("start nats server: %v", err)
+	}
+	t.Cleanup(srv.Shutdown)
+
+	n, err := nats.Connect(srv.ClientURL(), logger.NewLogger())
+	if err != nil {
+		t.Fatalf("connect to nats: %v", err)
+	}
+	t.Cleanup(n.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := n.EnsureStreams(ctx, nats.Streams...); err != nil {
+		t.Fatalf("provision streams: %v", err)
+	}
+
+	return n
+}
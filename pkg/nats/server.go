@@ -0,0 +1,115 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"os"
+	"taskhub/config"
+	"taskhub/pkg/logger"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"go.uber.org/fx"
+)
+
+var ErrServerNotReady = errors.New("nats: embedded server not ready")
+
+// ServerOptions configures an in-process NATS server.
+type ServerOptions struct {
+	// JetStream enables persistence, needed for streams and durable
+	// consumers.
+	JetStream bool
+	// StoreDir holds JetStream data. When empty a temporary directory is
+	// used and removed on Shutdown.
+	StoreDir string
+}
+
+// Server is an in-process NATS server listening on a random local port.
+type Server struct {
+	srv      *server.Server
+	storeDir string
+	tempDir  bool
+}
+
+func StartServer(opts ServerOptions) (*Server, error) {
+	s := &Server{storeDir: opts.StoreDir}
+	if opts.JetStream && s.storeDir == "" {
+		dir, err := os.MkdirTemp("", "taskhub-nats-")
+		if err != nil {
+			return nil, err
+		}
+		s.storeDir = dir
+		s.tempDir = true
+	}
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: opts.JetStream,
+		StoreDir:  s.storeDir,
+		NoSigs:    true,
+		NoLog:     true,
+	})
+	if err != nil {
+		s.removeTempDir()
+		return nil, err
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		srv.Shutdown()
+		s.removeTempDir()
+		return nil, ErrServerNotReady
+	}
+
+	s.srv = srv
+	return s, nil
+}
+
+// ClientURL is the URL clients connect to.
+func (s *Server) ClientURL() string {
+	return s.srv.ClientURL()
+}
+
+func (s *Server) Shutdown() {
+	if s == nil || s.srv == nil {
+		return
+	}
+
+	s.srv.Shutdown()
+	s.srv.WaitForShutdown()
+	s.removeTempDir()
+}
+
+func (s *Server) removeTempDir() {
+	if s.tempDir {
+		os.RemoveAll(s.storeDir)
+	}
+}
+
+// NewEmbeddedServer starts the in-process server when NATS_EMBEDDED is set
+// and returns nil otherwise, in which case NewNats dials NATS_URL.
+func NewEmbeddedServer(lc fx.Lifecycle, config *config.Config, logger *logger.Logger) (*Server, error) {
+	if config.EmbeddedNats == nil || !config.EmbeddedNats.Enabled {
+		return nil, nil
+	}
+
+	s, err := StartServer(ServerOptions{
+		JetStream: config.EmbeddedNats.JetStream,
+		StoreDir:  config.EmbeddedNats.StoreDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("embedded nats server started", "url", s.ClientURL(), "jetstream", config.EmbeddedNats.JetStream)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			s.Shutdown()
+			return nil
+		},
+	})
+
+	return s, nil
+}
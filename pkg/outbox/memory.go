@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore is an in-memory Store for tests and local development.
+type MemoryStore struct {
+	mu              sync.Mutex
+	nextID          int64
+	messages        []*memoryMessage
+	watermark       int64
+	lastPublishedAt *time.Time
+}
+
+type memoryMessage struct {
+	Message
+	publishedAt *time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Enqueue(ctx context.Context, eventID uuid.UUID, subject string, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	now := time.Now()
+	s.messages = append(s.messages, &memoryMessage{Message: Message{
+		ID:            s.nextID,
+		EventID:       eventID,
+		Subject:       subject,
+		Payload:       append([]byte(nil), payload...),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}})
+
+	return nil
+}
+
+func (s *MemoryStore) Pending(ctx context.Context, limit int) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []*Message
+	for _, m := range s.messages {
+		if m.publishedAt != nil {
+			continue
+		}
+		if len(pending) == limit {
+			break
+		}
+		c := m.Message
+		pending = append(pending, &c)
+	}
+
+	return pending, nil
+}
+
+func (s *MemoryStore) MarkPublished(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.find(id); m != nil {
+		now := time.Now()
+		m.publishedAt = &now
+		s.watermark = max(s.watermark, id)
+		s.lastPublishedAt = &now
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := s.find(id); m != nil {
+		m.Attempts++
+		m.LastError = reason
+		m.NextAttemptAt = nextAttemptAt
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.messages[:0]
+	var pruned int64
+	for _, m := range s.messages {
+		if m.publishedAt != nil && m.publishedAt.Before(before) {
+			pruned++
+			continue
+		}
+		kept = append(kept, m)
+	}
+	s.messages = kept
+
+	return pruned, nil
+}
+
+func (s *MemoryStore) Stats(ctx context.Context) (*Stats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := &Stats{Watermark: s.watermark, LastPublishedAt: s.lastPublishedAt}
+	for _, m := range s.messages {
+		if m.publishedAt == nil {
+			stats.Backlog++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *MemoryStore) find(id int64) *memoryMessage {
+	for _, m := range s.messages {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
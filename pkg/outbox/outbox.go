@@ -0,0 +1,54 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var OutboxModule = fx.Module(
+	"outbox",
+	fx.Provide(fx.Annotate(NewPostgresStore, fx.As(new(Store)))),
+)
+
+// Message is an event waiting in the outbox to be published. ID is assigned
+// by the store and increases in insertion order.
+type Message struct {
+	ID            int64
+	EventID       uuid.UUID
+	Subject       string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// Stats describes the relay's progress.
+type Stats struct {
+	// Backlog is the number of messages not yet published.
+	Backlog int `json:"backlog"`
+	// Watermark is the id of the last published message.
+	Watermark int64 `json:"watermark"`
+	// LastPublishedAt is when the watermark last moved.
+	LastPublishedAt *time.Time `json:"last_published_at,omitempty"`
+}
+
+// Store persists outbox messages. Enqueue joins the transaction carried by
+// ctx, if any, so the message commits atomically with the change it
+// describes.
+type Store interface {
+	Enqueue(ctx context.Context, eventID uuid.UUID, subject string, payload []byte) error
+	// Pending returns up to limit unpublished messages in id order.
+	Pending(ctx context.Context, limit int) ([]*Message, error)
+	// MarkPublished flags the message as published and advances the
+	// watermark.
+	MarkPublished(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt and when to retry.
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+	// Prune deletes messages published before the given time.
+	Prune(ctx context.Context, before time.Time) (int64, error)
+	Stats(ctx context.Context) (*Stats, error)
+}
@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"taskhub/config"
+	"taskhub/pkg/db"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostgresStore keeps the outbox in the event_outbox table.
+type PostgresStore struct {
+	conn *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+func NewPostgresStore(config *config.Config) *PostgresStore {
+	return &PostgresStore{conn: db.NewDB(config).GetConnection()}
+}
+
+func (s *PostgresStore) Enqueue(ctx context.Context, eventID uuid.UUID, subject string, payload []byte) error {
+	query := `INSERT INTO event_outbox (event_id, subject, payload) VALUES ($1, $2, $3)`
+
+	_, err := db.Conn(ctx, s.conn).ExecContext(ctx, query, eventID, subject, payload)
+	return err
+}
+
+func (s *PostgresStore) Pending(ctx context.Context, limit int) ([]*Message, error) {
+	query := `SELECT id, event_id, subject, payload, created_at, attempts, next_attempt_at, COALESCE(last_error, '')
+              FROM event_outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`
+
+	rows, err := s.conn.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ID, &m.EventID, &m.Subject, &m.Payload, &m.CreatedAt, &m.Attempts, &m.NextAttemptAt, &m.LastError); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (s *PostgresStore) MarkPublished(ctx context.Context, id int64) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE event_outbox SET published_at = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE event_outbox_watermark SET last_published_id = GREATEST(last_published_id, $1), updated_at = NOW() WHERE id = 1`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresStore) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	query := `UPDATE event_outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`
+
+	_, err := s.conn.ExecContext(ctx, query, reason, nextAttemptAt, id)
+	return err
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM event_outbox WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *PostgresStore) Stats(ctx context.Context) (*Stats, error) {
+	var stats Stats
+	var updatedAt sql.NullTime
+
+	err := s.conn.QueryRowContext(ctx, `SELECT
+        (SELECT COUNT(*) FROM event_outbox WHERE published_at IS NULL),
+        w.last_published_id, w.updated_at
+        FROM event_outbox_watermark w WHERE w.id = 1`,
+	).Scan(&stats.Backlog, &stats.Watermark, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedAt.Valid {
+		stats.LastPublishedAt = &updatedAt.Time
+	}
+
+	return &stats, nil
+}
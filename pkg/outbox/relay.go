@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"taskhub/pkg/logger"
+	"time"
+)
+
+// Publisher delivers a message payload to subject and reports whether the
+// broker accepted it. msgID lets the broker drop duplicates when a message
+// is published again after a crash.
+type Publisher func(ctx context.Context, subject string, msgID string, data []byte) error
+
+// Relay drains the outbox to a Publisher. Messages are published in id order
+// and a failed message holds back the ones behind it until its backoff
+// expires, so subscribers see events in the order they were committed.
+// Delivery is at-least-once: a crash between publishing and MarkPublished
+// republishes the message, and consumers deduplicate on the event id.
+type Relay struct {
+	store      Store
+	publish    Publisher
+	logger     *logger.Logger
+	batchSize  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	retention  time.Duration
+}
+
+func NewRelay(store Store, publish Publisher, logger *logger.Logger, batchSize int, maxBackoff time.Duration) *Relay {
+	return &Relay{
+		store:      store,
+		publish:    publish,
+		logger:     logger,
+		batchSize:  batchSize,
+		minBackoff: time.Second,
+		maxBackoff: maxBackoff,
+		retention:  24 * time.Hour,
+	}
+}
+
+// RunOnce publishes one batch of pending messages and returns how many were
+// published.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	messages, err := r.store.Pending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	now := time.Now()
+	for _, m := range messages {
+		if m.NextAttemptAt.After(now) {
+			break
+		}
+
+		if err := r.publish(ctx, m.Subject, m.EventID.String(), m.Payload); err != nil {
+			next := now.Add(r.backoff(m.Attempts + 1))
+			r.logger.Error("failed to publish outbox message", "id", m.ID, "subject", m.Subject, "attempts", m.Attempts+1, "error", err)
+			if markErr := r.store.MarkFailed(ctx, m.ID, err.Error(), next); markErr != nil {
+				return published, markErr
+			}
+			break
+		}
+
+		if err := r.store.MarkPublished(ctx, m.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	if _, err := r.store.Prune(ctx, now.Add(-r.retention)); err != nil {
+		r.logger.Error("failed to prune outbox", "error", err)
+	}
+
+	return published, nil
+}
+
+// backoff doubles from minBackoff for every failed attempt, capped at
+// maxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.minBackoff
+	for i := 1; i < attempts && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.maxBackoff)
+}
+
+func (r *Relay) Stats(ctx context.Context) (*Stats, error) {
+	return r.store.Stats(ctx)
+}
@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskhub/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	for _, subject := range []string{"a", "b", "c"} {
+		require.NoError(t, store.Enqueue(ctx, uuid.New(), subject, []byte(subject)))
+	}
+
+	var published []string
+	relay := NewRelay(store, func(ctx context.Context, subject, msgID string, data []byte) error {
+		published = append(published, subject)
+		return nil
+	}, logger.NewLogger(), 10, time.Minute)
+
+	n, err := relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"a", "b", "c"}, published)
+
+	stats, err := relay.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Backlog)
+	assert.Equal(t, int64(3), stats.Watermark)
+	assert.NotNil(t, stats.LastPublishedAt)
+}
+
+func TestRelay_FailureHoldsBackLaterMessages(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Enqueue(ctx, uuid.New(), "a", nil)
+	store.Enqueue(ctx, uuid.New(), "b", nil)
+
+	down := true
+	var published []string
+	relay := NewRelay(store, func(ctx context.Context, subject, msgID string, data []byte) error {
+		if down {
+			return errors.New("broker down")
+		}
+		published = append(published, subject)
+		return nil
+	}, logger.NewLogger(), 10, time.Minute)
+
+	n, err := relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	pending, _ := store.Pending(ctx, 10)
+	require.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "broker down", pending[0].LastError)
+	assert.True(t, pending[0].NextAttemptAt.After(time.Now()))
+
+	down = false
+	n, _ = relay.RunOnce(ctx)
+	assert.Zero(t, n, "backoff has not expired")
+
+	store.MarkFailed(ctx, pending[0].ID, "broker down", time.Now().Add(-time.Second))
+	n, err = relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b"}, published)
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(NewMemoryStore(), nil, logger.NewLogger(), 10, 10*time.Second)
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(10))
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Enqueue(ctx, uuid.New(), "a", nil)
+	store.Enqueue(ctx, uuid.New(), "b", nil)
+	store.MarkPublished(ctx, 1)
+
+	pruned, err := store.Prune(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	stats, _ := store.Stats(ctx)
+	assert.Equal(t, 1, stats.Backlog)
+	assert.Equal(t, int64(1), stats.Watermark)
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"taskhub/pkg/logger"
+	"time"
+)
+
+// Job is a unit of periodic work.
+type Job func(ctx context.Context) error
+
+// Locker elects a leader for a single run. TryLock returns acquired=false,
+// without an error, when another replica currently holds the lock.
+type Locker interface {
+	TryLock(ctx context.Context) (release func(), acquired bool, err error)
+}
+
+// Scheduler runs a Job on a fixed interval, only on the replica that wins
+// the Locker for that tick.
+type Scheduler struct {
+	name     string
+	interval time.Duration
+	locker   Locker
+	job      Job
+	logger   *logger.Logger
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewScheduler(name string, interval time.Duration, locker Locker, job Job, logger *logger.Logger) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		interval: interval,
+		locker:   locker,
+		job:      job,
+		logger:   logger,
+	}
+}
+
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.loop(s.stop, s.done)
+}
+
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+			if _, err := s.RunOnce(ctx); err != nil {
+				s.logger.Error("scheduled job failed", "job", s.name, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// RunOnce runs the job if this replica acquires the lock. It reports whether
+// the job ran.
+func (s *Scheduler) RunOnce(ctx context.Context) (bool, error) {
+	if s.locker != nil {
+		release, acquired, err := s.locker.TryLock(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !acquired {
+			return false, nil
+		}
+		defer release()
+	}
+
+	return true, s.job(ctx)
+}
+
+// LocalLocker is a process-local Locker for single-replica deployments and
+// tests.
+type LocalLocker struct {
+	mu sync.Mutex
+}
+
+func (l *LocalLocker) TryLock(ctx context.Context) (func(), bool, error) {
+	if !l.mu.TryLock() {
+		return nil, false, nil
+	}
+
+	return l.mu.Unlock, true, nil
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"taskhub/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_RunOnce(t *testing.T) {
+	var runs int32
+	s := NewScheduler("test", time.Minute, &LocalLocker{}, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, logger.NewLogger())
+
+	ran, err := s.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestScheduler_RunOnce_LockHeld(t *testing.T) {
+	locker := &LocalLocker{}
+	release, acquired, _ := locker.TryLock(context.Background())
+	assert.True(t, acquired)
+	defer release()
+
+	s := NewScheduler("test", time.Minute, locker, func(ctx context.Context) error {
+		t.Fatal("job must not run while another replica holds the lock")
+		return nil
+	}, logger.NewLogger())
+
+	ran, err := s.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestScheduler_RunOnce_JobError(t *testing.T) {
+	jobErr := errors.New("boom")
+	s := NewScheduler("test", time.Minute, nil, func(ctx context.Context) error {
+		return jobErr
+	}, logger.NewLogger())
+
+	ran, err := s.RunOnce(context.Background())
+
+	assert.True(t, ran)
+	assert.Equal(t, jobErr, err)
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	var runs int32
+	s := NewScheduler("test", 5*time.Millisecond, &LocalLocker{}, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, logger.NewLogger())
+
+	s.Start()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) >= 2 }, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, s.Stop(context.Background()))
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+func TestScheduler_StopWithoutStart(t *testing.T) {
+	s := NewScheduler("test", time.Minute, nil, nil, nil)
+	assert.NoError(t, s.Stop(context.Background()))
+}
@@ -0,0 +1,194 @@
+package sse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a single Server-Sent Event.
+type Event struct {
+	ID   string
+	Name string
+	Data []byte
+}
+
+// Write encodes e in the text/event-stream wire format.
+func (e Event) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	if e.ID != "" {
+		fmt.Fprintf(&buf, "id: %s\n", e.ID)
+	}
+	if e.Name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", e.Name)
+	}
+	for _, line := range strings.Split(string(e.Data), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Subscription receives the events published for one key. Events is closed
+// when the subscriber falls too far behind or Close is called; clients are
+// expected to reconnect with Last-Event-ID.
+type Subscription struct {
+	Events <-chan Event
+	// Replay holds the buffered events published after the requested
+	// Last-Event-ID.
+	Replay []Event
+	// Missed is set when the requested Last-Event-ID is no longer buffered,
+	// so the client has to reload its state instead of relying on Replay.
+	Missed bool
+
+	broker *Broker
+	key    string
+	ch     chan Event
+}
+
+func (s *Subscription) Close() {
+	s.broker.remove(s.key, s.ch)
+}
+
+// Broker fans events out to subscribers by key and keeps a short per-key
+// history so reconnecting clients can resume where they left off. History
+// is capped at historySize events per key and events older than historyAge
+// are dropped, along with the key once it has none left.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+	history     map[string][]historyEntry
+	historySize int
+	historyAge  time.Duration
+	bufferSize  int
+	now         func() time.Time
+	lastSweep   time.Time
+}
+
+type historyEntry struct {
+	event       Event
+	publishedAt time.Time
+}
+
+func NewBroker(historySize int, historyAge time.Duration) *Broker {
+	return &Broker{
+		subscribers: make(map[string]map[chan Event]struct{}),
+		history:     make(map[string][]historyEntry),
+		historySize: historySize,
+		historyAge:  historyAge,
+		bufferSize:  16,
+		now:         time.Now,
+	}
+}
+
+// Subscribe registers a subscriber for key. When lastEventID is set, the
+// events published after it are returned in Subscription.Replay.
+func (b *Broker) Subscribe(key string, lastEventID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, b.bufferSize)
+	if b.subscribers[key] == nil {
+		b.subscribers[key] = make(map[chan Event]struct{})
+	}
+	b.subscribers[key][ch] = struct{}{}
+
+	sub := &Subscription{Events: ch, broker: b, key: key, ch: ch}
+	if lastEventID == "" {
+		return sub
+	}
+
+	b.pruneLocked(key, b.now())
+	history := b.history[key]
+	for i, entry := range history {
+		if entry.event.ID == lastEventID {
+			for _, replay := range history[i+1:] {
+				sub.Replay = append(sub.Replay, replay.event)
+			}
+			return sub
+		}
+	}
+	sub.Missed = true
+
+	return sub
+}
+
+// Publish records e in the history of key and delivers it to its
+// subscribers. Subscribers whose buffer is full are disconnected rather than
+// blocking the publisher.
+func (b *Broker) Publish(key string, e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.pruneLocked(key, now)
+	history := append(b.history[key], historyEntry{event: e, publishedAt: now})
+	if len(history) > b.historySize {
+		history = history[len(history)-b.historySize:]
+	}
+	b.history[key] = history
+	b.sweepLocked(now)
+
+	for ch := range b.subscribers[key] {
+		select {
+		case ch <- e:
+		default:
+			b.removeLocked(key, ch)
+		}
+	}
+}
+
+// pruneLocked drops the events of key that are older than historyAge and
+// forgets key once none are left.
+func (b *Broker) pruneLocked(key string, now time.Time) {
+	history := b.history[key]
+	i := 0
+	for i < len(history) && now.Sub(history[i].publishedAt) > b.historyAge {
+		i++
+	}
+	if i == len(history) {
+		delete(b.history, key)
+		return
+	}
+	b.history[key] = history[i:]
+}
+
+// sweepLocked prunes every key at most once per historyAge, so keys that
+// stop receiving events are forgotten too.
+func (b *Broker) sweepLocked(now time.Time) {
+	if now.Sub(b.lastSweep) < b.historyAge {
+		return
+	}
+	b.lastSweep = now
+	for key := range b.history {
+		b.pruneLocked(key, now)
+	}
+}
+
+func (b *Broker) remove(key string, ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeLocked(key, ch)
+}
+
+func (b *Broker) removeLocked(key string, ch chan Event) {
+	subscribers, ok := b.subscribers[key]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, key)
+	}
+}
@@ -0,0 +1,90 @@
+package sse
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvent_Write(t *testing.T) {
+	var buf bytes.Buffer
+	err := Event{ID: "1", Name: "task-created", Data: []byte("line1\nline2")}.Write(&buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "id: 1\nevent: task-created\ndata: line1\ndata: line2\n\n", buf.String())
+}
+
+func TestBroker_DeliversByKey(t *testing.T) {
+	b := NewBroker(10, time.Minute)
+	alice := b.Subscribe("alice", "")
+	bob := b.Subscribe("bob", "")
+	defer alice.Close()
+	defer bob.Close()
+
+	b.Publish("alice", Event{ID: "1"})
+
+	assert.Equal(t, "1", (<-alice.Events).ID)
+	assert.Empty(t, bob.Events)
+}
+
+func TestBroker_ReplaysAfterLastEventID(t *testing.T) {
+	b := NewBroker(2, time.Minute)
+	for _, id := range []string{"1", "2", "3"} {
+		b.Publish("alice", Event{ID: id})
+	}
+
+	sub := b.Subscribe("alice", "2")
+	assert.False(t, sub.Missed)
+	require.Len(t, sub.Replay, 1)
+	assert.Equal(t, "3", sub.Replay[0].ID)
+	sub.Close()
+
+	sub = b.Subscribe("alice", "1")
+	assert.True(t, sub.Missed)
+	assert.Empty(t, sub.Replay)
+	sub.Close()
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(1, time.Minute)
+	sub := b.Subscribe("alice", "")
+
+	for i := 0; i <= b.bufferSize; i++ {
+		b.Publish("alice", Event{})
+	}
+
+	count := 0
+	for range sub.Events {
+		count++
+	}
+	assert.Equal(t, b.bufferSize, count)
+
+	sub.Close()
+}
+
+func TestBroker_ForgetsExpiredHistory(t *testing.T) {
+	b := NewBroker(10, time.Minute)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.Publish("alice", Event{ID: "1"})
+	b.Publish("bob", Event{ID: "2"})
+
+	now = now.Add(30 * time.Second)
+	sub := b.Subscribe("alice", "1")
+	assert.False(t, sub.Missed)
+	sub.Close()
+
+	now = now.Add(2 * time.Minute)
+	sub = b.Subscribe("alice", "1")
+	assert.True(t, sub.Missed)
+	sub.Close()
+
+	b.Publish("carol", Event{ID: "3"})
+	assert.NotContains(t, b.history, "alice")
+	assert.NotContains(t, b.history, "bob")
+	assert.Contains(t, b.history, "carol")
+}